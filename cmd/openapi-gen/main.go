@@ -0,0 +1,41 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/bradhe/what-day-is-it/pkg/openapi"
+	"github.com/bradhe/what-day-is-it/pkg/server"
+)
+
+// openapi-gen writes the Go client for the server's OpenAPI document. It's run
+// by go generate in pkg/client.
+func main() {
+	var (
+		pkg = flag.String("package", "client", "The package to generate.")
+		out = flag.String("o", "client.gen.go", "The file to write.")
+	)
+
+	flag.Parse()
+
+	doc, err := openapi.Parse([]byte(server.OpenAPISpec))
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	buf, err := openapi.GenerateClient(doc, *pkg)
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(*out, buf, 0644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
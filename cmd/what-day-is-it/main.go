@@ -3,19 +3,30 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/discord"
 	"github.com/bradhe/what-day-is-it/pkg/logs"
+	"github.com/bradhe/what-day-is-it/pkg/messaging"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/mqtt"
 	"github.com/bradhe/what-day-is-it/pkg/server"
+	"github.com/bradhe/what-day-is-it/pkg/slack"
 	"github.com/bradhe/what-day-is-it/pkg/storage"
 	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/bradhe/what-day-is-it/pkg/telegram"
 	"github.com/bradhe/what-day-is-it/pkg/twilio"
+	"github.com/bradhe/what-day-is-it/pkg/webhook"
 )
 
 var logger = logs.WithPackage("main")
 
-func doDeliveryRun(managers managers.Managers, sender *twilio.Sender) {
+func doDeliveryRun(managers managers.Managers, senders server.Senders) {
 	var acc int
 
 	manager := managers.PhoneNumbers()
@@ -36,16 +47,53 @@ func doDeliveryRun(managers managers.Managers, sender *twilio.Sender) {
 		}
 
 		for _, number := range numbers {
-			if !number.IsSendable {
+			body := server.DailyMessage(number)
+
+			if number.IsPauseOver(clock.Clock()) {
+				pausedUntil := number.PausedUntil
+
+				if err := manager.UpdateStatus(&number, models.StatusActive, clock.Clock()); err != nil {
+					logger.WithError(err).Error("failed to resume paused number")
+				} else {
+					server.RecordEvent(managers, number.Number, models.EventResumed, models.ActorSystem, map[string]string{
+						"until": pausedUntil.Format(time.RFC3339),
+					})
+
+					body = server.WelcomeBackMessage(number)
+				}
+			}
+
+			if !number.IsSendable() {
 				logger.Debug("skipping unsendable number")
 
 				// Update this anyway so we don't check it again for a while.
 				manager.UpdateSkipped(&number, clock.Clock())
 			} else {
-				body := fmt.Sprintf("Today is %s", clock.GetDayInZone(clock.MustLoadLocation(number.Timezone)))
 
-				if err := sender.Send(number.Number, body); err != nil {
-					logger.WithError(err).Warn("failed to deliver message via Twilio")
+				if err := senders.Send(number, body); server.IsUndeliverable(err) {
+					logger.WithError(err).Warn("phone number bounced")
+
+					server.RecordEvent(managers, number.Number, models.EventDeliveryFailed, models.ActorSystem, map[string]string{
+						"error":   err.Error(),
+						"bounced": "true",
+					})
+
+					if err := manager.UpdateStatus(&number, models.StatusBounced, clock.Clock()); err != nil {
+						logger.WithError(err).Error("failed to update record as bounced")
+					}
+
+					manager.UpdateSkipped(&number, clock.Clock())
+					continue
+				} else if err != nil {
+					logger.WithError(err).WithField("channel", number.DeliveryChannel()).Warn("failed to deliver message")
+
+					server.RecordEvent(managers, number.Number, models.EventDeliveryFailed, models.ActorSystem, map[string]string{
+						"error": err.Error(),
+					})
+				} else {
+					server.RecordEvent(managers, number.Number, models.EventMessageSent, models.ActorSystem, map[string]string{
+						"body": body,
+					})
 				}
 
 				// We'll finish this for the day.
@@ -58,12 +106,60 @@ func doDeliveryRun(managers managers.Managers, sender *twilio.Sender) {
 	logger.Infof("run completed. delivered %d messages.", acc)
 }
 
-func doDeliveryRunLoop(managers managers.Managers, sender *twilio.Sender) {
+func doDeliveryRunLoop(managers managers.Managers, senders server.Senders) {
 	for range time.Tick(15 * time.Minute) {
-		doDeliveryRun(managers, sender)
+		doDeliveryRun(managers, senders)
 	}
 }
 
+// printEvents writes number's history to stdout, newest first.
+func printEvents(managers managers.Managers, number string, limit int) error {
+	events, err := managers.Events().List(models.CleanSubscriberID(number), limit)
+
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "AT\tTYPE\tACTOR\tMETADATA")
+
+	for _, event := range events {
+		var metadata []string
+
+		for k, v := range event.Metadata {
+			metadata = append(metadata, fmt.Sprintf("%s=%q", k, v))
+		}
+
+		sort.Strings(metadata)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", event.At.UTC().Format(time.RFC3339), event.Type, event.Actor, strings.Join(metadata, " "))
+	}
+
+	return w.Flush()
+}
+
+// startTelegramBot points Telegram at the bot's webhook, or polls for updates
+// if there's nowhere for Telegram to send them.
+func startTelegramBot(bot *telegram.Bot, mode string, srv *server.Server) {
+	if mode == "webhook" {
+		if srv.TelegramSecretToken == "" {
+			logger.Fatal("Telegram webhook mode needs a webhook secret")
+		}
+
+		if err := bot.SetWebhook(srv.BaseURL+"/api/telegram/webhook", srv.TelegramSecretToken); err != nil {
+			logger.WithError(err).Fatal("failed to set Telegram webhook")
+		}
+
+		return
+	}
+
+	// Telegram won't answer getUpdates while there's a webhook.
+	if err := bot.DeleteWebhook(); err != nil {
+		logger.WithError(err).Fatal("failed to delete Telegram webhook")
+	}
+
+	go telegram.NewPoller(bot).Run(nil, srv.HandleTelegramUpdate)
+}
+
 func main() {
 	var (
 		assetBaseDir        = flag.String("asset-base-dir", "pkg/ui/dist", "The directory that assets are built in to.")
@@ -71,8 +167,32 @@ func main() {
 		twilioAccountSid    = flag.String("twilio-account-sid", "", "The account SID to authenticate with.")
 		twilioAuthToken     = flag.String("twilio-auth-token", "", "The Twilio authentication token to authenticate with.")
 		twilioPhoneNumber   = flag.String("twilio-phone-number", "", "The Twilio phone number to use when sending messages.")
+		whatsAppNumber      = flag.String("whatsapp-number", "", "The number that WhatsApp messages come from, if it isn't the Twilio phone number.")
+		whatsAppContentSID  = flag.String("whatsapp-content-sid", "", "Content SID of the approved WhatsApp template for daily messages.")
+		slackSigningSecret  = flag.String("slack-signing-secret", "", "The Slack app's signing secret. Slash commands are off without one.")
+		discordPublicKey    = flag.String("discord-public-key", "", "The Discord app's public key. Interactions are off without one.")
+		telegramToken       = flag.String("telegram-token", "", "The Telegram bot's token. The bot is off without one.")
+		telegramMode        = flag.String("telegram-mode", "poll", "How the Telegram bot gets messages, either poll or webhook.")
+		telegramSecret      = flag.String("telegram-webhook-secret", "", "Secret that Telegram sends with each update in webhook mode.")
+		smtpAddr            = flag.String("smtp-addr", "", "SMTP server to send email through, like smtp.example.com:587. Email is off without one.")
+		smtpUsername        = flag.String("smtp-username", "", "User name for the SMTP server.")
+		smtpPassword        = flag.String("smtp-password", "", "Password for the SMTP server.")
+		smtpFrom            = flag.String("smtp-from", "What Day Is It? <days@what-day-is-today.com>", "Who email is from.")
 		cloudformationStack = flag.String("cloudformation-stack", "what-day-is-it-1", "The stack that we want to store data in.")
 		addr                = flag.String("addr", "localhost:8081", "Address to bind the server to.")
+		adminNumbers        = flag.String("admin-numbers", "", "Comma-separated phone numbers that may run admin SMS commands.")
+		signingSecret       = flag.String("signing-secret", "", "Secret for signing the tokens we give subscribers. Subscribers can't use the API without one.")
+		baseURL             = flag.String("base-url", server.DefaultBaseURL, "Where the site lives, for links in texts.")
+		adminToken          = flag.String("admin-token", "", "Bearer token for the admin API. The admin API is off without one.")
+		eventLimit          = flag.Int("event-limit", server.DefaultEventLimit, "How many events the events command prints.")
+		confirmationWindow  = flag.Duration("confirmation-window", server.DefaultConfirmationWindow, "How long new subscribers have to confirm by SMS.")
+		mqttBroker          = flag.String("mqtt-broker", "", "MQTT broker to publish the day to, like localhost:1883. Publishing is off without one.")
+		mqttClientID        = flag.String("mqtt-client-id", "what-day-is-it", "Client ID to connect to the MQTT broker with. A random suffix is added so that tasks don't knock each other off.")
+		mqttUsername        = flag.String("mqtt-username", "", "User name for the MQTT broker.")
+		mqttPassword        = flag.String("mqtt-password", "", "Password for the MQTT broker.")
+		mqttZones           = flag.String("mqtt-zones", server.DefaultTimeZone, "Comma-separated timezones to publish the day for.")
+		mqttTopicPrefix     = flag.String("mqtt-topic-prefix", mqtt.DefaultTopicPrefix, "Topic that MQTT messages are published under.")
+		mqttDiscoveryPrefix = flag.String("mqtt-discovery-prefix", mqtt.DefaultDiscoveryPrefix, "Home Assistant's MQTT discovery prefix. Discovery is off if it's empty.")
 	)
 
 	flag.Parse()
@@ -84,28 +204,142 @@ func main() {
 	}
 
 	sender := twilio.NewSender(*twilioAccountSid, *twilioAuthToken, *twilioPhoneNumber)
+	sender.WhatsAppNumber = *whatsAppNumber
+	sender.ContentSID = *whatsAppContentSID
 	managers := storage.New(*cloudformationStack)
 
+	srv := server.NewServer(managers, &sender, *development, *assetBaseDir)
+
+	srv.ConfirmationWindow = *confirmationWindow
+	srv.BaseURL = *baseURL
+	srv.AdminToken = *adminToken
+	srv.SigningSecret = []byte(*signingSecret)
+	srv.SlackSigningSecret = []byte(*slackSigningSecret)
+	srv.TwilioAuthToken = *twilioAuthToken
+	srv.Senders[models.ChannelSlack] = slack.NewSender()
+	srv.Senders[models.ChannelDiscord] = discord.NewSender()
+	srv.Senders[models.ChannelWhatsApp] = &sender
+	srv.Senders[models.ChannelVoice] = twilio.NewCaller(sender)
+	sender.SessionOpen = srv.WhatsAppSessionOpen
+
+	hook := webhook.NewSender(srv.WebhookSubscription)
+	hook.Record = srv.RecordWebhookAttempt
+	srv.Senders[models.ChannelWebhook] = hook
+
+	if *discordPublicKey != "" {
+		if key, err := discord.ParsePublicKey(*discordPublicKey); err != nil {
+			logger.WithError(err).Fatal("invalid Discord public key")
+		} else {
+			srv.DiscordPublicKey = key
+		}
+	}
+
+	if *smtpAddr != "" {
+		mailer := messaging.NewSender(messaging.Options{
+			Addr:       *smtpAddr,
+			Username:   *smtpUsername,
+			Password:   *smtpPassword,
+			From:       *smtpFrom,
+			RequireTLS: true,
+		})
+
+		mailer.UnsubscribeURL = srv.UnsubscribeURL
+		srv.Senders[models.ChannelEmail] = mailer
+	}
+
+	var bot *telegram.Bot
+
+	if *telegramToken != "" {
+		if *telegramMode != "poll" && *telegramMode != "webhook" {
+			logger.WithField("mode", *telegramMode).Fatal("Telegram mode has to be poll or webhook")
+		}
+
+		bot = telegram.NewBot(*telegramToken)
+		srv.Senders[models.ChannelTelegram] = bot
+		srv.TelegramSecretToken = *telegramSecret
+	}
+
+	if *adminNumbers != "" {
+		srv.AdminNumbers = strings.Split(*adminNumbers, ",")
+	}
+
+	var publisher *mqtt.Publisher
+
+	if *mqttBroker != "" {
+		var zones []string
+
+		for _, str := range strings.Split(*mqttZones, ",") {
+			if candidates := clock.LookupPlace(str); len(candidates) != 1 {
+				logger.WithField("timezone", str).Fatal("MQTT zones have to be timezones that we know about")
+			} else {
+				zones = append(zones, candidates[0])
+			}
+		}
+
+		publisher = mqtt.NewPublisher(mqtt.Options{
+			Broker:   *mqttBroker,
+			ClientID: mqtt.UniqueClientID(*mqttClientID),
+			Username: *mqttUsername,
+			Password: *mqttPassword,
+		}, zones)
+
+		publisher.TopicPrefix = *mqttTopicPrefix
+		publisher.DiscoveryPrefix = *mqttDiscoveryPrefix
+	}
+
 	switch flag.Arg(0) {
 	case "":
 		logger.Info("starting what-day-is-it in default mode")
 
+		if publisher != nil {
+			go publisher.Run(nil)
+		}
+
+		if bot != nil {
+			startTelegramBot(bot, *telegramMode, srv)
+		}
+
 		// Default behavior is to run this all in a single, long-lived process.
-		go doDeliveryRunLoop(managers, &sender)
+		go doDeliveryRunLoop(managers, srv.Senders)
 
-		if err := server.NewServer(managers, &sender, *development, *assetBaseDir).ListenAndServe(*addr); err != nil {
+		if err := srv.ListenAndServe(*addr); err != nil {
 			panic(err)
 		}
 	case "serve":
 		logger.Info("starting what-day-is-it in HTTP mode")
 
+		if publisher != nil {
+			go publisher.Run(nil)
+		}
+
+		if bot != nil {
+			startTelegramBot(bot, *telegramMode, srv)
+		}
+
 		// Only serve the HTTP traffic if requested.
-		if err := server.NewServer(managers, &sender, *development, *assetBaseDir).ListenAndServe(*addr); err != nil {
+		if err := srv.ListenAndServe(*addr); err != nil {
 			panic(err)
 		}
 	case "deliver":
 		logger.Info("starting what-day-is-it in delivery mode")
 
-		doDeliveryRun(managers, &sender)
+		doDeliveryRun(managers, srv.Senders)
+
+		// Webhooks that didn't work the first time are still being retried.
+		hook.Wait()
+	case "events":
+		if flag.NArg() < 2 {
+			logger.Fatal("usage: what-day-is-it events <number>")
+		}
+
+		if err := printEvents(managers, flag.Arg(1), *eventLimit); err != nil {
+			logger.WithError(err).Fatal("failed to list events")
+		}
+	case "migrate":
+		logger.Info("starting what-day-is-it in migration mode")
+
+		if err := storage.Migrate(*cloudformationStack); err != nil {
+			logger.WithError(err).Fatal("migration failed")
+		}
 	}
 }
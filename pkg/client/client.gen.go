@@ -0,0 +1,556 @@
+// Code generated by openapi-gen. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type APIError struct {
+	// What went wrong, for machines.
+	Code string `json:"code"`
+
+	// What went wrong, for people.
+	Message string `json:"message"`
+}
+
+// DeliveryAttempt is one try at posting to a webhook subscription.
+type DeliveryAttempt struct {
+	// The same for every attempt at a delivery, and in the X-Webhook-Id header.
+	DeliveryID string `json:"delivery_id"`
+
+	// Which try this was, starting at 1.
+	Attempt int `json:"attempt"`
+
+	At time.Time `json:"at"`
+
+	Succeeded bool `json:"succeeded"`
+
+	// What the receiver responded with. Missing if it never did.
+	StatusCode *int `json:"status_code,omitempty"`
+
+	DurationMs int `json:"duration_ms"`
+
+	// What went wrong, if anything.
+	Error *string `json:"error,omitempty"`
+}
+
+type DiscordInteraction struct {
+	// 1 for a ping, 2 for a command.
+	Type int `json:"type"`
+
+	Data *DiscordInteractionData `json:"data,omitempty"`
+}
+
+type DiscordInteractionCallback struct {
+	Content string `json:"content"`
+
+	// 64 if only the person that asked sees the answer.
+	Flags *int `json:"flags,omitempty"`
+}
+
+type DiscordInteractionData struct {
+	// The command, like whatday.
+	Name string `json:"name"`
+
+	Options []DiscordInteractionOption `json:"options,omitempty"`
+}
+
+type DiscordInteractionOption struct {
+	Name string `json:"name"`
+
+	// What was given for the option, like Tokyo for timezone.
+	Value interface{} `json:"value"`
+}
+
+type DiscordInteractionResponse struct {
+	// 1 for a pong, 4 for a message.
+	Type int `json:"type"`
+
+	Data *DiscordInteractionCallback `json:"data,omitempty"`
+}
+
+// ErrorResponse is the body of every unsuccessful v1 response.
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+type EventResponse struct {
+	Type string `json:"type"`
+
+	At time.Time `json:"at"`
+
+	// Who did it: subscriber, web, system or admin:<number>.
+	Actor string `json:"actor"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type GetDeliveriesResponse struct {
+	// Newest first.
+	Deliveries []DeliveryAttempt `json:"deliveries"`
+}
+
+type GetEventsResponse struct {
+	Number *string `json:"number,omitempty"`
+
+	// Newest first.
+	Events []EventResponse `json:"events"`
+
+	Error *string `json:"error,omitempty"`
+}
+
+type GetHealthResponse struct {
+	OK bool `json:"ok"`
+}
+
+type GetTimezonesResponse struct {
+	Timezones []TimezoneResponse `json:"timezones"`
+}
+
+// PatchSubscriptionRequest is a change to a subscription's settings. Anything that's left out stays the same.
+type PatchSubscriptionRequest struct {
+	// A timezone name, city or abbreviation.
+	Timezone *string `json:"timezone,omitempty"`
+
+	// Local time of day to send at, like 07:30.
+	DeliveryTime *string `json:"delivery_time,omitempty"`
+
+	// Days of the week to send on, like monday or mon.
+	Days []string `json:"days,omitempty"`
+
+	// Pauses or resumes deliveries.
+	Paused *bool `json:"paused,omitempty"`
+
+	// Pauses deliveries until this date, like 2026-11-01.
+	PausedUntil *string `json:"paused_until,omitempty"`
+}
+
+type PauseResponse struct {
+	Number *string `json:"number,omitempty"`
+
+	Status *string `json:"status,omitempty"`
+
+	// When deliveries start again, if the pause ends on its own.
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+
+	Error *string `json:"error,omitempty"`
+}
+
+// PostPauseRequest is a pause. With neither for nor until it lasts until it's resumed.
+type PostPauseRequest struct {
+	// How long to pause for, like 7d or 2w.
+	For *string `json:"for,omitempty"`
+
+	// The date to pick back up on, like 2026-11-01. Takes precedence over for.
+	Until *string `json:"until,omitempty"`
+}
+
+type PostSubscribeRequest struct {
+	// The phone number to subscribe.
+	Number string `json:"number"`
+
+	// The timezone that the user selected.
+	Timezone *string `json:"timezone,omitempty"`
+
+	// How the owner of the number will confirm: by replying to a text, the default, or by sending us the code we text them.
+	Verification *string `json:"verification,omitempty"`
+}
+
+type PostSubscribeResponse struct {
+	Number *string `json:"number,omitempty"`
+
+	Timezone *string `json:"timezone,omitempty"`
+
+	// Indicates that we picked the timezone because the request didn't include a usable one.
+	TimezoneGuessed *bool `json:"timezone_guessed,omitempty"`
+
+	Error *string `json:"error,omitempty"`
+
+	Subscribed bool `json:"subscribed"`
+
+	Status *string `json:"status,omitempty"`
+
+	// Indicates that the number has to confirm by SMS before it's subscribed.
+	ConfirmationRequired *bool `json:"confirmation_required,omitempty"`
+
+	// Indicates that the code we texted to the number has to be sent to subscribeVerify.
+	VerificationRequired *bool `json:"verification_required,omitempty"`
+
+	// Lets the owner of the number manage their subscription through the API.
+	ManageToken *string `json:"manage_token,omitempty"`
+}
+
+type PostSubscribeVerifyRequest struct {
+	// The phone number that the code was sent to.
+	Number string `json:"number"`
+
+	// The code that we texted to the number.
+	Code string `json:"code"`
+}
+
+type PostSubscriptionRequest struct {
+	// The phone number to text or call, for the sms and voice channels.
+	Number string `json:"number,omitempty"`
+
+	// How to deliver the message.
+	Channel *string `json:"channel,omitempty"`
+
+	// The address to email, for the email channel.
+	Email *string `json:"email,omitempty"`
+
+	// The webhook to post to, for the slack, discord and webhook channels.
+	WebhookURL *string `json:"webhook_url,omitempty"`
+
+	// A timezone name, city or abbreviation. We'll guess if it's missing.
+	Timezone *string `json:"timezone,omitempty"`
+
+	// Local time of day to send at, like 07:30. The default is 08:00.
+	DeliveryTime *string `json:"delivery_time,omitempty"`
+
+	// How the owner of the number will confirm: by replying to a text, the default, or by sending us the code we text them. Email addresses and webhooks always get a code.
+	Verification *string `json:"verification,omitempty"`
+}
+
+type PostSubscriptionResponse struct {
+	Subscription Subscription `json:"subscription"`
+
+	// Indicates that the number has to reply YES to the text we sent it.
+	ConfirmationRequired bool `json:"confirmation_required"`
+
+	// Indicates that the code we texted has to be sent to verifySubscription.
+	VerificationRequired bool `json:"verification_required"`
+}
+
+type PostSubscriptionVerifyRequest struct {
+	// The code that we texted to the number.
+	Code string `json:"code"`
+}
+
+type PostSubscriptionVerifyResponse struct {
+	Subscription Subscription `json:"subscription"`
+
+	// Authorizes requests to manage the subscription. Missing if the server can't sign tokens.
+	ManageToken *string `json:"manage_token,omitempty"`
+
+	// Signs everything we post to a webhook subscription. Only webhook subscriptions have one, and it's only handed out here.
+	WebhookSecret *string `json:"webhook_secret,omitempty"`
+}
+
+type SlackMessage struct {
+	// Whether everybody in the channel sees the answer or just the person that asked.
+	ResponseType *string `json:"response_type,omitempty"`
+
+	Text string `json:"text"`
+}
+
+type Subscription struct {
+	// The phone number, or an ID like slack:0123456789abcdef for other channels.
+	Number string `json:"number"`
+
+	Channel string `json:"channel"`
+
+	Status string `json:"status"`
+
+	Timezone string `json:"timezone"`
+
+	// Indicates that we picked the timezone because nobody told us one.
+	TimezoneGuessed bool `json:"timezone_guessed"`
+
+	// Local time of day that the message goes out, like 08:00.
+	DeliveryTime string `json:"delivery_time"`
+
+	// The days of the week that the message goes out, like monday.
+	Days []string `json:"days"`
+
+	// When deliveries start again, for paused subscriptions that will resume on their own.
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+
+	NextDeliveryAt *time.Time `json:"next_delivery_at,omitempty"`
+}
+
+type TelegramChat struct {
+	ID int `json:"id"`
+
+	// One of private, group, supergroup or channel.
+	Type string `json:"type"`
+}
+
+type TelegramMessage struct {
+	MessageID int `json:"message_id"`
+
+	Chat TelegramChat `json:"chat"`
+
+	// What was sent, like /tz Tokyo.
+	Text *string `json:"text,omitempty"`
+}
+
+type TelegramUpdate struct {
+	UpdateID int `json:"update_id"`
+
+	Message *TelegramMessage `json:"message,omitempty"`
+}
+
+type TimezoneResponse struct {
+	// The canonical IANA name, like Europe/Berlin.
+	Name string `json:"name"`
+
+	// A name to show people, like Berlin (UTC+02:00).
+	DisplayName string `json:"display_name"`
+
+	// The offset from UTC right now, like +02:00.
+	UtcOffset string `json:"utc_offset"`
+
+	// Indicates that daylight saving time is in effect right now.
+	Dst bool `json:"dst"`
+
+	Cities []string `json:"cities"`
+
+	Abbreviations []string `json:"abbreviations"`
+
+	// Old names for the zone, like US/Pacific.
+	Aliases []string `json:"aliases"`
+}
+
+type TodayResponse struct {
+	// The timezone name that the day is for.
+	Timezone string `json:"timezone"`
+
+	// The name of the day, like Monday.
+	Day string `json:"day"`
+
+	// The ISO 8601 date, like 2026-10-19.
+	Date string `json:"date"`
+
+	// The ISO 8601 week, like 2026-W43.
+	IsoWeek string `json:"iso_week"`
+
+	DayOfYear int `json:"day_of_year"`
+
+	DaysLeftInYear int `json:"days_left_in_year"`
+
+	// The offset from UTC right now, like +09:00.
+	UtcOffset string `json:"utc_offset"`
+
+	// When this response stops being true, which is when the day ends or, if the clocks change first, when they do.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ListEventsParams are the optional query parameters for ListEvents.
+type ListEventsParams struct {
+	// How many events to return. Defaults to 50, up to 1000.
+	Limit *int
+}
+
+// ListEvents returns what's happened to a phone number, newest first.
+func (c *Client) ListEvents(ctx context.Context, number string, params *ListEventsParams) (*GetEventsResponse, error) {
+	query := url.Values{}
+
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", strconv.Itoa(*params.Limit))
+		}
+	}
+
+	var out GetEventsResponse
+
+	if err := c.do(ctx, "GET", "/api/admin/phone-numbers/"+url.PathEscape(number)+"/events", query, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// PauseSubscription stops texts to a phone number for a while.
+func (c *Client) PauseSubscription(ctx context.Context, number string, in *PostPauseRequest) (*PauseResponse, error) {
+	var out PauseResponse
+
+	if err := c.do(ctx, "POST", "/api/admin/phone-numbers/"+url.PathEscape(number)+"/pause", nil, in, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ResumeSubscription starts texts to a paused phone number again.
+func (c *Client) ResumeSubscription(ctx context.Context, number string) (*PauseResponse, error) {
+	var out PauseResponse
+
+	if err := c.do(ctx, "POST", "/api/admin/phone-numbers/"+url.PathEscape(number)+"/resume", nil, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetHealth checks that the server is up.
+func (c *Client) GetHealth(ctx context.Context) (*GetHealthResponse, error) {
+	var out GetHealthResponse
+
+	if err := c.do(ctx, "GET", "/api/health", nil, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// Subscribe subscribes a phone number.
+//
+// Deprecated: see the API documentation.
+func (c *Client) Subscribe(ctx context.Context, in *PostSubscribeRequest) (*PostSubscribeResponse, error) {
+	var out PostSubscribeResponse
+
+	if err := c.do(ctx, "POST", "/api/subscribe", nil, in, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// SubscribeVerify confirms a subscription with the code that we texted.
+//
+// Deprecated: see the API documentation.
+func (c *Client) SubscribeVerify(ctx context.Context, in *PostSubscribeVerifyRequest) (*PostSubscribeResponse, error) {
+	var out PostSubscribeResponse
+
+	if err := c.do(ctx, "POST", "/api/subscribe/verify", nil, in, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListTimezonesParams are the optional query parameters for ListTimezones.
+type ListTimezonesParams struct {
+	// Only returns timezones that might be what this means, best matches first. Partial and misspelled names are fine, like berl.
+	Q *string
+}
+
+// ListTimezones lists the timezones that subscriptions can use.
+func (c *Client) ListTimezones(ctx context.Context, params *ListTimezonesParams) (*GetTimezonesResponse, error) {
+	query := url.Values{}
+
+	if params != nil {
+		if params.Q != nil {
+			query.Set("q", *params.Q)
+		}
+	}
+
+	var out GetTimezonesResponse
+
+	if err := c.do(ctx, "GET", "/api/timezones", query, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetTodayParams are the optional query parameters for GetToday.
+type GetTodayParams struct {
+	// A timezone name, city or abbreviation. Defaults to the server's timezone.
+	Tz *string
+}
+
+// GetToday returns what day it is.
+func (c *Client) GetToday(ctx context.Context, params *GetTodayParams) (*TodayResponse, error) {
+	query := url.Values{}
+
+	if params != nil {
+		if params.Tz != nil {
+			query.Set("tz", *params.Tz)
+		}
+	}
+
+	var out TodayResponse
+
+	if err := c.do(ctx, "GET", "/api/today", query, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// CreateSubscription subscribes a phone number, email address, Slack or Discord channel, or webhook.
+func (c *Client) CreateSubscription(ctx context.Context, in *PostSubscriptionRequest) (*PostSubscriptionResponse, error) {
+	var out PostSubscriptionResponse
+
+	if err := c.do(ctx, "POST", "/api/v1/subscriptions", nil, in, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetSubscription returns a subscription.
+func (c *Client) GetSubscription(ctx context.Context, number string) (*Subscription, error) {
+	var out Subscription
+
+	if err := c.do(ctx, "GET", "/api/v1/subscriptions/"+url.PathEscape(number), nil, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// UpdateSubscription changes a subscription's settings.
+func (c *Client) UpdateSubscription(ctx context.Context, number string, in *PatchSubscriptionRequest) (*Subscription, error) {
+	var out Subscription
+
+	if err := c.do(ctx, "PATCH", "/api/v1/subscriptions/"+url.PathEscape(number), nil, in, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// DeleteSubscription unsubscribes a phone number.
+func (c *Client) DeleteSubscription(ctx context.Context, number string) (*Subscription, error) {
+	var out Subscription
+
+	if err := c.do(ctx, "DELETE", "/api/v1/subscriptions/"+url.PathEscape(number), nil, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// ListDeliveriesParams are the optional query parameters for ListDeliveries.
+type ListDeliveriesParams struct {
+	// How many attempts to return. Defaults to 50, up to 1000.
+	Limit *int
+}
+
+// ListDeliveries returns the most recent attempts at posting to a webhook subscription, newest first.
+func (c *Client) ListDeliveries(ctx context.Context, number string, params *ListDeliveriesParams) (*GetDeliveriesResponse, error) {
+	query := url.Values{}
+
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", strconv.Itoa(*params.Limit))
+		}
+	}
+
+	var out GetDeliveriesResponse
+
+	if err := c.do(ctx, "GET", "/api/v1/subscriptions/"+url.PathEscape(number)+"/deliveries", query, nil, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// VerifySubscription confirms a subscription with the code that we texted.
+func (c *Client) VerifySubscription(ctx context.Context, number string, in *PostSubscriptionVerifyRequest) (*PostSubscriptionVerifyResponse, error) {
+	var out PostSubscriptionVerifyResponse
+
+	if err := c.do(ctx, "POST", "/api/v1/subscriptions/"+url.PathEscape(number)+"/verify", nil, in, &out); err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
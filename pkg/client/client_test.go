@@ -0,0 +1,133 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/openapi"
+	"github.com/bradhe/what-day-is-it/pkg/server"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratedClientIsUpToDate(t *testing.T) {
+	doc, err := openapi.Parse([]byte(server.OpenAPISpec))
+	assert.NoError(t, err)
+
+	want, err := openapi.GenerateClient(doc, "client")
+	assert.NoError(t, err)
+
+	got, err := ioutil.ReadFile("client.gen.go")
+	assert.NoError(t, err)
+
+	if !bytes.Equal(want, bytes.Replace(got, []byte("\r\n"), []byte("\n"), -1)) {
+		t.Error("client.gen.go is out of date. Run go generate ./pkg/client.")
+	}
+}
+
+type lastMessageSender struct {
+	sync.Mutex
+	body string
+}
+
+func (s *lastMessageSender) Send(to, body string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.body = body
+	return nil
+}
+
+var codeexp = regexp.MustCompile(`[0-9]{6}`)
+
+func TestClient(t *testing.T) {
+	sender := &lastMessageSender{}
+
+	srv := server.NewServer(memory.New(), sender, false, "")
+	srv.SigningSecret = []byte("sekrit")
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+	c := New(ts.URL + "/")
+
+	health, err := c.GetHealth(ctx)
+	assert.NoError(t, err)
+	assert.True(t, health.OK)
+
+	today, err := c.GetToday(ctx, &GetTodayParams{Tz: String("Tokyo")})
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", today.Timezone)
+
+	created, err := c.CreateSubscription(ctx, &PostSubscriptionRequest{
+		Number:       "(415) 555-1234",
+		Timezone:     String("Tokyo"),
+		Verification: String("code"),
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, created.VerificationRequired)
+	assert.Equal(t, "pending", created.Subscription.Status)
+
+	_, err = c.VerifySubscription(ctx, "+14155551234", &PostSubscriptionVerifyRequest{Code: "nope"})
+	assert.Equal(t, &Error{StatusCode: 422, Code: "wrong_code", Message: "That code isn't right."}, err)
+
+	verified, err := c.VerifySubscription(ctx, "+14155551234", &PostSubscriptionVerifyRequest{Code: codeexp.FindString(sender.body)})
+	assert.NoError(t, err)
+	assert.NotNil(t, verified.ManageToken)
+
+	_, err = c.GetSubscription(ctx, "+14155551234")
+	assert.Equal(t, "unauthorized", err.(*Error).Code)
+
+	c.Token = *verified.ManageToken
+
+	sub, err := c.UpdateSubscription(ctx, "+14155551234", &PatchSubscriptionRequest{
+		DeliveryTime: String("07:15"),
+		Days:         []string{"sat", "sun"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", sub.Timezone)
+	assert.Equal(t, "07:15", sub.DeliveryTime)
+	assert.Equal(t, []string{"sunday", "saturday"}, sub.Days)
+	assert.NotNil(t, sub.NextDeliveryAt)
+
+	sub, err = c.DeleteSubscription(ctx, "+14155551234")
+	assert.NoError(t, err)
+	assert.Equal(t, "stopped", sub.Status)
+
+	// Admin endpoints don't say much when they fail.
+	_, err = c.ListEvents(ctx, "+14155551234", &ListEventsParams{Limit: Int(5)})
+	assert.Equal(t, &Error{StatusCode: 401}, err)
+
+	// The old endpoints have plain messages.
+	_, err = c.Subscribe(ctx, &PostSubscribeRequest{Number: "123"})
+	assert.Equal(t, &Error{StatusCode: 412, Message: "Invalid phone number."}, err)
+}
+
+func TestListEvents(t *testing.T) {
+	srv := server.NewServer(memory.New(), &lastMessageSender{}, false, "")
+	srv.AdminToken = "admin"
+
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	ctx := context.Background()
+
+	c := New(ts.URL)
+	c.Token = "admin"
+
+	_, err := c.Subscribe(ctx, &PostSubscribeRequest{Number: "+14155551234"})
+	assert.NoError(t, err)
+
+	resp, err := c.ListEvents(ctx, "+14155551234", &ListEventsParams{Limit: Int(1)})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Events, 1)
+	assert.Equal(t, "subscribed", resp.Events[0].Type)
+}
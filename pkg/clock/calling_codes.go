@@ -0,0 +1,46 @@
+package clock
+
+import "strings"
+
+var areaCodeIndex = buildAreaCodeIndex(areaCodes)
+
+func buildAreaCodeIndex(areaCodes map[string][]string) map[string]string {
+	index := make(map[string]string)
+
+	for zone, codes := range areaCodes {
+		for _, code := range codes {
+			index[code] = zone
+		}
+	}
+
+	return index
+}
+
+// GuessTimezone guesses the zone that a cleaned up phone number (e.g.
+// +15554443333) lives in based on its calling code and, in North America,
+// its area code.
+func GuessTimezone(number string) (string, bool) {
+	if !strings.HasPrefix(number, "+") {
+		return "", false
+	}
+
+	digits := number[1:]
+
+	if strings.HasPrefix(digits, "1") {
+		if len(digits) < 4 {
+			return "", false
+		}
+
+		zone, ok := areaCodeIndex[digits[1:4]]
+		return zone, ok
+	}
+
+	// Calling codes are prefix-free so the first match is the only match.
+	for i := 1; i <= 3 && i <= len(digits); i++ {
+		if zone, ok := callingCodes[digits[:i]]; ok {
+			return zone, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,180 @@
+package clock
+
+// callingCodes maps international calling codes to the zone that most of the
+// people using them live in. North America (+1) is handled by areaCodes.
+var callingCodes = map[string]string{
+	"7":   "Europe/Moscow",
+	"20":  "Africa/Cairo",
+	"27":  "Africa/Johannesburg",
+	"30":  "Europe/Athens",
+	"31":  "Europe/Amsterdam",
+	"32":  "Europe/Brussels",
+	"33":  "Europe/Paris",
+	"34":  "Europe/Madrid",
+	"36":  "Europe/Budapest",
+	"39":  "Europe/Rome",
+	"40":  "Europe/Bucharest",
+	"41":  "Europe/Zurich",
+	"43":  "Europe/Vienna",
+	"44":  "Europe/London",
+	"45":  "Europe/Copenhagen",
+	"46":  "Europe/Stockholm",
+	"47":  "Europe/Oslo",
+	"48":  "Europe/Warsaw",
+	"49":  "Europe/Berlin",
+	"51":  "America/Lima",
+	"52":  "America/Mexico_City",
+	"53":  "America/Havana",
+	"54":  "America/Argentina/Buenos_Aires",
+	"55":  "America/Sao_Paulo",
+	"56":  "America/Santiago",
+	"57":  "America/Bogota",
+	"58":  "America/Caracas",
+	"60":  "Asia/Kuala_Lumpur",
+	"61":  "Australia/Sydney",
+	"62":  "Asia/Jakarta",
+	"63":  "Asia/Manila",
+	"64":  "Pacific/Auckland",
+	"65":  "Asia/Singapore",
+	"66":  "Asia/Bangkok",
+	"81":  "Asia/Tokyo",
+	"82":  "Asia/Seoul",
+	"84":  "Asia/Ho_Chi_Minh",
+	"86":  "Asia/Shanghai",
+	"90":  "Europe/Istanbul",
+	"91":  "Asia/Kolkata",
+	"92":  "Asia/Karachi",
+	"93":  "Asia/Kabul",
+	"94":  "Asia/Colombo",
+	"95":  "Asia/Yangon",
+	"98":  "Asia/Tehran",
+	"212": "Africa/Casablanca",
+	"213": "Africa/Algiers",
+	"216": "Africa/Tunis",
+	"218": "Africa/Tripoli",
+	"220": "Africa/Banjul",
+	"221": "Africa/Dakar",
+	"225": "Africa/Abidjan",
+	"233": "Africa/Accra",
+	"234": "Africa/Lagos",
+	"251": "Africa/Addis_Ababa",
+	"254": "Africa/Nairobi",
+	"255": "Africa/Dar_es_Salaam",
+	"256": "Africa/Kampala",
+	"260": "Africa/Lusaka",
+	"263": "Africa/Harare",
+	"351": "Europe/Lisbon",
+	"352": "Europe/Luxembourg",
+	"353": "Europe/Dublin",
+	"354": "Atlantic/Reykjavik",
+	"356": "Europe/Malta",
+	"357": "Asia/Nicosia",
+	"358": "Europe/Helsinki",
+	"359": "Europe/Sofia",
+	"370": "Europe/Vilnius",
+	"371": "Europe/Riga",
+	"372": "Europe/Tallinn",
+	"380": "Europe/Kiev",
+	"381": "Europe/Belgrade",
+	"385": "Europe/Zagreb",
+	"386": "Europe/Ljubljana",
+	"420": "Europe/Prague",
+	"421": "Europe/Bratislava",
+	"502": "America/Guatemala",
+	"503": "America/El_Salvador",
+	"504": "America/Tegucigalpa",
+	"505": "America/Managua",
+	"506": "America/Costa_Rica",
+	"507": "America/Panama",
+	"593": "America/Guayaquil",
+	"598": "America/Montevideo",
+	"852": "Asia/Hong_Kong",
+	"853": "Asia/Macau",
+	"855": "Asia/Phnom_Penh",
+	"880": "Asia/Dhaka",
+	"886": "Asia/Taipei",
+	"960": "Indian/Maldives",
+	"961": "Asia/Beirut",
+	"962": "Asia/Amman",
+	"963": "Asia/Damascus",
+	"964": "Asia/Baghdad",
+	"965": "Asia/Kuwait",
+	"966": "Asia/Riyadh",
+	"968": "Asia/Muscat",
+	"971": "Asia/Dubai",
+	"972": "Asia/Jerusalem",
+	"973": "Asia/Bahrain",
+	"974": "Asia/Qatar",
+	"977": "Asia/Kathmandu",
+}
+
+// areaCodes lists North American area codes by zone. Area codes that straddle
+// zone boundaries are filed under whichever side most people live on, and a
+// few that are split too evenly to call are left out entirely.
+var areaCodes = map[string][]string{
+	"America/New_York": {
+		"201", "202", "203", "207", "212", "215", "216", "220", "223", "229", "234", "239", "240",
+		"252", "267", "272", "276", "283", "301", "302", "304", "305", "315", "321", "326", "330", "332",
+		"336", "339", "347", "351", "352", "380", "386", "401", "404", "407", "410", "412", "413", "419",
+		"423", "434", "440", "443", "445", "448", "470", "475", "478", "484", "502", "508", "513", "516",
+		"518", "540", "551", "561", "567", "570", "571", "582", "585", "603", "606", "607", "609",
+		"610", "614", "617", "631", "640", "646", "656", "667", "678", "680", "681", "689", "703", "704",
+		"706", "716", "717", "718", "724", "727", "732", "740", "743", "754", "757", "762", "770", "772",
+		"774", "781", "786", "802", "803", "804", "813", "814", "826", "835", "838", "839", "843", "845",
+		"848", "854", "856", "857", "859", "860", "862", "863", "864", "865", "878", "904", "908", "910",
+		"912", "914", "917", "919", "929", "934", "937", "941", "943", "948", "954", "959", "973", "978",
+		"980", "984",
+	},
+	"America/Detroit": {
+		"231", "248", "269", "313", "517", "586", "616", "679", "734", "810", "906", "947", "989",
+	},
+	"America/Indiana/Indianapolis": {
+		"260", "317", "463", "574", "765", "812", "930",
+	},
+	"America/Chicago": {
+		"205", "210", "214", "217", "218", "219", "224", "225", "228", "251", "254", "256", "262", "270",
+		"274", "281", "308", "309", "312", "314", "316", "318", "319", "320", "325", "331", "334", "337",
+		"346", "361", "364", "402", "405", "409", "414", "417", "430", "432", "447", "464", "469", "479",
+		"501", "504", "507", "512", "515", "531", "534", "539", "557", "563", "572", "573", "580", "601",
+		"605", "608", "612", "615", "618", "620", "629", "630", "636", "641", "651", "659", "660", "662",
+		"682", "701", "708", "712", "713", "715", "726", "731", "737", "763", "769", "773", "779", "785",
+		"806", "815", "816", "817", "830", "832", "847", "870", "872", "901", "903", "913", "918", "920",
+		"931", "936", "938", "940", "945", "952", "956", "972", "975", "979", "985",
+	},
+	"America/Denver": {
+		"303", "307", "385", "406", "435", "505", "575", "719", "720", "801", "915", "970", "983",
+	},
+	"America/Boise": {
+		"208", "986",
+	},
+	"America/Phoenix": {
+		"480", "520", "602", "623", "928",
+	},
+	"America/Los_Angeles": {
+		"206", "209", "213", "253", "279", "310", "323", "341", "350", "360", "408", "415", "424", "425",
+		"442", "458", "503", "509", "510", "530", "541", "559", "562", "564", "619", "626", "628", "650",
+		"657", "661", "669", "702", "707", "714", "725", "747", "760", "775", "805", "818", "820", "831",
+		"840", "858", "909", "916", "925", "949", "951", "971",
+	},
+	"America/Anchorage":     {"907"},
+	"Pacific/Honolulu":      {"808"},
+	"America/Toronto":       {"226", "249", "263", "289", "343", "354", "365", "367", "382", "416", "418", "437", "438", "450", "468", "514", "519", "548", "579", "581", "613", "647", "683", "705", "742", "753", "807", "819", "873", "905"},
+	"America/Winnipeg":      {"204", "431", "584"},
+	"America/Regina":        {"306", "474", "639"},
+	"America/Edmonton":      {"368", "403", "587", "780", "825"},
+	"America/Vancouver":     {"236", "250", "257", "604", "672", "778"},
+	"America/Halifax":       {"428", "506", "782", "902"},
+	"America/St_Johns":      {"709", "879"},
+	"America/Puerto_Rico":   {"787", "939"},
+	"America/St_Thomas":     {"340"},
+	"America/Santo_Domingo": {"809", "829", "849"},
+	"America/Jamaica":       {"658", "876"},
+	"America/Nassau":        {"242"},
+	"America/Barbados":      {"246"},
+	"America/Cayman":        {"345"},
+	"America/Port_of_Spain": {"868"},
+	"Atlantic/Bermuda":      {"441"},
+	"Pacific/Guam":          {"671"},
+	"Pacific/Saipan":        {"670"},
+	"Pacific/Pago_Pago":     {"684"},
+}
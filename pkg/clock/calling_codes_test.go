@@ -0,0 +1,54 @@
+package clock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuessTimezone(t *testing.T) {
+	tests := []struct {
+		number   string
+		expected string
+		ok       bool
+	}{
+		{"+14155551234", "America/Los_Angeles", true},
+		{"+12125551234", "America/New_York", true},
+		{"+13125551234", "America/Chicago", true},
+		{"+13035551234", "America/Denver", true},
+		{"+16025551234", "America/Phoenix", true},
+		{"+14165551234", "America/Toronto", true},
+		{"+18085551234", "Pacific/Honolulu", true},
+		{"+17875551234", "America/Puerto_Rico", true},
+		{"+15555551234", "", false},
+		{"+447700900123", "Europe/London", true},
+		{"+4915112345678", "Europe/Berlin", true},
+		{"+353851234567", "Europe/Dublin", true},
+		{"+819012345678", "Asia/Tokyo", true},
+		{"+79161234567", "Europe/Moscow", true},
+		{"+8801712345678", "Asia/Dhaka", true},
+		{"+999123456789", "", false},
+		{"5554443333", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		zone, ok := GuessTimezone(test.number)
+		assert.Equal(t, test.ok, ok, "guessing %q", test.number)
+		assert.Equal(t, test.expected, zone, "guessing %q", test.number)
+	}
+}
+
+func TestCallingCodeZonesAreLoadable(t *testing.T) {
+	for _, zone := range callingCodes {
+		assert.NotPanics(t, func() {
+			MustLoadLocation(zone)
+		}, zone)
+	}
+
+	for zone := range areaCodes {
+		assert.NotPanics(t, func() {
+			MustLoadLocation(zone)
+		}, zone)
+	}
+}
@@ -0,0 +1,27 @@
+package clock
+
+import "time"
+
+// NextDeadline returns the first scheduled time, local to loc, on a day after
+// sentAt.
+func NextDeadline(sentAt *time.Time, loc *time.Location, schedule Schedule) *time.Time {
+	return schedule.firstFrom(sentAt.In(loc), 1)
+}
+
+// RescheduleDeadline returns the deadline for someone who last got a message
+// at lastSentAt, as of now in loc. That's today's deadline--even if it has
+// already passed--unless they've already gotten a message today or don't get
+// one on today at all.
+func RescheduleDeadline(lastSentAt, now *time.Time, loc *time.Location, schedule Schedule) *time.Time {
+	today := now.In(loc)
+
+	if lastSentAt != nil {
+		sent := lastSentAt.In(loc)
+
+		if sent.Year() == today.Year() && sent.YearDay() == today.YearDay() {
+			return NextDeadline(now, loc, schedule)
+		}
+	}
+
+	return schedule.firstFrom(today, 0)
+}
@@ -0,0 +1,38 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextDeadline(t *testing.T) {
+	loc := MustLoadLocation("America/Los_Angeles")
+
+	deadline := NextDeadline(mustParseTime("2020-04-20T15:00:00Z"), loc, DefaultSchedule)
+	assert.Equal(t, "2020-04-21T08:00:00-07:00", deadline.Format(time.RFC3339))
+
+	// Monday afternoon, but only on weekends at 9:30.
+	weekends := Schedule{Minute: 9*60 + 30, Days: NewWeekdays(time.Saturday, time.Sunday)}
+	deadline = NextDeadline(mustParseTime("2020-04-20T22:00:00Z"), loc, weekends)
+	assert.Equal(t, "2020-04-25T09:30:00-07:00", deadline.Format(time.RFC3339))
+}
+
+func TestRescheduleDeadline(t *testing.T) {
+	loc := MustLoadLocation("Asia/Tokyo")
+	now := mustParseTime("2020-04-20T03:00:00Z") // noon on Monday in Tokyo
+
+	// Never got a message, so today's deadline is already due.
+	assert.Equal(t, "2020-04-20T08:00:00+09:00", RescheduleDeadline(nil, now, loc, DefaultSchedule).Format(time.RFC3339))
+
+	// Got yesterday's message in Tokyo, so today's deadline is still due.
+	assert.Equal(t, "2020-04-20T08:00:00+09:00", RescheduleDeadline(mustParseTime("2020-04-19T14:00:00Z"), now, loc, DefaultSchedule).Format(time.RFC3339))
+
+	// Already got a message today in Tokyo, so wait for tomorrow.
+	assert.Equal(t, "2020-04-21T08:00:00+09:00", RescheduleDeadline(mustParseTime("2020-04-19T23:30:00Z"), now, loc, DefaultSchedule).Format(time.RFC3339))
+
+	// Doesn't get one on Mondays.
+	tuesdays := Schedule{Minute: 18 * 60, Days: NewWeekdays(time.Tuesday)}
+	assert.Equal(t, "2020-04-21T18:00:00+09:00", RescheduleDeadline(nil, now, loc, tuesdays).Format(time.RFC3339))
+}
@@ -0,0 +1,233 @@
+package clock
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Zone describes an IANA time zone and the names people use to refer to it.
+type Zone struct {
+	// The canonical IANA name, e.g. "Europe/Berlin".
+	Name string
+
+	// Representative cities, regions and single-zone countries.
+	Cities []string
+
+	// Common abbreviations, e.g. "CET".
+	Abbreviations []string
+
+	// Old IANA names that link to this zone, e.g. "US/Pacific".
+	Aliases []string
+}
+
+// DisplayName is a friendly name for the zone, e.g. "Buenos Aires".
+func (z Zone) DisplayName() string {
+	return strings.Replace(z.Name[strings.LastIndex(z.Name, "/")+1:], "_", " ", -1)
+}
+
+// Zones returns every zone in the gazetteer.
+func Zones() []Zone {
+	return append([]Zone(nil), zones...)
+}
+
+var placeIndex = buildPlaceIndex(zones)
+
+func normalizePlace(str string) string {
+	str = strings.ToLower(str)
+	str = strings.NewReplacer("_", " ", ".", "", ",", " ", "'", "").Replace(str)
+	return strings.Join(strings.Fields(str), " ")
+}
+
+func buildPlaceIndex(zones []Zone) map[string][]string {
+	index := make(map[string][]string)
+
+	add := func(key, zone string) {
+		key = normalizePlace(key)
+
+		for _, existing := range index[key] {
+			if existing == zone {
+				return
+			}
+		}
+
+		index[key] = append(index[key], zone)
+	}
+
+	for _, zone := range zones {
+		add(zone.Name, zone.Name)
+
+		// Lets people type "New York" as well as "America/New_York".
+		add(zone.Name[strings.LastIndex(zone.Name, "/")+1:], zone.Name)
+
+		for _, city := range zone.Cities {
+			add(city, zone.Name)
+		}
+
+		for _, abbr := range zone.Abbreviations {
+			add(abbr, zone.Name)
+		}
+
+		for _, alias := range zone.Aliases {
+			add(alias, zone.Name)
+		}
+	}
+
+	return index
+}
+
+// LookupPlace resolves free text like "Tokyo", "new york", "CET" or
+// "Europe/Berlin" to IANA zone names. More than one result means the text
+// was ambiguous, and none means we have no idea where that is.
+func LookupPlace(str string) []string {
+	if zones, ok := placeIndex[normalizePlace(str)]; ok {
+		return zones
+	}
+
+	// Fall back to anything that the zoneinfo database knows about even if it
+	// isn't in our gazetteer.
+	str = strings.TrimSpace(str)
+
+	if strings.Contains(str, "/") {
+		if _, err := time.LoadLocation(str); err == nil {
+			return []string{str}
+		}
+	}
+
+	return nil
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func min(first int, rest ...int) int {
+	for _, n := range rest {
+		if n < first {
+			first = n
+		}
+	}
+
+	return first
+}
+
+// fuzzyRank is the rank of matches that needed a typo forgiven.
+const fuzzyRank = 4
+
+// matchRank says how well query matches a name for a zone, lower being
+// better, or -1 for not at all. Typos are forgiven once the query is long
+// enough to mean something.
+func matchRank(query, name string) int {
+	switch {
+	case name == query:
+		return 0
+	case strings.HasPrefix(name, query):
+		return 1
+	case strings.Contains(" "+strings.Replace(name, "/", " ", -1), " "+query):
+		return 2
+	case strings.Contains(name, query):
+		return 3
+	}
+
+	if len(query) < 5 {
+		return -1
+	}
+
+	allowed := 1
+
+	if len(query) >= 8 {
+		allowed = 2
+	}
+
+	// Compare against the start of the name so that "berln" finds "berlin".
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == ' ' || r == '/' }) {
+		for n := len(query) - 1; n <= len(query)+1; n++ {
+			if n <= len(word) && editDistance(query, word[:n]) <= allowed {
+				return fuzzyRank
+			}
+		}
+	}
+
+	return -1
+}
+
+// SearchZones returns the zones that query might refer to, best matches
+// first. It's for showing people a list to pick from as they type, so unlike
+// LookupPlace it's happy with partial and misspelled names.
+func SearchZones(query string) []Zone {
+	query = normalizePlace(query)
+
+	if query == "" {
+		return Zones()
+	}
+
+	type match struct {
+		zone Zone
+		rank int
+	}
+
+	var matches []match
+
+	for _, zone := range zones {
+		best := -1
+
+		names := []string{zone.Name, zone.DisplayName()}
+		names = append(names, zone.Cities...)
+		names = append(names, zone.Abbreviations...)
+		names = append(names, zone.Aliases...)
+
+		for _, name := range names {
+			if rank := matchRank(query, normalizePlace(name)); rank >= 0 && (best < 0 || rank < best) {
+				best = rank
+			}
+		}
+
+		if best >= 0 {
+			matches = append(matches, match{zone, best})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].rank < matches[j].rank
+	})
+
+	// Typos are only worth suggesting when nothing matches properly.
+	for i, m := range matches {
+		if m.rank == fuzzyRank && matches[0].rank < fuzzyRank {
+			matches = matches[:i]
+			break
+		}
+	}
+
+	result := make([]Zone, len(matches))
+
+	for i, m := range matches {
+		result[i] = m.zone
+	}
+
+	return result
+}
@@ -0,0 +1,109 @@
+package clock
+
+// zones is the gazetteer of places that people are likely to text us. Cities
+// and abbreviations that show up under more than one zone are ambiguous on
+// purpose; we ask the user which one they meant.
+var zones = []Zone{
+	// North America
+	{Name: "America/New_York", Cities: []string{"New York", "NYC", "Brooklyn", "Manhattan", "Boston", "Philadelphia", "Washington", "Washington DC", "DC", "Baltimore", "Pittsburgh", "Atlanta", "Miami", "Orlando", "Tampa", "Charlotte", "Raleigh", "Detroit", "Cleveland", "Columbus", "Springfield", "Portland"}, Abbreviations: []string{"EST", "EDT", "ET", "Eastern"}, Aliases: []string{"US/Eastern", "EST5EDT"}},
+	{Name: "America/Chicago", Cities: []string{"Chicago", "Houston", "Dallas", "Austin", "San Antonio", "Minneapolis", "St Louis", "Saint Louis", "Kansas City", "Nashville", "Memphis", "New Orleans", "Milwaukee", "Oklahoma City", "Omaha", "Springfield", "Birmingham"}, Abbreviations: []string{"CST", "CDT", "CT", "Central"}, Aliases: []string{"US/Central", "CST6CDT"}},
+	{Name: "America/Denver", Cities: []string{"Denver", "Salt Lake City", "Albuquerque", "Boise", "El Paso"}, Abbreviations: []string{"MST", "MDT", "MT", "Mountain"}, Aliases: []string{"US/Mountain", "MST7MDT", "Navajo"}},
+	{Name: "America/Phoenix", Cities: []string{"Phoenix", "Tucson", "Scottsdale", "Arizona"}, Abbreviations: []string{"MST"}, Aliases: []string{"US/Arizona"}},
+	{Name: "America/Los_Angeles", Cities: []string{"Los Angeles", "LA", "San Francisco", "SF", "Oakland", "San Jose", "San Diego", "Sacramento", "Seattle", "Portland", "Las Vegas"}, Abbreviations: []string{"PST", "PDT", "PT", "Pacific"}, Aliases: []string{"US/Pacific", "PST8PDT"}},
+	{Name: "America/Anchorage", Cities: []string{"Anchorage", "Juneau", "Fairbanks", "Alaska"}, Abbreviations: []string{"AKST", "AKDT"}, Aliases: []string{"US/Alaska"}},
+	{Name: "Pacific/Honolulu", Cities: []string{"Honolulu", "Maui", "Hawaii"}, Abbreviations: []string{"HST"}, Aliases: []string{"US/Hawaii"}},
+	{Name: "America/Toronto", Cities: []string{"Toronto", "Ottawa", "Montreal", "Quebec City", "Ontario", "Quebec"}, Aliases: []string{"Canada/Eastern", "America/Montreal"}},
+	{Name: "America/Vancouver", Cities: []string{"Vancouver", "Victoria", "British Columbia"}, Aliases: []string{"Canada/Pacific"}},
+	{Name: "America/Edmonton", Cities: []string{"Edmonton", "Calgary", "Alberta"}, Aliases: []string{"Canada/Mountain"}},
+	{Name: "America/Winnipeg", Cities: []string{"Winnipeg", "Manitoba"}, Aliases: []string{"Canada/Central"}},
+	{Name: "America/Regina", Cities: []string{"Regina", "Saskatoon", "Saskatchewan"}, Aliases: []string{"Canada/Saskatchewan"}},
+	{Name: "America/Halifax", Cities: []string{"Halifax", "Nova Scotia"}, Abbreviations: []string{"AST", "ADT"}, Aliases: []string{"Canada/Atlantic"}},
+	{Name: "America/St_Johns", Cities: []string{"St Johns", "Newfoundland"}, Abbreviations: []string{"NST", "NDT"}, Aliases: []string{"Canada/Newfoundland"}},
+	{Name: "America/Mexico_City", Cities: []string{"Mexico City", "Guadalajara", "Monterrey", "Mexico"}, Aliases: []string{"Mexico/General"}},
+	{Name: "America/Tijuana", Cities: []string{"Tijuana"}, Aliases: []string{"Mexico/BajaNorte", "America/Ensenada"}},
+	{Name: "America/Havana", Cities: []string{"Havana", "Cuba"}, Abbreviations: []string{"CST"}, Aliases: []string{"Cuba"}},
+	{Name: "America/Puerto_Rico", Cities: []string{"San Juan", "Puerto Rico"}, Abbreviations: []string{"AST"}},
+	{Name: "America/Jamaica", Cities: []string{"Kingston", "Jamaica"}, Aliases: []string{"Jamaica"}},
+	{Name: "America/Panama", Cities: []string{"Panama", "Panama City"}},
+	{Name: "America/Costa_Rica", Cities: []string{"San Jose", "Costa Rica"}},
+	{Name: "America/Guatemala", Cities: []string{"Guatemala", "Guatemala City"}},
+
+	// South America
+	{Name: "America/Bogota", Cities: []string{"Bogota", "Medellin", "Cali", "Colombia"}, Abbreviations: []string{"COT"}},
+	{Name: "America/Lima", Cities: []string{"Lima", "Peru"}, Abbreviations: []string{"PET"}},
+	{Name: "America/Caracas", Cities: []string{"Caracas", "Venezuela"}},
+	{Name: "America/Santiago", Cities: []string{"Santiago", "Chile"}, Aliases: []string{"Chile/Continental"}},
+	{Name: "America/Argentina/Buenos_Aires", Cities: []string{"Buenos Aires", "Cordoba", "Argentina"}, Abbreviations: []string{"ART"}, Aliases: []string{"America/Buenos_Aires"}},
+	{Name: "America/Sao_Paulo", Cities: []string{"Sao Paulo", "Rio de Janeiro", "Rio", "Brasilia", "Brazil"}, Abbreviations: []string{"BRT"}, Aliases: []string{"Brazil/East"}},
+
+	// Europe
+	{Name: "Europe/London", Cities: []string{"London", "Manchester", "Birmingham", "Liverpool", "Leeds", "Bristol", "Edinburgh", "Glasgow", "Cardiff", "Belfast", "England", "Scotland", "Wales", "UK", "United Kingdom", "Britain"}, Abbreviations: []string{"BST"}, Aliases: []string{"GB", "GB-Eire", "Europe/Belfast"}},
+	{Name: "Europe/Dublin", Cities: []string{"Dublin", "Cork", "Ireland"}, Abbreviations: []string{"IST"}, Aliases: []string{"Eire"}},
+	{Name: "Europe/Lisbon", Cities: []string{"Lisbon", "Porto", "Portugal"}, Abbreviations: []string{"WET", "WEST"}, Aliases: []string{"Portugal"}},
+	{Name: "Europe/Madrid", Cities: []string{"Madrid", "Barcelona", "Valencia", "Seville", "Spain"}},
+	{Name: "Europe/Paris", Cities: []string{"Paris", "Lyon", "Marseille", "Nice", "Toulouse", "France"}},
+	{Name: "Europe/Brussels", Cities: []string{"Brussels", "Antwerp", "Belgium"}},
+	{Name: "Europe/Amsterdam", Cities: []string{"Amsterdam", "Rotterdam", "The Hague", "Utrecht", "Netherlands", "Holland"}},
+	{Name: "Europe/Berlin", Cities: []string{"Berlin", "Munich", "Hamburg", "Frankfurt", "Cologne", "Stuttgart", "Dusseldorf", "Germany"}, Abbreviations: []string{"CET", "CEST"}},
+	{Name: "Europe/Zurich", Cities: []string{"Zurich", "Geneva", "Basel", "Bern", "Switzerland"}},
+	{Name: "Europe/Vienna", Cities: []string{"Vienna", "Salzburg", "Austria"}},
+	{Name: "Europe/Rome", Cities: []string{"Rome", "Milan", "Naples", "Turin", "Florence", "Venice", "Italy"}},
+	{Name: "Europe/Prague", Cities: []string{"Prague", "Czechia", "Czech Republic"}},
+	{Name: "Europe/Warsaw", Cities: []string{"Warsaw", "Krakow", "Poland"}, Aliases: []string{"Poland"}},
+	{Name: "Europe/Budapest", Cities: []string{"Budapest", "Hungary"}},
+	{Name: "Europe/Belgrade", Cities: []string{"Belgrade", "Serbia"}},
+	{Name: "Europe/Copenhagen", Cities: []string{"Copenhagen", "Denmark"}},
+	{Name: "Europe/Stockholm", Cities: []string{"Stockholm", "Gothenburg", "Sweden"}},
+	{Name: "Europe/Oslo", Cities: []string{"Oslo", "Bergen", "Norway"}},
+	{Name: "Europe/Helsinki", Cities: []string{"Helsinki", "Finland"}, Abbreviations: []string{"EET", "EEST"}},
+	{Name: "Europe/Athens", Cities: []string{"Athens", "Greece"}},
+	{Name: "Europe/Bucharest", Cities: []string{"Bucharest", "Romania"}},
+	{Name: "Europe/Sofia", Cities: []string{"Sofia", "Bulgaria"}},
+	{Name: "Europe/Kiev", Cities: []string{"Kyiv", "Kiev", "Ukraine"}},
+	{Name: "Europe/Istanbul", Cities: []string{"Istanbul", "Ankara", "Turkey"}, Abbreviations: []string{"TRT"}, Aliases: []string{"Turkey", "Asia/Istanbul"}},
+	{Name: "Europe/Moscow", Cities: []string{"Moscow", "St Petersburg", "Saint Petersburg"}, Abbreviations: []string{"MSK"}, Aliases: []string{"W-SU"}},
+	{Name: "Atlantic/Reykjavik", Cities: []string{"Reykjavik", "Iceland"}, Aliases: []string{"Iceland"}},
+
+	// Africa
+	{Name: "Africa/Casablanca", Cities: []string{"Casablanca", "Rabat", "Morocco"}},
+	{Name: "Africa/Accra", Cities: []string{"Accra", "Ghana"}},
+	{Name: "Africa/Lagos", Cities: []string{"Lagos", "Abuja", "Nigeria"}, Abbreviations: []string{"WAT"}},
+	{Name: "Africa/Cairo", Cities: []string{"Cairo", "Alexandria", "Egypt"}, Aliases: []string{"Egypt"}},
+	{Name: "Africa/Johannesburg", Cities: []string{"Johannesburg", "Cape Town", "Pretoria", "Durban", "South Africa"}, Abbreviations: []string{"SAST"}},
+	{Name: "Africa/Nairobi", Cities: []string{"Nairobi", "Kenya"}, Abbreviations: []string{"EAT"}},
+	{Name: "Africa/Addis_Ababa", Cities: []string{"Addis Ababa", "Ethiopia"}},
+
+	// Asia
+	{Name: "Asia/Jerusalem", Cities: []string{"Jerusalem", "Tel Aviv", "Israel"}, Abbreviations: []string{"IST", "IDT"}, Aliases: []string{"Israel", "Asia/Tel_Aviv"}},
+	{Name: "Asia/Riyadh", Cities: []string{"Riyadh", "Jeddah", "Saudi Arabia"}},
+	{Name: "Asia/Tehran", Cities: []string{"Tehran", "Iran"}, Aliases: []string{"Iran"}},
+	{Name: "Asia/Dubai", Cities: []string{"Dubai", "Abu Dhabi", "UAE", "United Arab Emirates"}, Abbreviations: []string{"GST"}},
+	{Name: "Asia/Karachi", Cities: []string{"Karachi", "Lahore", "Islamabad", "Hyderabad", "Pakistan"}, Abbreviations: []string{"PKT"}},
+	{Name: "Asia/Kolkata", Cities: []string{"Kolkata", "Calcutta", "Mumbai", "Bombay", "Delhi", "New Delhi", "Bangalore", "Bengaluru", "Chennai", "Hyderabad", "Pune", "India"}, Abbreviations: []string{"IST"}, Aliases: []string{"Asia/Calcutta"}},
+	{Name: "Asia/Kathmandu", Cities: []string{"Kathmandu", "Nepal"}, Aliases: []string{"Asia/Katmandu"}},
+	{Name: "Asia/Dhaka", Cities: []string{"Dhaka", "Bangladesh"}, Abbreviations: []string{"BST"}, Aliases: []string{"Asia/Dacca"}},
+	{Name: "Asia/Bangkok", Cities: []string{"Bangkok", "Thailand"}, Abbreviations: []string{"ICT"}},
+	{Name: "Asia/Ho_Chi_Minh", Cities: []string{"Ho Chi Minh City", "Saigon", "Hanoi", "Vietnam"}, Aliases: []string{"Asia/Saigon"}},
+	{Name: "Asia/Jakarta", Cities: []string{"Jakarta"}, Abbreviations: []string{"WIB"}},
+	{Name: "Asia/Kuala_Lumpur", Cities: []string{"Kuala Lumpur", "Malaysia"}},
+	{Name: "Asia/Singapore", Cities: []string{"Singapore"}, Abbreviations: []string{"SGT"}, Aliases: []string{"Singapore"}},
+	{Name: "Asia/Manila", Cities: []string{"Manila", "Philippines"}, Abbreviations: []string{"PHT"}},
+	{Name: "Asia/Shanghai", Cities: []string{"Shanghai", "Beijing", "Shenzhen", "Guangzhou", "Chengdu", "China"}, Abbreviations: []string{"CST"}, Aliases: []string{"PRC", "Asia/Chongqing", "Asia/Harbin"}},
+	{Name: "Asia/Hong_Kong", Cities: []string{"Hong Kong"}, Abbreviations: []string{"HKT"}, Aliases: []string{"Hongkong"}},
+	{Name: "Asia/Taipei", Cities: []string{"Taipei", "Taiwan"}, Aliases: []string{"ROC"}},
+	{Name: "Asia/Seoul", Cities: []string{"Seoul", "Busan", "Korea", "South Korea"}, Abbreviations: []string{"KST"}, Aliases: []string{"ROK"}},
+	{Name: "Asia/Tokyo", Cities: []string{"Tokyo", "Osaka", "Kyoto", "Yokohama", "Japan"}, Abbreviations: []string{"JST"}, Aliases: []string{"Japan"}},
+
+	// Oceania
+	{Name: "Australia/Perth", Cities: []string{"Perth"}, Abbreviations: []string{"AWST"}, Aliases: []string{"Australia/West"}},
+	{Name: "Australia/Darwin", Cities: []string{"Darwin"}, Aliases: []string{"Australia/North"}},
+	{Name: "Australia/Adelaide", Cities: []string{"Adelaide"}, Abbreviations: []string{"ACST", "ACDT"}, Aliases: []string{"Australia/South"}},
+	{Name: "Australia/Brisbane", Cities: []string{"Brisbane", "Gold Coast", "Queensland"}, Aliases: []string{"Australia/Queensland"}},
+	{Name: "Australia/Sydney", Cities: []string{"Sydney", "Canberra", "New South Wales"}, Abbreviations: []string{"AEST", "AEDT"}, Aliases: []string{"Australia/NSW", "Australia/ACT", "Australia/Canberra"}},
+	{Name: "Australia/Melbourne", Cities: []string{"Melbourne", "Victoria"}, Aliases: []string{"Australia/Victoria"}},
+	{Name: "Australia/Hobart", Cities: []string{"Hobart", "Tasmania"}, Aliases: []string{"Australia/Tasmania"}},
+	{Name: "Pacific/Auckland", Cities: []string{"Auckland", "Wellington", "Christchurch", "New Zealand"}, Abbreviations: []string{"NZST", "NZDT"}, Aliases: []string{"NZ"}},
+	{Name: "Pacific/Fiji", Cities: []string{"Suva", "Fiji"}},
+
+	{Name: "UTC", Cities: []string{"Zulu"}, Abbreviations: []string{"GMT", "Z"}, Aliases: []string{"Etc/UTC", "Etc/GMT", "Etc/Universal", "Etc/Zulu", "UCT", "Universal"}},
+}
@@ -0,0 +1,88 @@
+package clock
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupPlace(t *testing.T) {
+	tests := []struct {
+		place    string
+		expected []string
+	}{
+		{"Tokyo", []string{"Asia/Tokyo"}},
+		{"  tokyo ", []string{"Asia/Tokyo"}},
+		{"New York", []string{"America/New_York"}},
+		{"new_york", []string{"America/New_York"}},
+		{"St. Louis", []string{"America/Chicago"}},
+		{"CET", []string{"Europe/Berlin"}},
+		{"Europe/Berlin", []string{"Europe/Berlin"}},
+		{"europe/berlin", []string{"Europe/Berlin"}},
+		{"Buenos Aires", []string{"America/Argentina/Buenos_Aires"}},
+		{"America/Indiana/Indianapolis", []string{"America/Indiana/Indianapolis"}},
+		{"US/Pacific", []string{"America/Los_Angeles"}},
+		{"Asia/Calcutta", []string{"Asia/Kolkata"}},
+		{"Etc/UTC", []string{"UTC"}},
+		{"Portland", []string{"America/New_York", "America/Los_Angeles"}},
+		{"IST", []string{"Europe/Dublin", "Asia/Jerusalem", "Asia/Kolkata"}},
+		{"Atlantis", nil},
+		{"Local", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, LookupPlace(test.place), "looking up %q", test.place)
+	}
+}
+
+func TestGazetteerZonesAreLoadable(t *testing.T) {
+	for _, zone := range zones {
+		assert.NotPanics(t, func() {
+			MustLoadLocation(zone.Name)
+		}, zone.Name)
+	}
+}
+
+func TestSearchZones(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"berl", []string{"Europe/Berlin"}},
+		{"Berln", []string{"Europe/Berlin", "Europe/Zurich"}},
+		{"tokyo", []string{"Asia/Tokyo"}},
+		{"JST", []string{"Asia/Tokyo"}},
+		{"us/pac", []string{"America/Los_Angeles"}},
+		{"buenos", []string{"America/Argentina/Buenos_Aires"}},
+
+		// Better matches come first.
+		{"portland", []string{"America/New_York", "America/Los_Angeles"}},
+		{"san jose", []string{"America/Los_Angeles", "America/Costa_Rica"}},
+		{"melb", []string{"Australia/Melbourne"}},
+
+		{"xyzzy", []string{}},
+	}
+
+	for _, test := range tests {
+		names := []string{}
+
+		for _, zone := range SearchZones(test.query) {
+			names = append(names, zone.Name)
+		}
+
+		assert.Equal(t, test.expected, names, "searching for %q", test.query)
+	}
+
+	assert.Len(t, SearchZones(""), len(zones))
+}
+
+func TestGazetteerAliasesAreLoadable(t *testing.T) {
+	for _, zone := range zones {
+		for _, alias := range zone.Aliases {
+			assert.NotPanics(t, func() {
+				MustLoadLocation(alias)
+			}, alias)
+		}
+	}
+}
@@ -13,3 +13,92 @@ func MustLoadLocation(name string) *time.Location {
 func GetDayInZone(loc *time.Location) string {
 	return Clock().In(loc).Format("Monday")
 }
+
+// NextMidnight returns the start of the day after t, in t's location. Where
+// the clocks go forward at midnight, the day starts when they change.
+func NextMidnight(t time.Time) time.Time {
+	year, month, day := t.Date()
+	midnight := time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+
+	if _, _, d := midnight.Date(); d == day {
+		// Midnight was skipped, and time.Date normalized it with the offset
+		// from after the change, which is still before the day started.
+		_, before := midnight.Zone()
+		_, after := midnight.Add(24 * time.Hour).Zone()
+		midnight = midnight.Add(time.Duration(after-before) * time.Second)
+	}
+
+	return midnight
+}
+
+// IsDST indicates that daylight saving time is in effect at t, which is when
+// the offset is ahead of where it is at the other end of the year.
+func IsDST(t time.Time) bool {
+	_, offset := t.Zone()
+	_, jan := time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location()).Zone()
+	_, jul := time.Date(t.Year(), time.July, 1, 0, 0, 0, 0, t.Location()).Zone()
+
+	return offset > min(jan, jul)
+}
+
+// StartOfDay returns the start of the day that t is in, in t's location.
+func StartOfDay(t time.Time) time.Time {
+	return NextMidnight(t.AddDate(0, 0, -1))
+}
+
+// Transition is a change in a zone's offset from UTC.
+type Transition struct {
+	// The first instant that the new offset applies.
+	At time.Time
+
+	// Offsets from UTC in seconds, before and after the change.
+	OffsetBefore int
+	OffsetAfter  int
+
+	// The zone's abbreviation after the change, e.g. "PDT".
+	Name string
+
+	DST bool
+}
+
+// Transitions returns the changes in loc's offset between from and to. It
+// assumes that they're at least a day apart, which is true everywhere.
+func Transitions(loc *time.Location, from, to time.Time) []Transition {
+	var transitions []Transition
+
+	offset := func(t time.Time) int {
+		_, offset := t.In(loc).Zone()
+		return offset
+	}
+
+	for lo := from.Truncate(time.Second); lo.Before(to); lo = lo.Add(24 * time.Hour) {
+		hi := lo.Add(24 * time.Hour)
+
+		if offset(lo) == offset(hi) {
+			continue
+		}
+
+		before := offset(lo)
+
+		// Offsets change on the second, so narrow it down to that.
+		for a, b := lo, hi; ; {
+			if b.Sub(a) <= time.Second {
+				if b.Before(to) {
+					at := b.In(loc)
+					name, after := at.Zone()
+					transitions = append(transitions, Transition{at, before, after, name, IsDST(at)})
+				}
+
+				break
+			}
+
+			if mid := a.Add(b.Sub(a) / 2).Truncate(time.Second); offset(mid) == before {
+				a = mid
+			} else {
+				b = mid
+			}
+		}
+	}
+
+	return transitions
+}
@@ -24,6 +24,86 @@ func TestGetDayInZone(t *testing.T) {
 	})
 }
 
+func TestNextMidnight(t *testing.T) {
+	tests := []struct {
+		now      string
+		zone     string
+		expected string
+	}{
+		{"2026-10-19T17:00:00Z", "UTC", "2026-10-20T00:00:00Z"},
+		{"2026-10-19T17:00:00Z", "Asia/Tokyo", "2026-10-21T00:00:00+09:00"},
+		{"2026-12-31T23:59:59Z", "UTC", "2027-01-01T00:00:00Z"},
+
+		// The day that the clocks change is only 23 hours long.
+		{"2026-03-08T08:00:00Z", "America/Los_Angeles", "2026-03-09T00:00:00-07:00"},
+
+		// Midnight doesn't happen in Santiago the day the clocks go forward.
+		{"2026-09-05T12:00:00Z", "America/Santiago", "2026-09-06T01:00:00-03:00"},
+	}
+
+	for _, test := range tests {
+		now := mustParseTime(test.now).In(MustLoadLocation(test.zone))
+		assert.Equal(t, test.expected, NextMidnight(now).Format(time.RFC3339), test.now+" in "+test.zone)
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	tests := []struct {
+		now      string
+		zone     string
+		expected string
+	}{
+		{"2026-10-19T17:00:00Z", "UTC", "2026-10-19T00:00:00Z"},
+		{"2026-10-19T17:00:00Z", "Asia/Tokyo", "2026-10-20T00:00:00+09:00"},
+		{"2026-11-01T12:00:00Z", "America/Los_Angeles", "2026-11-01T00:00:00-07:00"},
+		{"2026-09-06T12:00:00Z", "America/Santiago", "2026-09-06T01:00:00-03:00"},
+	}
+
+	for _, test := range tests {
+		now := mustParseTime(test.now).In(MustLoadLocation(test.zone))
+		assert.Equal(t, test.expected, StartOfDay(now).Format(time.RFC3339), test.now+" in "+test.zone)
+	}
+}
+
+func TestIsDST(t *testing.T) {
+	tests := []struct {
+		now      string
+		zone     string
+		expected bool
+	}{
+		{"2026-07-01T12:00:00Z", "America/New_York", true},
+		{"2026-12-01T12:00:00Z", "America/New_York", false},
+		{"2026-07-01T12:00:00Z", "Australia/Sydney", false},
+		{"2026-12-01T12:00:00Z", "Australia/Sydney", true},
+		{"2026-07-01T12:00:00Z", "Asia/Tokyo", false},
+		{"2026-07-01T12:00:00Z", "UTC", false},
+	}
+
+	for _, test := range tests {
+		now := mustParseTime(test.now).In(MustLoadLocation(test.zone))
+		assert.Equal(t, test.expected, IsDST(now), test.now+" in "+test.zone)
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	loc := MustLoadLocation("America/Los_Angeles")
+	transitions := Transitions(loc, *mustParseTime("2026-01-01T00:00:00Z"), *mustParseTime("2027-01-01T00:00:00Z"))
+
+	if assert.Len(t, transitions, 2) {
+		assert.Equal(t, "2026-03-08T03:00:00-07:00", transitions[0].At.Format(time.RFC3339))
+		assert.Equal(t, -8*60*60, transitions[0].OffsetBefore)
+		assert.Equal(t, -7*60*60, transitions[0].OffsetAfter)
+		assert.Equal(t, "PDT", transitions[0].Name)
+		assert.True(t, transitions[0].DST)
+
+		assert.Equal(t, "2026-11-01T01:00:00-08:00", transitions[1].At.Format(time.RFC3339))
+		assert.Equal(t, "PST", transitions[1].Name)
+		assert.False(t, transitions[1].DST)
+	}
+
+	assert.Empty(t, Transitions(MustLoadLocation("Asia/Tokyo"), *mustParseTime("2026-01-01T00:00:00Z"), *mustParseTime("2027-01-01T00:00:00Z")))
+}
+
 func makeMockClock(t *time.Time) ClockFunc {
 	return func() *time.Time {
 		return t
@@ -0,0 +1,113 @@
+package clock
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTimeOfDay = errors.New("clock: invalid time of day")
+	ErrInvalidWeekday   = errors.New("clock: invalid weekday")
+)
+
+// Weekdays is a set of days of the week. The zero value means every day.
+type Weekdays uint8
+
+const allWeekdays Weekdays = 1<<7 - 1
+
+// NewWeekdays returns the set of days.
+func NewWeekdays(days ...time.Weekday) (w Weekdays) {
+	for _, d := range days {
+		w |= 1 << uint(d)
+	}
+
+	return
+}
+
+// Contains indicates whether d is in the set.
+func (w Weekdays) Contains(d time.Weekday) bool {
+	return w == 0 || w&(1<<uint(d)) != 0
+}
+
+// IsEveryDay indicates whether the set has all seven days in it.
+func (w Weekdays) IsEveryDay() bool {
+	return w == 0 || w&allWeekdays == allWeekdays
+}
+
+// Days lists the days in the set, starting with Sunday.
+func (w Weekdays) Days() []time.Weekday {
+	var days []time.Weekday
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if w.Contains(d) {
+			days = append(days, d)
+		}
+	}
+
+	return days
+}
+
+// ParseWeekday reads the name of a day of the week. Anything from the first
+// three letters to the whole name works, in any case.
+func ParseWeekday(str string) (time.Weekday, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+
+	if len(str) >= 3 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), str) {
+				return d, nil
+			}
+		}
+	}
+
+	return time.Sunday, ErrInvalidWeekday
+}
+
+// Schedule is when, in someone's own timezone, they get their message.
+type Schedule struct {
+	// The time of day, in minutes after midnight.
+	Minute int
+
+	// The days of the week to send on.
+	Days Weekdays
+}
+
+// DefaultSchedule is 8am every day.
+var DefaultSchedule = Schedule{Minute: 8 * 60}
+
+// ParseTimeOfDay reads a 24-hour "15:04" time in to minutes after midnight.
+func ParseTimeOfDay(str string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(str))
+
+	if err != nil {
+		return 0, ErrInvalidTimeOfDay
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// FormatTimeOfDay is the opposite of ParseTimeOfDay.
+func FormatTimeOfDay(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+// on returns the scheduled time on the date that's offset days after t.
+func (s Schedule) on(t time.Time, offset int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+offset, s.Minute/60, s.Minute%60, 0, 0, t.Location())
+}
+
+// firstFrom returns the scheduled time on the first scheduled day that's
+// offset or more days after t.
+func (s Schedule) firstFrom(t time.Time, offset int) *time.Time {
+	for i := offset; i < offset+7; i++ {
+		if deadline := s.on(t, i); s.Days.Contains(deadline.Weekday()) {
+			return &deadline
+		}
+	}
+
+	// Days is never empty, so this can't happen.
+	deadline := s.on(t, offset)
+	return &deadline
+}
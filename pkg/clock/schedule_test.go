@@ -0,0 +1,68 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeekdays(t *testing.T) {
+	var every Weekdays
+	assert.True(t, every.IsEveryDay())
+	assert.True(t, every.Contains(time.Wednesday))
+	assert.Len(t, every.Days(), 7)
+
+	weekends := NewWeekdays(time.Sunday, time.Saturday)
+	assert.False(t, weekends.IsEveryDay())
+	assert.False(t, weekends.Contains(time.Wednesday))
+	assert.Equal(t, []time.Weekday{time.Sunday, time.Saturday}, weekends.Days())
+
+	assert.True(t, NewWeekdays(every.Days()...).IsEveryDay())
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected time.Weekday
+		err      error
+	}{
+		{"monday", time.Monday, nil},
+		{"Tue", time.Tuesday, nil},
+		{" WEDNES ", time.Wednesday, nil},
+		{"sat", time.Saturday, nil},
+		{"su", time.Sunday, ErrInvalidWeekday},
+		{"someday", time.Sunday, ErrInvalidWeekday},
+		{"mondays", time.Sunday, ErrInvalidWeekday},
+	}
+
+	for _, test := range tests {
+		d, err := ParseWeekday(test.str)
+		assert.Equal(t, test.err, err, test.str)
+		assert.Equal(t, test.expected, d, test.str)
+	}
+}
+
+func TestParseTimeOfDay(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected int
+		err      error
+	}{
+		{"08:00", 480, nil},
+		{"7:30", 450, nil},
+		{"23:59", 1439, nil},
+		{"00:00", 0, nil},
+		{"24:00", 0, ErrInvalidTimeOfDay},
+		{"8am", 0, ErrInvalidTimeOfDay},
+		{"", 0, ErrInvalidTimeOfDay},
+	}
+
+	for _, test := range tests {
+		minute, err := ParseTimeOfDay(test.str)
+		assert.Equal(t, test.err, err, test.str)
+		assert.Equal(t, test.expected, minute, test.str)
+	}
+
+	assert.Equal(t, "07:30", FormatTimeOfDay(450))
+}
@@ -0,0 +1,119 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Interaction types that Discord sends us.
+const (
+	InteractionPing               = 1
+	InteractionApplicationCommand = 2
+)
+
+// Interaction response types.
+const (
+	ResponsePong           = 1
+	ResponseChannelMessage = 4
+)
+
+// FlagEphemeral shows a message to the person that asked for it and nobody
+// else.
+const FlagEphemeral = 1 << 6
+
+// MaxRequestAge is how old a signed request can be before we assume that
+// somebody is replaying it.
+var MaxRequestAge = 5 * time.Minute
+
+var (
+	ErrInvalidSignature = errors.New("discord: invalid request signature")
+	ErrStaleRequest     = errors.New("discord: request is too old")
+)
+
+// Interaction is something that somebody did with the app, like running one of
+// its commands.
+type Interaction struct {
+	Type int              `json:"type"`
+	Data *InteractionData `json:"data,omitempty"`
+}
+
+// InteractionData is the command that was run.
+type InteractionData struct {
+	Name    string              `json:"name"`
+	Options []InteractionOption `json:"options,omitempty"`
+}
+
+type InteractionOption struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// Option returns the value of the option called name, or nothing if it wasn't
+// given.
+func (d InteractionData) Option(name string) string {
+	for _, option := range d.Options {
+		if option.Name == name && option.Value != nil {
+			return fmt.Sprint(option.Value)
+		}
+	}
+
+	return ""
+}
+
+// InteractionResponse is what we answer an interaction with.
+type InteractionResponse struct {
+	Type int                  `json:"type"`
+	Data *InteractionCallback `json:"data,omitempty"`
+}
+
+// InteractionCallback is the message that we answer a command with.
+type InteractionCallback struct {
+	Content string `json:"content"`
+	Flags   int    `json:"flags,omitempty"`
+}
+
+// ParsePublicKey parses the application's public key, as it's shown in the
+// developer portal.
+func ParsePublicKey(str string) (ed25519.PublicKey, error) {
+	key, err := hex.DecodeString(str)
+
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("discord: public key must be 64 hex characters")
+	}
+
+	return ed25519.PublicKey(key), nil
+}
+
+// VerifyRequest checks that body came from Discord, using the signature and
+// timestamp from the X-Signature-Ed25519 and X-Signature-Timestamp headers.
+func VerifyRequest(key ed25519.PublicKey, signature, timestamp string, body []byte, now time.Time) error {
+	if len(key) != ed25519.PublicKeySize {
+		return ErrInvalidSignature
+	}
+
+	sig, err := hex.DecodeString(signature)
+
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return ErrInvalidSignature
+	}
+
+	if !ed25519.Verify(key, append([]byte(timestamp), body...), sig) {
+		return ErrInvalidSignature
+	}
+
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if age := now.Sub(time.Unix(sec, 0)); age > MaxRequestAge || age < -MaxRequestAge {
+		return ErrStaleRequest
+	}
+
+	return nil
+}
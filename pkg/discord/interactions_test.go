@@ -0,0 +1,54 @@
+package discord
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	public, private, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	body := []byte(`{"type":1}`)
+	now := time.Unix(1760893200, 0)
+	timestamp := "1760893200"
+	signature := hex.EncodeToString(ed25519.Sign(private, append([]byte(timestamp), body...)))
+
+	assert.NoError(t, VerifyRequest(public, signature, timestamp, body, now))
+	assert.Equal(t, ErrStaleRequest, VerifyRequest(public, signature, timestamp, body, now.Add(6*time.Minute)))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(public, signature, timestamp, []byte(`{"type":2}`), now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(public, signature, "1760893201", body, now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(public, "nope", timestamp, body, now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(nil, signature, timestamp, body, now))
+
+	other, _, _ := ed25519.GenerateKey(nil)
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(other, signature, timestamp, body, now))
+}
+
+func TestParsePublicKey(t *testing.T) {
+	public, _, _ := ed25519.GenerateKey(nil)
+
+	key, err := ParsePublicKey(hex.EncodeToString(public))
+	assert.NoError(t, err)
+	assert.Equal(t, public, key)
+
+	_, err = ParsePublicKey("abcd")
+	assert.Error(t, err)
+
+	_, err = ParsePublicKey("not hex")
+	assert.Error(t, err)
+}
+
+func TestInteractionOption(t *testing.T) {
+	var interaction Interaction
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"type": 2, "data": {"name": "whatday", "options": [{"name": "timezone", "type": 3, "value": "Tokyo"}]}}`), &interaction))
+	assert.Equal(t, InteractionApplicationCommand, interaction.Type)
+	assert.Equal(t, "Tokyo", interaction.Data.Option("timezone"))
+	assert.Equal(t, "", interaction.Data.Option("date"))
+}
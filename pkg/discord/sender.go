@@ -0,0 +1,100 @@
+package discord
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/bradhe/what-day-is-it/pkg/hooks"
+	"github.com/bradhe/what-day-is-it/pkg/logs"
+)
+
+var logger = logs.WithPackage("discord")
+
+// We won't post anywhere but Discord, since the URLs come from whoever fills
+// in the subscribe form.
+var webhookHosts = map[string]bool{
+	"discord.com":    true,
+	"discordapp.com": true,
+}
+
+var webhookpathexp = regexp.MustCompile(`^/api(/v[0-9]+)?/webhooks/[0-9]+/[A-Za-z0-9_\-]+$`)
+
+// IsWebhookURL indicates that str looks like a Discord channel webhook URL.
+func IsWebhookURL(str string) bool {
+	u, err := url.Parse(str)
+
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "https" && webhookHosts[u.Host] && webhookpathexp.MatchString(u.Path)
+}
+
+// Message is what we post to a webhook.
+type Message struct {
+	Content string `json:"content"`
+
+	// Keeps us from pinging anybody, whatever ends up in the content.
+	AllowedMentions allowedMentions `json:"allowed_mentions"`
+}
+
+type allowedMentions struct {
+	Parse []string `json:"parse"`
+}
+
+// Error is Discord refusing a message that we posted to a webhook.
+type Error struct {
+	Status  int    `json:"-"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("discord: %s (code %d, status %d)", e.Message, e.Code, e.Status)
+}
+
+// These mean the webhook is gone for good, so there's no point in trying
+// again tomorrow.
+var undeliverableCodes = map[int]bool{
+	10003: true, // Unknown channel
+	10015: true, // Unknown webhook
+	50027: true, // Invalid webhook token
+}
+
+// Undeliverable indicates that the webhook can't take messages at all, as
+// opposed to something going wrong this time around.
+func (e *Error) Undeliverable() bool {
+	return undeliverableCodes[e.Code]
+}
+
+// Sender posts messages to channel webhooks.
+type Sender struct {
+	hooks.Sender
+}
+
+func NewSender() Sender {
+	return Sender{hooks.NewSender(func(status int, body []byte) error {
+		derr := Error{Status: status}
+
+		if err := json.Unmarshal(body, &derr); err != nil {
+			logger.WithError(err).Error("failed to parse Discord error response")
+		}
+
+		return &derr
+	})}
+}
+
+// Send posts body to the webhook at to.
+func (s Sender) Send(to, body string) error {
+	buf, _ := json.Marshal(Message{Content: body, AllowedMentions: allowedMentions{Parse: []string{}}})
+
+	if _, err := s.Post(to, nil, buf); err != nil {
+		logger.WithError(err).Error("failed to post message to Discord")
+		return err
+	}
+
+	logger.Info("message posted to Discord")
+	return nil
+}
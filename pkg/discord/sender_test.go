@@ -0,0 +1,61 @@
+package discord
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	var got map[string]interface{}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	assert.NoError(t, NewSender().Send(ts.URL, "Today is Monday"))
+	assert.Equal(t, "Today is Monday", got["content"])
+	assert.Equal(t, map[string]interface{}{"parse": []interface{}{}}, got["allowed_mentions"])
+}
+
+func TestSendErrors(t *testing.T) {
+	cases := []struct {
+		status        int
+		body          string
+		undeliverable bool
+	}{
+		{http.StatusNotFound, `{"message": "Unknown Webhook", "code": 10015}`, true},
+		{http.StatusUnauthorized, `{"message": "Invalid Webhook Token", "code": 50027}`, true},
+		{http.StatusTooManyRequests, `{"message": "You are being rate limited.", "retry_after": 1.5, "global": false}`, false},
+		{http.StatusBadGateway, `<html>nope</html>`, false},
+	}
+
+	for _, c := range cases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.body))
+		}))
+
+		err := NewSender().Send(ts.URL, "Today is Monday")
+		ts.Close()
+
+		if derr, ok := err.(*Error); assert.True(t, ok, c.body) {
+			assert.Equal(t, c.status, derr.Status)
+			assert.Equal(t, c.undeliverable, derr.Undeliverable(), c.body)
+		}
+	}
+}
+
+func TestIsWebhookURL(t *testing.T) {
+	assert.True(t, IsWebhookURL("https://discord.com/api/webhooks/123456789012345678/abc-DEF_123"))
+	assert.True(t, IsWebhookURL("https://discordapp.com/api/v10/webhooks/123456789012345678/abc-DEF_123"))
+	assert.False(t, IsWebhookURL("http://discord.com/api/webhooks/123456789012345678/abc"))
+	assert.False(t, IsWebhookURL("https://discord.com.example.com/api/webhooks/123456789012345678/abc"))
+	assert.False(t, IsWebhookURL("https://discord.com/api/channels/123456789012345678/messages"))
+	assert.False(t, IsWebhookURL("not a url"))
+}
@@ -0,0 +1,109 @@
+// Package hooks is what posting to Slack, Discord and our own webhooks have in
+// common: sending JSON to a URL, and signing it.
+package hooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// How long a receiver gets to answer.
+const DefaultTimeout = 10 * time.Second
+
+// How much of a response we read, whether or not anybody looks at it.
+const maxResponseSize = 64 * 1024
+
+var (
+	ErrInvalidSignature = errors.New("hooks: invalid signature")
+	ErrStaleRequest     = errors.New("hooks: stale request")
+)
+
+// Sender posts JSON to URLs.
+type Sender struct {
+	Client *http.Client
+
+	// Refused makes the error for a response that isn't a 2xx, from its
+	// status and the start of its body.
+	Refused func(status int, body []byte) error
+}
+
+func NewSender(refused func(int, []byte) error) Sender {
+	return Sender{&http.Client{Timeout: DefaultTimeout}, refused}
+}
+
+// Post sends body to url as JSON, along with header, and returns the status
+// of the response. It's 0 if we never got one.
+func (s Sender) Post(url string, header http.Header, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+
+	if err != nil {
+		return 0, err
+	}
+
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.Client.Do(req)
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	// Reading all of it lets the connection be used again.
+	buf, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, s.Refused(resp.StatusCode, buf)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign returns version, =, and the hex HMAC-SHA256 of
+// "<version>:<timestamp>:<body>" keyed with secret. That's how Slack signs
+// what it sends us, and how we sign what we send.
+func Sign(secret []byte, version, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(version + ":" + timestamp + ":"))
+	mac.Write(body)
+
+	return version + "=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that signature is what Sign makes of body, and that
+// timestamp, in seconds since the epoch, is no more than maxAge from now
+// either way.
+func Verify(secret []byte, version, timestamp, signature string, body []byte, now time.Time, maxAge time.Duration) error {
+	if len(secret) == 0 {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(secret, version, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+
+	sec, err := strconv.ParseInt(timestamp, 10, 64)
+
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	if age := now.Sub(time.Unix(sec, 0)); age > maxAge || age < -maxAge {
+		return ErrStaleRequest
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package hooks
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPost(t *testing.T) {
+	var req *http.Request
+	var body []byte
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		body, _ = ioutil.ReadAll(r.Body)
+
+		if r.Header.Get("X-Nope") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("no thanks"))
+		}
+	}))
+	defer ts.Close()
+
+	var refusedWith []byte
+	s := NewSender(func(status int, body []byte) error {
+		refusedWith = body
+		return errors.New("refused")
+	})
+
+	status, err := s.Post(ts.URL, http.Header{"X-Thing": {"yes"}}, []byte(`{"text": "Today is Monday"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "yes", req.Header.Get("X-Thing"))
+	assert.Equal(t, `{"text": "Today is Monday"}`, string(body))
+
+	status, err = s.Post(ts.URL, http.Header{"X-Nope": {"yes"}}, nil)
+	assert.EqualError(t, err, "refused")
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Equal(t, "no thanks", string(refusedWith))
+}
+
+func TestVerify(t *testing.T) {
+	secret := []byte("sekrit")
+	body := []byte(`{"text": "Today is Monday"}`)
+	now := time.Unix(1792429200, 0)
+	signature := Sign(secret, "v1", "1792429200", body)
+
+	assert.NoError(t, Verify(secret, "v1", "1792429200", signature, body, now, time.Minute))
+
+	assert.Equal(t, ErrStaleRequest, Verify(secret, "v1", "1792429200", signature, body, now.Add(2*time.Minute), time.Minute))
+	assert.Equal(t, ErrInvalidSignature, Verify(secret, "v0", "1792429200", signature, body, now, time.Minute))
+	assert.Equal(t, ErrInvalidSignature, Verify(nil, "v1", "1792429200", signature, body, now, time.Minute))
+	assert.Equal(t, ErrInvalidSignature, Verify(secret, "v1", "1792429201", signature, body, now, time.Minute))
+}
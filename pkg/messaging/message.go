@@ -0,0 +1,127 @@
+package messaging
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+)
+
+// Message is an email with a plain text body and an HTML one, which mail
+// clients pick between.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+
+	Text string
+	HTML string
+
+	// Anything else to send along, like List-Unsubscribe.
+	Header map[string]string
+}
+
+// messageID makes up a Message-ID at the domain that the message is from.
+func messageID(from string) string {
+	domain := "localhost"
+
+	if addr, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndexByte(addr.Address, '@'); i >= 0 {
+			domain = addr.Address[i+1:]
+		}
+	}
+
+	buf := make([]byte, 16)
+
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(buf), domain)
+}
+
+func writePart(w *multipart.Writer, contentType, body string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+
+	return qp.Close()
+}
+
+// Bytes renders m the way it goes over the wire, as multipart/alternative
+// with CRLF line endings.
+func (m Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	header := map[string]string{
+		"From":         m.From,
+		"To":           m.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date":         clock.Clock().Format(mailDateFormat),
+		"Message-ID":   messageID(m.From),
+		"MIME-Version": "1.0",
+		"Content-Type": "multipart/alternative; boundary=" + w.Boundary(),
+	}
+
+	for k, v := range m.Header {
+		header[k] = v
+	}
+
+	keys := make([]string, 0, len(header))
+
+	for k := range header {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		// Nothing we put in a header should be able to start another one.
+		if strings.ContainsAny(header[k], "\r\n") {
+			return nil, fmt.Errorf("messaging: invalid %s header", k)
+		}
+
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, header[k])
+	}
+
+	buf.WriteString("\r\n")
+
+	// Plain text goes first since clients prefer the last part they can show.
+	if err := writePart(w, "text/plain", m.Text); err != nil {
+		return nil, err
+	}
+
+	if err := writePart(w, "text/html", m.HTML); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// RFC 5322 dates, like Mon, 19 Oct 2026 08:00:00 +0000.
+const mailDateFormat = "Mon, 02 Jan 2006 15:04:05 -0700"
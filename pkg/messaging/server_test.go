@@ -0,0 +1,241 @@
+package messaging
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receivedMessage is a message that the test server accepted.
+type receivedMessage struct {
+	From string
+	To   []string
+	Data string
+
+	// How the message got to us.
+	TLS      bool
+	Username string
+}
+
+// testSMTPServer is just enough of an SMTP server to send mail to.
+type testSMTPServer struct {
+	sync.Mutex
+
+	l        net.Listener
+	tls      *tls.Config
+	received []receivedMessage
+
+	username string
+	password string
+
+	// Recipients that get a 550.
+	rejected map[string]bool
+}
+
+// newTestCertificate makes a self-signed certificate for 127.0.0.1, and a
+// pool that trusts it.
+func newTestCertificate(t *testing.T) (tls.Certificate, *x509.CertPool) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, pool
+}
+
+// newTestSMTPServer starts a server that supports STARTTLS with config, or
+// doesn't if it's nil.
+func newTestSMTPServer(t *testing.T, config *tls.Config) *testSMTPServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testSMTPServer{l: l, tls: config, rejected: make(map[string]bool)}
+	go s.serve()
+
+	return s
+}
+
+func (s *testSMTPServer) addr() string {
+	return s.l.Addr().String()
+}
+
+func (s *testSMTPServer) close() {
+	s.l.Close()
+}
+
+func (s *testSMTPServer) Received() []receivedMessage {
+	s.Lock()
+	defer s.Unlock()
+
+	return append([]receivedMessage(nil), s.received...)
+}
+
+func (s *testSMTPServer) serve() {
+	for {
+		conn, err := s.l.Accept()
+
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *testSMTPServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	tc := textproto.NewConn(conn)
+	tc.PrintfLine("220 127.0.0.1 ESMTP test")
+
+	var msg receivedMessage
+
+	for {
+		line, err := tc.ReadLine()
+
+		if err != nil {
+			return
+		}
+
+		verb, arg := line, ""
+
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			verb, arg = line[:i], line[i+1:]
+		}
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			extensions := []string{"127.0.0.1", "8BITMIME"}
+
+			if s.tls != nil && !msg.TLS {
+				extensions = append(extensions, "STARTTLS")
+			}
+
+			if s.username != "" {
+				extensions = append(extensions, "AUTH PLAIN")
+			}
+
+			for i, ext := range extensions {
+				if i == len(extensions)-1 {
+					tc.PrintfLine("250 %s", ext)
+				} else {
+					tc.PrintfLine("250-%s", ext)
+				}
+			}
+		case "STARTTLS":
+			tc.PrintfLine("220 go ahead")
+
+			tlsConn := tls.Server(conn, s.tls)
+
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+
+			conn = tlsConn
+			tc = textproto.NewConn(conn)
+			msg = receivedMessage{TLS: true}
+		case "AUTH":
+			fields := strings.Fields(arg)
+
+			if len(fields) != 2 || fields[0] != "PLAIN" {
+				tc.PrintfLine("504 unsupported")
+				continue
+			}
+
+			creds, _ := base64.StdEncoding.DecodeString(fields[1])
+			parts := strings.Split(string(creds), "\x00")
+
+			if len(parts) == 3 && parts[1] == s.username && parts[2] == s.password {
+				msg.Username = parts[1]
+				tc.PrintfLine("235 ok")
+			} else {
+				tc.PrintfLine("535 bad credentials")
+			}
+		case "MAIL":
+			if s.username != "" && msg.Username == "" {
+				tc.PrintfLine("530 authentication required")
+				continue
+			}
+
+			// Ignoring parameters like BODY=8BITMIME.
+			msg.From = strings.Trim(strings.Fields(strings.TrimPrefix(arg, "FROM:"))[0], "<>")
+			tc.PrintfLine("250 ok")
+		case "RCPT":
+			to := strings.Trim(strings.TrimPrefix(arg, "TO:"), "<>")
+
+			if s.rejected[to] {
+				tc.PrintfLine("550 no such user")
+				continue
+			}
+
+			msg.To = append(msg.To, to)
+			tc.PrintfLine("250 ok")
+		case "DATA":
+			tc.PrintfLine("354 go ahead")
+
+			data, err := tc.ReadDotBytes()
+
+			if err != nil {
+				return
+			}
+
+			msg.Data = string(data)
+
+			s.Lock()
+			s.received = append(s.received, msg)
+			s.Unlock()
+
+			msg = receivedMessage{TLS: msg.TLS, Username: msg.Username}
+			tc.PrintfLine("250 queued")
+		case "RSET", "NOOP":
+			tc.PrintfLine("250 ok")
+		case "QUIT":
+			tc.PrintfLine("221 bye")
+			return
+		default:
+			tc.PrintfLine("502 unknown command")
+		}
+	}
+}
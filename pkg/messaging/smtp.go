@@ -0,0 +1,230 @@
+package messaging
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/logs"
+)
+
+var logger = logs.WithPackage("messaging")
+
+// How long the whole conversation with the SMTP server gets.
+const sendTimeout = time.Minute
+
+// ErrTLSRequired is the server not offering STARTTLS when we won't send
+// without it.
+var ErrTLSRequired = errors.New("messaging: server doesn't support STARTTLS")
+
+// Options describe how to reach the SMTP server.
+type Options struct {
+	// The server's address, like smtp.example.com:587.
+	Addr string
+
+	// Credentials for AUTH PLAIN. We don't log in if Username is empty.
+	Username string
+	Password string
+
+	// Who the email is from, like "What Day Is It? <days@example.com>".
+	From string
+
+	// Refuses to send anything if the server doesn't support STARTTLS.
+	// Servers that do support it are always used with it.
+	RequireTLS bool
+
+	// Used for STARTTLS. Nil checks the server's certificate against the
+	// host in Addr.
+	TLSConfig *tls.Config
+}
+
+// Error is the SMTP server refusing a recipient.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("messaging: %d %s", e.Code, e.Message)
+}
+
+// Undeliverable indicates that the mailbox doesn't exist or won't ever take
+// mail from us, as opposed to being full or busy.
+func (e *Error) Undeliverable() bool {
+	switch e.Code {
+	case 550, 551, 553:
+		return true
+	default:
+		return false
+	}
+}
+
+// Sender delivers messages by email.
+type Sender struct {
+	Options Options
+
+	TextTemplate *texttemplate.Template
+	HTMLTemplate *htmltemplate.Template
+
+	// UnsubscribeURL returns where the owner of an address goes to stop
+	// getting email. Email doesn't have List-Unsubscribe headers if it's
+	// nil or returns nothing.
+	UnsubscribeURL func(to string) string
+}
+
+func NewSender(opts Options) *Sender {
+	return &Sender{
+		Options:      opts,
+		TextTemplate: DefaultTextTemplate,
+		HTMLTemplate: DefaultHTMLTemplate,
+	}
+}
+
+// Compose renders body in to an email for to.
+func (s *Sender) Compose(to, body string) (Message, error) {
+	data := TemplateData{Body: body}
+
+	if s.UnsubscribeURL != nil {
+		data.UnsubscribeURL = s.UnsubscribeURL(to)
+	}
+
+	var text, html bytes.Buffer
+
+	if err := s.TextTemplate.Execute(&text, data); err != nil {
+		return Message{}, err
+	}
+
+	if err := s.HTMLTemplate.Execute(&html, data); err != nil {
+		return Message{}, err
+	}
+
+	m := Message{
+		From:    s.Options.From,
+		To:      to,
+		Subject: subjectFor(body),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}
+
+	if data.UnsubscribeURL != "" {
+		// RFC 8058 one-click unsubscribe, which POSTs to the URL.
+		m.Header = map[string]string{
+			"List-Unsubscribe":      "<" + data.UnsubscribeURL + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		}
+	}
+
+	return m, nil
+}
+
+// Send emails body to to.
+func (s *Sender) Send(to, body string) error {
+	m, err := s.Compose(to, body)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to render email")
+		return err
+	}
+
+	if err := s.Deliver(m); err != nil {
+		logger.WithError(err).Error("failed to send email")
+		return err
+	}
+
+	logger.Info("email sent")
+	return nil
+}
+
+// Deliver hands m to the SMTP server.
+func (s *Sender) Deliver(m Message) error {
+	from, err := mail.ParseAddress(m.From)
+
+	if err != nil {
+		return fmt.Errorf("messaging: invalid from address: %v", err)
+	}
+
+	data, err := m.Bytes()
+
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(s.Options.Addr)
+
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", s.Options.Addr, sendTimeout)
+
+	if err != nil {
+		return err
+	}
+
+	conn.SetDeadline(time.Now().Add(sendTimeout))
+
+	c, err := smtp.NewClient(conn, host)
+
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		config := s.Options.TLSConfig
+
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+
+		if err := c.StartTLS(config); err != nil {
+			return err
+		}
+	} else if s.Options.RequireTLS {
+		return ErrTLSRequired
+	}
+
+	if s.Options.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.Options.Username, s.Options.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(from.Address); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(m.To); err != nil {
+		if terr, ok := err.(*textproto.Error); ok {
+			return &Error{terr.Code, terr.Msg}
+		}
+
+		return err
+	}
+
+	w, err := c.Data()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
@@ -0,0 +1,159 @@
+package messaging
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSender(s *testSMTPServer, config *tls.Config) *Sender {
+	sender := NewSender(Options{
+		Addr:      s.addr(),
+		From:      "What Day Is It? <days@example.com>",
+		TLSConfig: config,
+	})
+
+	sender.UnsubscribeURL = func(to string) string {
+		return "https://example.com/unsubscribe?token=" + to
+	}
+
+	return sender
+}
+
+// readParts pulls the plain text and HTML bodies out of an email.
+func readParts(t *testing.T, msg *mail.Message) map[string]string {
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+
+	if !assert.NoError(t, err) || !assert.Equal(t, "multipart/alternative", mediaType) {
+		return nil
+	}
+
+	parts := make(map[string]string)
+	r := multipart.NewReader(msg.Body, params["boundary"])
+
+	for {
+		part, err := r.NextPart()
+
+		if err != nil {
+			break
+		}
+
+		// The multipart reader decodes quoted-printable for us.
+		body, _ := ioutil.ReadAll(part)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[contentType] = string(body)
+	}
+
+	return parts
+}
+
+func TestSenderSend(t *testing.T) {
+	cert, pool := newTestCertificate(t)
+
+	s := newTestSMTPServer(t, &tls.Config{Certificates: []tls.Certificate{cert}})
+	s.username, s.password = "days", "sekrit"
+	defer s.close()
+
+	sender := newTestSender(s, &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"})
+	sender.Options.Username, sender.Options.Password = "days", "sekrit"
+	sender.Options.RequireTLS = true
+
+	if !assert.NoError(t, sender.Send("someone@example.com", "Today is Tuesday")) {
+		return
+	}
+
+	received := s.Received()
+
+	if !assert.Len(t, received, 1) {
+		return
+	}
+
+	assert.True(t, received[0].TLS)
+	assert.Equal(t, "days", received[0].Username)
+	assert.Equal(t, "days@example.com", received[0].From)
+	assert.Equal(t, []string{"someone@example.com"}, received[0].To)
+
+	msg, err := mail.ReadMessage(strings.NewReader(received[0].Data))
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "What Day Is It? <days@example.com>", msg.Header.Get("From"))
+	assert.Equal(t, "someone@example.com", msg.Header.Get("To"))
+	assert.Equal(t, "Today is Tuesday", msg.Header.Get("Subject"))
+	assert.Equal(t, "<https://example.com/unsubscribe?token=someone@example.com>", msg.Header.Get("List-Unsubscribe"))
+	assert.Equal(t, "List-Unsubscribe=One-Click", msg.Header.Get("List-Unsubscribe-Post"))
+	assert.Regexp(t, `^<[0-9a-f]{32}@example\.com>$`, msg.Header.Get("Message-ID"))
+
+	parts := readParts(t, msg)
+	assert.True(t, strings.HasPrefix(parts["text/plain"], "Today is Tuesday\n"))
+	assert.Contains(t, parts["text/plain"], "Unsubscribe: https://example.com/unsubscribe?token=someone@example.com")
+	assert.Contains(t, parts["text/html"], ">Today is Tuesday</p>")
+	assert.Contains(t, parts["text/html"], `<a href="https://example.com/unsubscribe?token=someone@example.com">Unsubscribe</a>`)
+}
+
+func TestSenderWithoutTLS(t *testing.T) {
+	s := newTestSMTPServer(t, nil)
+	defer s.close()
+
+	sender := newTestSender(s, nil)
+	sender.UnsubscribeURL = nil
+
+	assert.NoError(t, sender.Send("someone@example.com", "Your What Day Is It code is 123456. It expires in 10 minutes. If you didn't ask for it, just ignore this."))
+
+	if received := s.Received(); assert.Len(t, received, 1) {
+		assert.False(t, received[0].TLS)
+
+		msg, err := mail.ReadMessage(strings.NewReader(received[0].Data))
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, DefaultSubject, msg.Header.Get("Subject"))
+			assert.Empty(t, msg.Header.Get("List-Unsubscribe"))
+			assert.Contains(t, readParts(t, msg)["text/plain"], "Your What Day Is It code is 123456.")
+		}
+	}
+
+	sender.Options.RequireTLS = true
+	assert.Equal(t, ErrTLSRequired, sender.Send("someone@example.com", "Today is Tuesday"))
+	assert.Len(t, s.Received(), 1)
+}
+
+func TestSenderRejectedRecipient(t *testing.T) {
+	s := newTestSMTPServer(t, nil)
+	s.rejected["nobody@example.com"] = true
+	defer s.close()
+
+	err := newTestSender(s, nil).Send("nobody@example.com", "Today is Tuesday")
+
+	if assert.IsType(t, &Error{}, err) {
+		assert.Equal(t, 550, err.(*Error).Code)
+		assert.True(t, err.(*Error).Undeliverable())
+	}
+
+	assert.False(t, (&Error{452, "mailbox full"}).Undeliverable())
+	assert.Empty(t, s.Received())
+}
+
+func TestSenderBadPassword(t *testing.T) {
+	s := newTestSMTPServer(t, nil)
+	s.username, s.password = "days", "sekrit"
+	defer s.close()
+
+	sender := newTestSender(s, nil)
+	sender.Options.Username, sender.Options.Password = "days", "nope"
+
+	assert.Error(t, sender.Send("someone@example.com", "Today is Tuesday"))
+	assert.Empty(t, s.Received())
+}
+
+func TestMessageHeaderInjection(t *testing.T) {
+	_, err := Message{From: "days@example.com", To: "someone@example.com\r\nBcc: everyone@example.com"}.Bytes()
+	assert.Error(t, err)
+}
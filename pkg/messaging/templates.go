@@ -0,0 +1,53 @@
+package messaging
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// DefaultSubject is the subject of anything too long to be its own subject.
+const DefaultSubject = "What day is it?"
+
+// Subjects longer than this get cut off in most inboxes.
+const maxSubjectLength = 60
+
+// TemplateData is what the email templates get.
+type TemplateData struct {
+	// The message, like "Today is Tuesday".
+	Body string
+
+	// Where to go to stop getting email. Empty if there isn't anywhere.
+	UnsubscribeURL string
+}
+
+var DefaultTextTemplate = texttemplate.Must(texttemplate.New("text").Parse(`{{.Body}}
+{{if .UnsubscribeURL}}
+--
+You're getting this because you subscribed to What Day Is It?
+Unsubscribe: {{.UnsubscribeURL}}
+{{end}}`))
+
+var DefaultHTMLTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>What Day Is It?</title>
+</head>
+<body style="font-family: sans-serif;">
+<p style="font-size: 24px;">{{.Body}}</p>
+{{if .UnsubscribeURL}}<p style="font-size: 12px; color: #888888;">You're getting this because you subscribed to What Day Is It? <a href="{{.UnsubscribeURL}}">Unsubscribe</a></p>{{end}}
+</body>
+</html>
+`))
+
+// subjectFor picks a subject for body. Short messages like "Today is Tuesday"
+// are their own subject, so people don't even have to open them.
+func subjectFor(body string) string {
+	if len(body) > maxSubjectLength || strings.ContainsAny(body, "\r\n") {
+		return DefaultSubject
+	}
+
+	return body
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// Channel is how a subscriber gets their messages.
+type Channel string
+
+const (
+	ChannelSMS      Channel = "sms"
+	ChannelSlack    Channel = "slack"
+	ChannelDiscord  Channel = "discord"
+	ChannelTelegram Channel = "telegram"
+	ChannelEmail    Channel = "email"
+	ChannelWebhook  Channel = "webhook"
+	ChannelWhatsApp Channel = "whatsapp"
+	ChannelVoice    Channel = "voice"
+)
+
+var channels = map[Channel]bool{
+	ChannelSMS:      true,
+	ChannelSlack:    true,
+	ChannelDiscord:  true,
+	ChannelTelegram: true,
+	ChannelEmail:    true,
+	ChannelWebhook:  true,
+	ChannelWhatsApp: true,
+	ChannelVoice:    true,
+}
+
+const whatsAppPrefix = "whatsapp:"
+
+// TakesKeywords indicates that subscribers on c can send us START and STOP
+// themselves. Everybody else has to come back through the form.
+func (c Channel) TakesKeywords() bool {
+	return c == ChannelSMS || c == ChannelTelegram || c == ChannelWhatsApp
+}
+
+var channelidexp = regexp.MustCompile(`^([a-z]+):[0-9a-f]{16}$`)
+
+// ParseChannel turns str in to a channel that we know how to deliver on. An
+// empty string is SMS.
+func ParseChannel(str string) (Channel, bool) {
+	if str == "" {
+		return ChannelSMS, true
+	}
+
+	channel := Channel(strings.ToLower(str))
+	return channel, channels[channel]
+}
+
+// SubscriberID is what stands in for the phone number of a subscriber on
+// channel at address. It's the cleaned up number for SMS and the cleaned up
+// address for email, and something like slack:0123456789abcdef for everything
+// else so that addresses, which can be secrets, don't end up in URLs and logs.
+func SubscriberID(channel Channel, address string) string {
+	switch channel {
+	case ChannelSMS, "":
+		return CleanPhoneNumber(address)
+	case ChannelEmail:
+		return CleanEmailAddress(address)
+	case ChannelWhatsApp:
+		address = WhatsAppAddress(address)
+	case ChannelVoice:
+		address = CleanPhoneNumber(address)
+	}
+
+	sum := sha256.Sum256([]byte(address))
+	return string(channel) + ":" + hex.EncodeToString(sum[:8])
+}
+
+// CleanSubscriberID is CleanPhoneNumber for IDs that might be email
+// addresses or for other channels, which are left alone.
+func CleanSubscriberID(id string) string {
+	if IsEmailAddress(id) {
+		return CleanEmailAddress(id)
+	} else if channelidexp.MatchString(id) {
+		return id
+	}
+
+	return CleanPhoneNumber(id)
+}
+
+// WhatsAppAddress returns number the way Twilio writes WhatsApp addresses,
+// like whatsapp:+14155551234.
+func WhatsAppAddress(number string) string {
+	return whatsAppPrefix + CleanPhoneNumber(strings.TrimPrefix(number, whatsAppPrefix))
+}
+
+// IsWhatsAppAddress indicates that address is on WhatsApp instead of SMS.
+func IsWhatsAppAddress(address string) bool {
+	return strings.HasPrefix(address, whatsAppPrefix)
+}
+
+// IsValidSubscriberID indicates that id is a clean phone number or email
+// address, or the ID of a subscriber on another channel that we know about.
+func IsValidSubscriberID(id string) bool {
+	if IsEmailAddress(id) {
+		return IsCleanEmailAddress(id)
+	} else if m := channelidexp.FindStringSubmatch(id); m != nil {
+		return channels[Channel(m[1])] && Channel(m[1]) != ChannelSMS && Channel(m[1]) != ChannelEmail
+	}
+
+	return IsCleanPhoneNumber(id)
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// Somebody@somewhere.something, which is all we need on top of what
+// net/mail checks.
+var emailexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s.]+$`)
+
+// CleanEmailAddress is CleanPhoneNumber for email addresses. Case doesn't
+// matter to anybody's mailbox, so it's lowercased.
+func CleanEmailAddress(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// IsEmailAddress indicates that str is trying to be an email address rather
+// than a phone number or the ID of some other subscriber.
+func IsEmailAddress(str string) bool {
+	return strings.Contains(str, "@")
+}
+
+// IsCleanEmailAddress indicates that email is a bare address, with no name or
+// angle brackets, that's been through CleanEmailAddress.
+func IsCleanEmailAddress(email string) bool {
+	if email != CleanEmailAddress(email) || !emailexp.MatchString(email) {
+		return false
+	}
+
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Name == "" && addr.Address == email
+}
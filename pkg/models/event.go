@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// EventType is something that happened to a phone number that we want to be
+// able to look back on later.
+type EventType string
+
+const (
+	EventSubscribed      EventType = "subscribed"
+	EventConfirmed       EventType = "confirmed"
+	EventResubscribed    EventType = "resubscribed"
+	EventMessageSent     EventType = "message_sent"
+	EventDeliveryFailed  EventType = "delivery_failed"
+	EventStopReceived    EventType = "stop_received"
+	EventPaused          EventType = "paused"
+	EventResumed         EventType = "resumed"
+	EventTimezoneChanged EventType = "timezone_changed"
+	EventScheduleChanged EventType = "schedule_changed"
+	EventUnsubscribed    EventType = "unsubscribed"
+	EventAdminAction     EventType = "admin_action"
+	EventWebhookAttempt  EventType = "webhook_attempt"
+)
+
+const (
+	// ActorSubscriber is the owner of the phone number, texting us.
+	ActorSubscriber = "subscriber"
+
+	// ActorWeb is whoever filled out the subscribe form. It might not be the
+	// owner of the number.
+	ActorWeb = "web"
+
+	// ActorSystem is us, usually the delivery run.
+	ActorSystem = "system"
+)
+
+// AdminActor is the actor for something an admin did from number.
+func AdminActor(number string) string {
+	return "admin:" + number
+}
+
+// Event is an entry in a phone number's history. Events are never changed
+// once they're recorded.
+type Event struct {
+	Number string
+	Type   EventType
+	At     time.Time
+
+	// Who made this happen. One of the Actor constants, or AdminActor.
+	Actor string
+
+	// Anything else that's useful to know, like the old and new timezone.
+	Metadata map[string]string
+}
@@ -1,8 +1,12 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"regexp"
 	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
 )
 
 var phoneexp = regexp.MustCompile(`[^\+0-9]`)
@@ -12,8 +16,104 @@ type PhoneNumber struct {
 	Number       string
 	Timezone     string
 	LastSentAt   *time.Time
-	IsSendable   bool
+	Status       Status
 	SendDeadline *time.Time
+
+	// The last time the number entered each status.
+	StatusChangedAt map[Status]time.Time
+
+	// Set when we picked Timezone ourselves instead of the user telling us.
+	TimezoneGuessed bool
+
+	// The last time someone signed this number up again after it was created.
+	ResubscribedAt *time.Time
+
+	// Set while we're waiting for the number to confirm its subscription. The
+	// record is thrown away if it isn't confirmed by then.
+	ConfirmationExpiresAt *time.Time
+
+	// Set while the number is paused and we know when to pick back up. Paused
+	// numbers without one stay paused until they say RESUME.
+	PausedUntil *time.Time
+
+	// When the number gets its message. Nil means clock.DefaultSchedule.
+	Schedule *clock.Schedule
+
+	// How the subscriber gets their message. Empty means ChannelSMS, in which
+	// case Number is a phone number. Otherwise it's from SubscriberID.
+	Channel Channel
+
+	// Where messages go on channels other than SMS, like a Slack incoming
+	// webhook URL.
+	Address string
+
+	// Signs what we post to webhook subscriptions, so receivers know that
+	// it came from us.
+	WebhookSecret string
+
+	// The last time the subscriber messaged us. WhatsApp only lets us send
+	// free-form messages for a day after that.
+	LastReceivedAt *time.Time
+}
+
+// DeliveryChannel returns how the subscriber gets their message.
+func (p PhoneNumber) DeliveryChannel() Channel {
+	if p.Channel == "" {
+		return ChannelSMS
+	}
+
+	return p.Channel
+}
+
+// DeliveryAddress returns where on its channel the subscriber's messages go.
+func (p PhoneNumber) DeliveryAddress() string {
+	if p.DeliveryChannel() == ChannelSMS {
+		return p.Number
+	}
+
+	return p.Address
+}
+
+// DeliverySchedule returns when the number gets its message.
+func (p PhoneNumber) DeliverySchedule() clock.Schedule {
+	if p.Schedule == nil {
+		return clock.DefaultSchedule
+	}
+
+	return *p.Schedule
+}
+
+// IsSendable indicates that the number should get today's message.
+func (p PhoneNumber) IsSendable() bool {
+	return p.Status == StatusActive
+}
+
+// IsAwaitingConfirmation indicates that the subscription hasn't been confirmed.
+func (p PhoneNumber) IsAwaitingConfirmation() bool {
+	return p.Status == StatusPending
+}
+
+// IsExpired indicates that the subscription was never confirmed and it's too
+// late to do so now.
+func (p PhoneNumber) IsExpired(now *time.Time) bool {
+	return p.Status == StatusPending && p.ConfirmationExpiresAt != nil && !now.Before(*p.ConfirmationExpiresAt)
+}
+
+// IsPauseOver indicates that the number is paused but it's time to start
+// sending to it again.
+func (p PhoneNumber) IsPauseOver(now *time.Time) bool {
+	return p.Status == StatusPaused && p.PausedUntil != nil && !now.Before(*p.PausedUntil)
+}
+
+// NewWebhookSecret makes up a secret for a webhook subscription.
+func NewWebhookSecret() string {
+	buf := make([]byte, 32)
+
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+
+	return "whsec_" + hex.EncodeToString(buf)
 }
 
 func CleanPhoneNumber(number string) string {
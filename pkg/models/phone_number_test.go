@@ -34,3 +34,64 @@ func TestIsCleanPhoneNumber(t *testing.T) {
 	assert.False(t, IsCleanPhoneNumber("+5554443333"))
 	assert.False(t, IsCleanPhoneNumber("Hello, World!"))
 }
+
+func TestSubscriberID(t *testing.T) {
+	assert.Equal(t, "+15554443333", SubscriberID(ChannelSMS, "555 444 3333"))
+
+	id := SubscriberID(ChannelSlack, "https://hooks.slack.com/services/T000/B000/XXXX")
+	assert.Regexp(t, `^slack:[0-9a-f]{16}$`, id)
+	assert.NotEqual(t, id, SubscriberID(ChannelSlack, "https://hooks.slack.com/services/T000/B000/YYYY"))
+
+	assert.Equal(t, id, CleanSubscriberID(id))
+	assert.True(t, IsValidSubscriberID(id))
+	assert.True(t, IsValidSubscriberID("+15554443333"))
+	assert.False(t, IsValidSubscriberID("sms:0123456789abcdef"))
+	assert.False(t, IsValidSubscriberID("fax:0123456789abcdef"))
+	assert.False(t, IsValidSubscriberID("slack:nope"))
+	assert.False(t, IsValidSubscriberID("email:0123456789abcdef"))
+
+	assert.Equal(t, "someone@example.com", SubscriberID(ChannelEmail, " Someone@Example.com"))
+	assert.Equal(t, "someone@example.com", CleanSubscriberID("Someone@Example.com"))
+	assert.True(t, IsValidSubscriberID("someone@example.com"))
+	assert.False(t, IsValidSubscriberID("Someone@example.com"))
+
+	id = SubscriberID(ChannelWhatsApp, "whatsapp:+15554443333")
+	assert.Regexp(t, `^whatsapp:[0-9a-f]{16}$`, id)
+	assert.Equal(t, id, SubscriberID(ChannelWhatsApp, "+1 555 444 3333"))
+	assert.True(t, IsValidSubscriberID(id))
+	assert.Equal(t, "whatsapp:+15554443333", WhatsAppAddress("555-444-3333"))
+
+	id = SubscriberID(ChannelVoice, "+15554443333")
+	assert.Regexp(t, `^voice:[0-9a-f]{16}$`, id)
+	assert.Equal(t, id, SubscriberID(ChannelVoice, "555 444 3333"))
+	assert.True(t, IsValidSubscriberID(id))
+}
+
+func TestIsCleanEmailAddress(t *testing.T) {
+	cases := map[string]bool{
+		"someone@example.com":           true,
+		"some.one+days@mail.example.io": true,
+		"Someone@example.com":           false,
+		"someone@example":               false,
+		"someone@@example.com":          false,
+		"some one@example.com":          false,
+		"Someone <someone@example.com>": false,
+		"@example.com":                  false,
+		"someone@example.com.":          false,
+		"":                              false,
+	}
+
+	for email, expected := range cases {
+		assert.Equal(t, expected, IsCleanEmailAddress(email), email)
+	}
+}
+
+func TestDeliveryAddress(t *testing.T) {
+	sms := PhoneNumber{Number: "+15554443333"}
+	assert.Equal(t, ChannelSMS, sms.DeliveryChannel())
+	assert.Equal(t, "+15554443333", sms.DeliveryAddress())
+
+	slack := PhoneNumber{Number: "slack:0123456789abcdef", Channel: ChannelSlack, Address: "https://hooks.slack.com/services/T000/B000/XXXX"}
+	assert.Equal(t, ChannelSlack, slack.DeliveryChannel())
+	assert.Equal(t, "https://hooks.slack.com/services/T000/B000/XXXX", slack.DeliveryAddress())
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// Status is where a phone number is in its subscription lifecycle.
+type Status string
+
+const (
+	// StatusPending numbers signed up but haven't confirmed yet.
+	StatusPending Status = "pending"
+
+	// StatusActive numbers get a text every morning.
+	StatusActive Status = "active"
+
+	// StatusPaused numbers are still subscribed but have asked us to hold off
+	// for a while.
+	StatusPaused Status = "paused"
+
+	// StatusStopped numbers opted out with STOP or one of its friends.
+	StatusStopped Status = "stopped"
+
+	// StatusBounced numbers were rejected by the carrier.
+	StatusBounced Status = "bounced"
+
+	// StatusBanned numbers were shut off by an admin.
+	StatusBanned Status = "banned"
+)
+
+var ErrInvalidTransition = errors.New("models: invalid status transition")
+
+// transitions lists every status that a phone number is allowed to move to
+// from each status. This is the only place that decides that.
+var transitions = map[Status][]Status{
+	StatusPending: {StatusActive, StatusStopped, StatusBanned},
+	StatusActive:  {StatusPaused, StatusStopped, StatusBounced, StatusBanned},
+	StatusPaused:  {StatusActive, StatusStopped, StatusBounced, StatusBanned},
+	StatusStopped: {StatusActive, StatusBanned},
+	StatusBounced: {StatusActive, StatusStopped, StatusBanned},
+
+	// Lifting a ban doesn't opt anyone back in; they have to do that themselves.
+	StatusBanned: {StatusStopped},
+}
+
+// CanTransitionTo indicates whether a phone number may move from s to status.
+func (s Status) CanTransitionTo(status Status) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsValid indicates whether s is one of the statuses we know about.
+func (s Status) IsValid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// Transition moves the phone number to status and records when that happened.
+// Moving to the status it's already in is a no-op.
+func (p *PhoneNumber) Transition(status Status, at *time.Time) error {
+	if p.Status == status {
+		return nil
+	}
+
+	if !p.Status.CanTransitionTo(status) {
+		return ErrInvalidTransition
+	}
+
+	if p.StatusChangedAt == nil {
+		p.StatusChangedAt = make(map[Status]time.Time)
+	} else {
+		// Don't scribble on a map that a copy of this record might share.
+		changedAt := make(map[Status]time.Time, len(p.StatusChangedAt)+1)
+
+		for k, v := range p.StatusChangedAt {
+			changedAt[k] = v
+		}
+
+		p.StatusChangedAt = changedAt
+	}
+
+	p.Status = status
+	p.StatusChangedAt[status] = *at
+
+	// Only pending numbers expire.
+	if status != StatusPending {
+		p.ConfirmationExpiresAt = nil
+	}
+
+	if status != StatusPaused {
+		p.PausedUntil = nil
+	}
+
+	return nil
+}
+
+// Pause moves the phone number to StatusPaused until the given time, or until
+// it resumes on its own if until is nil. Pausing a paused number just moves
+// the date.
+func (p *PhoneNumber) Pause(until, at *time.Time) error {
+	if err := p.Transition(StatusPaused, at); err != nil {
+		return err
+	}
+
+	p.PausedUntil = until
+	return nil
+}
+
+// StatusSince returns when the phone number entered its current status, or nil
+// if we don't know.
+func (p PhoneNumber) StatusSince() *time.Time {
+	if t, ok := p.StatusChangedAt[p.Status]; ok {
+		return &t
+	}
+
+	return nil
+}
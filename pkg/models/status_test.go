@@ -0,0 +1,80 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from    Status
+		to      Status
+		allowed bool
+	}{
+		{StatusPending, StatusActive, true},
+		{StatusPending, StatusPaused, false},
+		{StatusActive, StatusPaused, true},
+		{StatusActive, StatusPending, false},
+		{StatusPaused, StatusActive, true},
+		{StatusStopped, StatusActive, true},
+		{StatusStopped, StatusPaused, false},
+		{StatusBounced, StatusActive, true},
+		{StatusBanned, StatusActive, false},
+		{StatusBanned, StatusStopped, true},
+		{Status("bogus"), StatusActive, false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.allowed, test.from.CanTransitionTo(test.to), "%s -> %s", test.from, test.to)
+	}
+}
+
+func TestPhoneNumberTransition(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Hour)
+
+	num := PhoneNumber{Status: StatusPending, ConfirmationExpiresAt: &later}
+
+	assert.NoError(t, num.Transition(StatusActive, &now))
+	assert.Equal(t, StatusActive, num.Status)
+	assert.Equal(t, now, num.StatusChangedAt[StatusActive])
+	assert.Nil(t, num.ConfirmationExpiresAt)
+
+	// Staying put doesn't count as a change.
+	assert.NoError(t, num.Transition(StatusActive, &later))
+	assert.Equal(t, now, num.StatusChangedAt[StatusActive])
+
+	assert.Equal(t, ErrInvalidTransition, num.Transition(StatusPending, &later))
+	assert.Equal(t, StatusActive, num.Status)
+
+	// Copies don't see each other's changes.
+	cp := num
+	assert.NoError(t, cp.Transition(StatusStopped, &later))
+	assert.Equal(t, StatusActive, num.Status)
+	assert.NotContains(t, num.StatusChangedAt, StatusStopped)
+}
+
+func TestPhoneNumberPause(t *testing.T) {
+	now := time.Now()
+	later := now.Add(24 * time.Hour)
+
+	num := PhoneNumber{Status: StatusActive}
+
+	assert.NoError(t, num.Pause(&later, &now))
+	assert.Equal(t, StatusPaused, num.Status)
+	assert.Equal(t, &later, num.PausedUntil)
+	assert.False(t, num.IsPauseOver(&now))
+	assert.True(t, num.IsPauseOver(&later))
+
+	// An open-ended pause never runs out.
+	assert.NoError(t, num.Pause(nil, &now))
+	assert.False(t, num.IsPauseOver(&later))
+
+	assert.NoError(t, num.Transition(StatusActive, &later))
+	assert.Nil(t, num.PausedUntil)
+
+	stopped := PhoneNumber{Status: StatusStopped}
+	assert.Equal(t, ErrInvalidTransition, stopped.Pause(&later, &now))
+}
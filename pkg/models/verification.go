@@ -0,0 +1,71 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// Verification is a one-time code that we texted to a phone number to prove
+// that whoever is filling out the subscribe form actually owns it. We only
+// ever keep a salted hash of the code.
+type Verification struct {
+	Number    string
+	CodeHash  string
+	Salt      string
+	Attempts  int
+	ExpiresAt *time.Time
+
+	// What the subscribe request asked for, which only takes effect once the
+	// code checks out. Empty means to leave it alone.
+	Timezone     string
+	DeliveryTime string
+}
+
+func hashVerificationCode(code, salt string) string {
+	sum := sha256.Sum256([]byte(salt + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewVerification generates a random six digit code for number. The code is
+// returned so that it can be sent; it can't be recovered from the record.
+func NewVerification(number string, expiresAt *time.Time) (Verification, string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+
+	if err != nil {
+		return Verification{}, "", err
+	}
+
+	salt := make([]byte, 16)
+
+	if _, err := rand.Read(salt); err != nil {
+		return Verification{}, "", err
+	}
+
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	verification := Verification{
+		Number:    number,
+		Salt:      hex.EncodeToString(salt),
+		ExpiresAt: expiresAt,
+	}
+
+	verification.CodeHash = hashVerificationCode(code, verification.Salt)
+
+	return verification, code, nil
+}
+
+// Matches checks code against the hashed code in constant time.
+func (v Verification) Matches(code string) bool {
+	hash := hashVerificationCode(code, v.Salt)
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(v.CodeHash)) == 1
+}
+
+// IsExpired indicates that it's too late to use this code.
+func (v Verification) IsExpired(now *time.Time) bool {
+	return v.ExpiresAt == nil || !now.Before(*v.ExpiresAt)
+}
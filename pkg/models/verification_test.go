@@ -0,0 +1,31 @@
+package models
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVerification(t *testing.T) {
+	expiresAt := time.Now().Add(time.Minute)
+
+	verification, code, err := NewVerification("+15554443333", &expiresAt)
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[0-9]{6}$`), code)
+	assert.NotContains(t, verification.CodeHash, code)
+
+	assert.True(t, verification.Matches(code))
+	assert.False(t, verification.Matches("1234567"))
+	assert.False(t, verification.Matches(""))
+}
+
+func TestVerificationIsExpired(t *testing.T) {
+	now := time.Now()
+	later := now.Add(time.Minute)
+
+	assert.False(t, Verification{ExpiresAt: &later}.IsExpired(&now))
+	assert.True(t, Verification{ExpiresAt: &now}.IsExpired(&now))
+	assert.True(t, Verification{}.IsExpired(&now))
+}
@@ -0,0 +1,225 @@
+package mqtt
+
+import (
+	"bufio"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testBroker is just enough of an MQTT broker to publish to. It keeps
+// retained messages and publishes wills, and can hang up on its clients.
+type testBroker struct {
+	sync.Mutex
+	ln       net.Listener
+	password string
+	retained map[string]string
+	conns    map[net.Conn]bool
+	connects int
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := &testBroker{
+		ln:       ln,
+		retained: make(map[string]string),
+		conns:    make(map[net.Conn]bool),
+	}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+
+			if err != nil {
+				return
+			}
+
+			go b.serve(conn)
+		}
+	}()
+
+	return b
+}
+
+func (b *testBroker) addr() string {
+	return b.ln.Addr().String()
+}
+
+func (b *testBroker) close() {
+	b.ln.Close()
+	b.kick()
+}
+
+// kick hangs up on everybody.
+func (b *testBroker) kick() {
+	b.Lock()
+	defer b.Unlock()
+
+	for conn := range b.conns {
+		conn.Close()
+	}
+}
+
+func (b *testBroker) get(topic string) (string, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	payload, ok := b.retained[topic]
+	return payload, ok
+}
+
+func (b *testBroker) set(topic, payload string) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.retained[topic] = payload
+}
+
+func (b *testBroker) connectCount() int {
+	b.Lock()
+	defer b.Unlock()
+
+	return b.connects
+}
+
+// waitFor waits for cond to be true, since the broker does things in the
+// background.
+func waitFor(t *testing.T, cond func() bool, msg string) {
+	for deadline := time.Now().Add(2 * time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", msg)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type connectRequest struct {
+	clientID string
+	will     *Message
+	password string
+}
+
+func parseConnect(body []byte) (connectRequest, error) {
+	var req connectRequest
+
+	_, body, err := readString(body)
+
+	if err != nil || len(body) < 4 {
+		return req, errMalformed
+	}
+
+	flags := body[1]
+
+	if req.clientID, body, err = readString(body[4:]); err != nil {
+		return req, err
+	}
+
+	if flags&0x04 != 0 {
+		var topic, payload string
+
+		if topic, body, err = readString(body); err != nil {
+			return req, err
+		}
+
+		if payload, body, err = readString(body); err != nil {
+			return req, err
+		}
+
+		req.will = &Message{topic, []byte(payload), flags&0x20 != 0}
+	}
+
+	if flags&0x80 != 0 {
+		if _, body, err = readString(body); err != nil {
+			return req, err
+		}
+	}
+
+	if flags&0x40 != 0 {
+		if req.password, _, err = readString(body); err != nil {
+			return req, err
+		}
+	}
+
+	return req, nil
+}
+
+func (b *testBroker) serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	p, err := readPacket(r)
+
+	if err != nil || p.kind != packetConnect {
+		return
+	}
+
+	req, err := parseConnect(p.body)
+
+	if err != nil {
+		return
+	}
+
+	if req.password != b.password {
+		writePacket(conn, packetConnack, 0, []byte{0, 4})
+		return
+	}
+
+	b.Lock()
+	b.conns[conn] = true
+	b.connects++
+	b.Unlock()
+
+	defer func() {
+		b.Lock()
+		delete(b.conns, conn)
+		b.Unlock()
+	}()
+
+	writePacket(conn, packetConnack, 0, []byte{0, 0})
+
+	for {
+		p, err := readPacket(r)
+
+		if err != nil {
+			if req.will != nil && req.will.Retain {
+				b.set(req.will.Topic, string(req.will.Payload))
+			}
+
+			return
+		}
+
+		switch p.kind {
+		case packetPublish:
+			topic, body, err := readString(p.body)
+
+			if err != nil {
+				return
+			}
+
+			if qos := p.flags >> 1 & 0x03; qos > 0 {
+				var id uint16
+
+				if id, body, err = readUint16(body); err != nil {
+					return
+				}
+
+				writePacket(conn, packetPuback, 0, appendUint16(nil, id))
+			}
+
+			if p.flags&0x01 != 0 {
+				b.set(topic, string(body))
+			}
+		case packetPingreq:
+			writePacket(conn, packetPingresp, 0, nil)
+		case packetDisconnect:
+			return
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package mqtt
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/logs"
+)
+
+var logger = logs.WithPackage("mqtt")
+
+// DefaultKeepAlive is how often we let the broker know that we're still
+// around when there's nothing to publish.
+const DefaultKeepAlive = 60 * time.Second
+
+// How long the broker has to answer us before we give up on it.
+const responseTimeout = 10 * time.Second
+
+// Message is something to publish.
+type Message struct {
+	Topic   string
+	Payload []byte
+
+	// Retained messages are kept by the broker and handed to anybody that
+	// subscribes later.
+	Retain bool
+}
+
+// Options describe how to connect to a broker.
+type Options struct {
+	// The broker's address, like "localhost:1883" or "tcp://localhost:1883".
+	Broker string
+
+	// Has to be different for everything connected to the broker, or the
+	// broker disconnects whoever was there first. See UniqueClientID.
+	ClientID string
+
+	Username string
+
+	// MQTT only allows a password along with a user name.
+	Password string
+
+	KeepAlive time.Duration
+
+	// Will is published by the broker if we go away without disconnecting.
+	Will *Message
+}
+
+var errPasswordWithoutUsername = errors.New("mqtt: a password needs a user name")
+
+// UniqueClientID adds a random suffix to prefix so that more than one copy of
+// the server can be connected at once. It stays within the 23 characters that
+// every broker has to accept if prefix is 14 characters or less.
+func UniqueClientID(prefix string) string {
+	buf := make([]byte, 4)
+
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+
+	return prefix + "-" + hex.EncodeToString(buf)
+}
+
+// ConnectError is the broker refusing to let us connect.
+type ConnectError struct {
+	Code byte
+}
+
+var connectErrorMessages = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+func (e *ConnectError) Error() string {
+	if msg, ok := connectErrorMessages[e.Code]; ok {
+		return "mqtt: connection refused, " + msg
+	}
+
+	return fmt.Sprintf("mqtt: connection refused with code %d", e.Code)
+}
+
+// Client publishes messages to an MQTT 3.1.1 broker. It doesn't subscribe to
+// anything, and isn't safe to use from more than one goroutine.
+type Client struct {
+	conn     net.Conn
+	r        *bufio.Reader
+	packetID uint16
+}
+
+func connectPacket(opts Options) []byte {
+	var flags byte = 0x02 // clean session
+
+	if opts.Will != nil {
+		flags |= 0x04 | 0x01<<3 // at least once
+
+		if opts.Will.Retain {
+			flags |= 0x20
+		}
+	}
+
+	if opts.Username != "" {
+		flags |= 0x80
+	}
+
+	if opts.Password != "" {
+		flags |= 0x40
+	}
+
+	keepAlive := opts.KeepAlive
+
+	if keepAlive == 0 {
+		keepAlive = DefaultKeepAlive
+	}
+
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags)
+	body = appendUint16(body, uint16(keepAlive/time.Second))
+	body = appendString(body, opts.ClientID)
+
+	if opts.Will != nil {
+		body = appendString(body, opts.Will.Topic)
+		body = appendString(body, string(opts.Will.Payload))
+	}
+
+	if opts.Username != "" {
+		body = appendString(body, opts.Username)
+	}
+
+	if opts.Password != "" {
+		body = appendString(body, opts.Password)
+	}
+
+	return body
+}
+
+// Dial connects to the broker.
+func Dial(opts Options) (*Client, error) {
+	if opts.Password != "" && opts.Username == "" {
+		return nil, errPasswordWithoutUsername
+	}
+
+	addr := strings.TrimPrefix(opts.Broker, "tcp://")
+	conn, err := net.DialTimeout("tcp", addr, responseTimeout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: conn, r: bufio.NewReader(conn)}
+
+	if err := c.write(packetConnect, 0, connectPacket(opts)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	p, err := c.read(packetConnack)
+
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if len(p.body) != 2 {
+		conn.Close()
+		return nil, errMalformed
+	} else if p.body[1] != 0 {
+		conn.Close()
+		return nil, &ConnectError{p.body[1]}
+	}
+
+	logger.WithField("broker", addr).Info("connected to MQTT broker")
+	return c, nil
+}
+
+func (c *Client) write(kind, flags byte, body []byte) error {
+	c.conn.SetWriteDeadline(time.Now().Add(responseTimeout))
+	return writePacket(c.conn, kind, flags, body)
+}
+
+// read waits for a packet of the given kind. We never subscribe to anything,
+// so nothing else should turn up.
+func (c *Client) read(kind byte) (packet, error) {
+	c.conn.SetReadDeadline(time.Now().Add(responseTimeout))
+
+	p, err := readPacket(c.r)
+
+	if err != nil {
+		return p, err
+	}
+
+	if p.kind != kind {
+		return p, fmt.Errorf("mqtt: expected packet type %d, got %d", kind, p.kind)
+	}
+
+	return p, nil
+}
+
+// Publish sends m to the broker and waits for the broker to say that it got
+// it.
+func (c *Client) Publish(m Message) error {
+	c.packetID++
+
+	if c.packetID == 0 {
+		c.packetID = 1
+	}
+
+	var flags byte = 0x01 << 1 // at least once
+
+	if m.Retain {
+		flags |= 0x01
+	}
+
+	body := appendString(nil, m.Topic)
+	body = appendUint16(body, c.packetID)
+	body = append(body, m.Payload...)
+
+	if err := c.write(packetPublish, flags, body); err != nil {
+		return err
+	}
+
+	p, err := c.read(packetPuback)
+
+	if err != nil {
+		return err
+	}
+
+	if id, _, err := readUint16(p.body); err != nil {
+		return err
+	} else if id != c.packetID {
+		return fmt.Errorf("mqtt: expected ack for %d, got %d", c.packetID, id)
+	}
+
+	return nil
+}
+
+// Ping checks that the broker is still there, which keeps the connection
+// alive.
+func (c *Client) Ping() error {
+	if err := c.write(packetPingreq, 0, nil); err != nil {
+		return err
+	}
+
+	_, err := c.read(packetPingresp)
+	return err
+}
+
+// Close disconnects from the broker, which won't publish our will.
+func (c *Client) Close() error {
+	c.write(packetDisconnect, 0, nil)
+	return c.conn.Close()
+}
@@ -0,0 +1,104 @@
+package mqtt
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacketRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 127, 128, 16383, 16384, maxReadLength} {
+		var buf bytes.Buffer
+
+		body := bytes.Repeat([]byte{'x'}, size)
+		assert.NoError(t, writePacket(&buf, packetPublish, 0x03, body))
+
+		p, err := readPacket(bufio.NewReader(&buf))
+		assert.NoError(t, err)
+		assert.Equal(t, byte(packetPublish), p.kind)
+		assert.Equal(t, byte(0x03), p.flags)
+		assert.Equal(t, size, len(p.body))
+	}
+}
+
+func TestReadPacketTooLarge(t *testing.T) {
+	var buf bytes.Buffer
+
+	// Only the header, since we shouldn't get as far as reading the body.
+	writePacket(&buf, packetPublish, 0, make([]byte, maxReadLength+1))
+
+	_, err := readPacket(bufio.NewReader(io.LimitReader(&buf, 8)))
+	assert.Equal(t, errTooLarge, err)
+}
+
+func TestUniqueClientID(t *testing.T) {
+	id := UniqueClientID("what-day-is-it")
+
+	assert.Regexp(t, `^what-day-is-it-[0-9a-f]{8}$`, id)
+	assert.LessOrEqual(t, len(id), 23)
+	assert.NotEqual(t, id, UniqueClientID("what-day-is-it"))
+}
+
+func TestClient(t *testing.T) {
+	b := newTestBroker(t)
+	defer b.close()
+
+	c, err := Dial(Options{Broker: "tcp://" + b.addr(), ClientID: "test"})
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.NoError(t, c.Publish(Message{"a/b", []byte("retained"), true}))
+	assert.NoError(t, c.Publish(Message{"a/c", []byte("not retained"), false}))
+	assert.NoError(t, c.Ping())
+	assert.NoError(t, c.Close())
+
+	payload, ok := b.get("a/b")
+	assert.True(t, ok)
+	assert.Equal(t, "retained", payload)
+
+	_, ok = b.get("a/c")
+	assert.False(t, ok)
+}
+
+func TestClientBadPassword(t *testing.T) {
+	b := newTestBroker(t)
+	b.password = "sekrit"
+	defer b.close()
+
+	_, err := Dial(Options{Broker: b.addr(), ClientID: "test", Username: "test", Password: "nope"})
+	assert.Equal(t, &ConnectError{4}, err)
+	assert.EqualError(t, err, "mqtt: connection refused, bad user name or password")
+
+	c, err := Dial(Options{Broker: b.addr(), ClientID: "test", Username: "test", Password: "sekrit"})
+
+	if assert.NoError(t, err) {
+		c.Close()
+	}
+
+	_, err = Dial(Options{Broker: b.addr(), ClientID: "test", Password: "sekrit"})
+	assert.Equal(t, errPasswordWithoutUsername, err)
+}
+
+func TestClientWill(t *testing.T) {
+	b := newTestBroker(t)
+	defer b.close()
+
+	c, err := Dial(Options{Broker: b.addr(), ClientID: "test", Will: &Message{"status", []byte("offline"), true}})
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Going away without saying goodbye.
+	c.conn.Close()
+
+	waitFor(t, func() bool {
+		payload, _ := b.get("status")
+		return payload == "offline"
+	}, "the will")
+}
@@ -0,0 +1,137 @@
+package mqtt
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Control packet types from the MQTT 3.1.1 spec.
+const (
+	packetConnect    = 1
+	packetConnack    = 2
+	packetPublish    = 3
+	packetPuback     = 4
+	packetPingreq    = 12
+	packetPingresp   = 13
+	packetDisconnect = 14
+)
+
+// maxRemainingLength is the most that four bytes of remaining length can
+// describe.
+const maxRemainingLength = 268435455
+
+// maxReadLength is the biggest packet we'll read. We only ever read acks, so
+// anything bigger than this means something's wrong with the broker.
+const maxReadLength = 64 * 1024
+
+var (
+	errMalformed = errors.New("mqtt: malformed packet")
+	errTooLarge  = errors.New("mqtt: packet too large")
+)
+
+type packet struct {
+	kind  byte
+	flags byte
+	body  []byte
+}
+
+func readPacket(r *bufio.Reader) (packet, error) {
+	header, err := r.ReadByte()
+
+	if err != nil {
+		return packet{}, err
+	}
+
+	var length, shift uint
+
+	for i := 0; ; i++ {
+		if i == 4 {
+			return packet{}, errMalformed
+		}
+
+		b, err := r.ReadByte()
+
+		if err != nil {
+			return packet{}, err
+		}
+
+		length |= uint(b&0x7f) << shift
+		shift += 7
+
+		if b&0x80 == 0 {
+			break
+		}
+	}
+
+	if length > maxReadLength {
+		return packet{}, errTooLarge
+	}
+
+	body := make([]byte, length)
+
+	if _, err := io.ReadFull(r, body); err != nil {
+		return packet{}, err
+	}
+
+	return packet{header >> 4, header & 0x0f, body}, nil
+}
+
+func writePacket(w io.Writer, kind, flags byte, body []byte) error {
+	if len(body) > maxRemainingLength {
+		return errTooLarge
+	}
+
+	buf := []byte{kind<<4 | flags&0x0f}
+
+	for length := len(body); ; {
+		b := byte(length & 0x7f)
+		length >>= 7
+
+		if length > 0 {
+			b |= 0x80
+		}
+
+		buf = append(buf, b)
+
+		if length == 0 {
+			break
+		}
+	}
+
+	_, err := w.Write(append(buf, body...))
+	return err
+}
+
+func appendUint16(b []byte, n uint16) []byte {
+	return append(b, byte(n>>8), byte(n))
+}
+
+// appendString appends str with the length prefix that MQTT uses for strings
+// and binary data.
+func appendString(b []byte, str string) []byte {
+	return append(appendUint16(b, uint16(len(str))), str...)
+}
+
+func readUint16(b []byte) (uint16, []byte, error) {
+	if len(b) < 2 {
+		return 0, nil, errMalformed
+	}
+
+	return binary.BigEndian.Uint16(b), b[2:], nil
+}
+
+func readString(b []byte) (string, []byte, error) {
+	n, b, err := readUint16(b)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(b) < int(n) {
+		return "", nil, errMalformed
+	}
+
+	return string(b[:n]), b[n:], nil
+}
@@ -0,0 +1,314 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+)
+
+const (
+	DefaultTopicPrefix     = "what-day-is-it"
+	DefaultDiscoveryPrefix = "homeassistant"
+	DefaultMinBackoff      = time.Second
+	DefaultMaxBackoff      = 5 * time.Minute
+)
+
+// Publisher keeps a retained message with the name of the day on a topic for
+// each zone, like what-day-is-it/Asia/Tokyo/today, and updates it at each
+// local midnight. It also tells Home Assistant about each topic so that they
+// show up as sensors without any configuration.
+type Publisher struct {
+	Options Options
+
+	// Canonical timezone names to publish the day for.
+	Zones []string
+
+	TopicPrefix string
+
+	// Where Home Assistant looks for discovery messages. Discovery is off if
+	// it's empty.
+	DiscoveryPrefix string
+
+	// How long to wait before reconnecting, which doubles each time that
+	// reconnecting fails.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func NewPublisher(opts Options, zones []string) *Publisher {
+	return &Publisher{
+		Options:         opts,
+		Zones:           zones,
+		TopicPrefix:     DefaultTopicPrefix,
+		DiscoveryPrefix: DefaultDiscoveryPrefix,
+		MinBackoff:      DefaultMinBackoff,
+		MaxBackoff:      DefaultMaxBackoff,
+	}
+}
+
+// DayAttributes are the details that go along with the day's name, for Home
+// Assistant's sensor attributes.
+type DayAttributes struct {
+	Timezone  string `json:"timezone"`
+	Date      string `json:"date"`
+	ISOWeek   string `json:"iso_week"`
+	DayOfYear int    `json:"day_of_year"`
+}
+
+func newDayAttributes(now time.Time) DayAttributes {
+	year, week := now.ISOWeek()
+
+	return DayAttributes{
+		Timezone:  now.Location().String(),
+		Date:      now.Format("2006-01-02"),
+		ISOWeek:   fmt.Sprintf("%04d-W%02d", year, week),
+		DayOfYear: now.YearDay(),
+	}
+}
+
+type discoveryDevice struct {
+	Identifiers []string `json:"identifiers"`
+	Name        string   `json:"name"`
+}
+
+// discoveryConfig is a Home Assistant MQTT sensor.
+type discoveryConfig struct {
+	Name                string          `json:"name"`
+	UniqueID            string          `json:"unique_id"`
+	StateTopic          string          `json:"state_topic"`
+	JSONAttributesTopic string          `json:"json_attributes_topic"`
+	AvailabilityTopic   string          `json:"availability_topic"`
+	Icon                string          `json:"icon"`
+	Device              discoveryDevice `json:"device"`
+}
+
+func (p *Publisher) topic(parts ...string) string {
+	return strings.Join(append([]string{p.TopicPrefix}, parts...), "/")
+}
+
+// StatusTopic says whether we're online, for Home Assistant to show the
+// sensors as unavailable when we aren't.
+func (p *Publisher) StatusTopic() string {
+	return p.topic("status")
+}
+
+// TodayTopic is where the name of the day in zone goes.
+func (p *Publisher) TodayTopic(zone string) string {
+	return p.topic(zone, "today")
+}
+
+func (p *Publisher) attributesTopic(zone string) string {
+	return p.topic(zone, "attributes")
+}
+
+func objectID(zone string) string {
+	return strings.ToLower(strings.NewReplacer("/", "_", "-", "_").Replace(zone))
+}
+
+func (p *Publisher) discoveryMessage(zone string) Message {
+	node := objectID(p.TopicPrefix)
+	display := clock.Zone{Name: zone}.DisplayName()
+
+	config := discoveryConfig{
+		Name:                "Day in " + display,
+		UniqueID:            node + "_" + objectID(zone),
+		StateTopic:          p.TodayTopic(zone),
+		JSONAttributesTopic: p.attributesTopic(zone),
+		AvailabilityTopic:   p.StatusTopic(),
+		Icon:                "mdi:calendar-today",
+		Device: discoveryDevice{
+			Identifiers: []string{node},
+			Name:        "What day is it?",
+		},
+	}
+
+	payload, _ := json.Marshal(config)
+
+	return Message{
+		Topic:   strings.Join([]string{p.DiscoveryPrefix, "sensor", node, objectID(zone), "config"}, "/"),
+		Payload: payload,
+		Retain:  true,
+	}
+}
+
+func (p *Publisher) status(status string) Message {
+	return Message{p.StatusTopic(), []byte(status), true}
+}
+
+func (p *Publisher) options() Options {
+	opts := p.Options
+	will := p.status("offline")
+	opts.Will = &will
+	return opts
+}
+
+// backoff is how long to wait before the attempt'th try at reconnecting.
+func (p *Publisher) backoff(attempt int) time.Duration {
+	delay := p.MinBackoff
+
+	for i := 0; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	return delay
+}
+
+// jitter picks a delay between half of delay and all of it, so that every
+// copy of the server doesn't reconnect at the same moment after the broker
+// restarts.
+func jitter(delay time.Duration) time.Duration {
+	if delay < 2 {
+		return delay
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)))
+}
+
+// publishSession is one connection to the broker.
+type publishSession struct {
+	p *Publisher
+	c *Client
+
+	// The date that we last published for each zone.
+	published map[string]string
+}
+
+// publishDue publishes the day in each zone where it has changed, and returns
+// how long it is until the next one does.
+func (s *publishSession) publishDue(now time.Time) (time.Duration, error) {
+	var next time.Time
+
+	for _, zone := range s.p.Zones {
+		local := now.In(clock.MustLoadLocation(zone))
+		attrs := newDayAttributes(local)
+
+		if s.published[zone] != attrs.Date {
+			payload, _ := json.Marshal(attrs)
+
+			if err := s.c.Publish(Message{s.p.attributesTopic(zone), payload, true}); err != nil {
+				return 0, err
+			}
+
+			if err := s.c.Publish(Message{s.p.TodayTopic(zone), []byte(local.Weekday().String()), true}); err != nil {
+				return 0, err
+			}
+
+			s.published[zone] = attrs.Date
+		}
+
+		if midnight := clock.NextMidnight(local); next.IsZero() || midnight.Before(next) {
+			next = midnight
+		}
+	}
+
+	if next.IsZero() {
+		return time.Hour, nil
+	}
+
+	return next.Sub(now), nil
+}
+
+// start says that we're online and publishes everything that a broker that's
+// lost its retained messages needs.
+func (s *publishSession) start() error {
+	if err := s.c.Publish(s.p.status("online")); err != nil {
+		return err
+	}
+
+	if s.p.DiscoveryPrefix != "" {
+		for _, zone := range s.p.Zones {
+			if err := s.c.Publish(s.p.discoveryMessage(zone)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// session publishes until stop is closed, which returns nil, or until the
+// connection breaks.
+func (p *Publisher) session(stop <-chan struct{}, connected *bool) error {
+	c, err := Dial(p.options())
+
+	if err != nil {
+		return err
+	}
+
+	*connected = true
+	defer c.Close()
+
+	s := publishSession{p, c, make(map[string]string)}
+
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	keepAlive := p.Options.KeepAlive
+
+	if keepAlive == 0 {
+		keepAlive = DefaultKeepAlive
+	}
+
+	for {
+		wait, err := s.publishDue(*clock.Clock())
+
+		if err != nil {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		ping := time.NewTimer(keepAlive / 2)
+
+		select {
+		case <-stop:
+			timer.Stop()
+			ping.Stop()
+			return c.Publish(p.status("offline"))
+		case <-timer.C:
+			ping.Stop()
+		case <-ping.C:
+			timer.Stop()
+
+			if err := c.Ping(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Run publishes until stop is closed, reconnecting whenever the connection to
+// the broker breaks. It runs forever if stop is nil.
+func (p *Publisher) Run(stop <-chan struct{}) {
+	for attempt := 0; ; attempt++ {
+		connected := false
+		err := p.session(stop, &connected)
+
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if connected {
+			attempt = 0
+		}
+
+		delay := jitter(p.backoff(attempt))
+		logger.WithError(err).WithField("retry_in", delay.String()).Warn("lost connection to MQTT broker")
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+	}
+}
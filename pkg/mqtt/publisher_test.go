@@ -0,0 +1,131 @@
+package mqtt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func mustParseTime(str string) time.Time {
+	t, err := time.Parse(time.RFC3339, str)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func TestPublisherSession(t *testing.T) {
+	b := newTestBroker(t)
+	defer b.close()
+
+	p := NewPublisher(Options{Broker: b.addr(), ClientID: "test"}, []string{"Asia/Tokyo", "UTC"})
+
+	c, err := Dial(p.options())
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	defer c.Close()
+
+	s := publishSession{p, c, make(map[string]string)}
+	assert.NoError(t, s.start())
+
+	status, _ := b.get("what-day-is-it/status")
+	assert.Equal(t, "online", status)
+
+	var config discoveryConfig
+	payload, _ := b.get("homeassistant/sensor/what_day_is_it/asia_tokyo/config")
+	assert.NoError(t, json.Unmarshal([]byte(payload), &config))
+	assert.Equal(t, "Day in Tokyo", config.Name)
+	assert.Equal(t, "what_day_is_it_asia_tokyo", config.UniqueID)
+	assert.Equal(t, "what-day-is-it/Asia/Tokyo/today", config.StateTopic)
+	assert.Equal(t, "what-day-is-it/Asia/Tokyo/attributes", config.JSONAttributesTopic)
+	assert.Equal(t, "what-day-is-it/status", config.AvailabilityTopic)
+
+	// UTC is the next to roll over, in seven hours.
+	wait, err := s.publishDue(mustParseTime("2026-10-19T17:00:00Z"))
+	assert.NoError(t, err)
+	assert.Equal(t, 7*time.Hour, wait)
+
+	today, _ := b.get("what-day-is-it/Asia/Tokyo/today")
+	assert.Equal(t, "Tuesday", today)
+
+	today, _ = b.get("what-day-is-it/UTC/today")
+	assert.Equal(t, "Monday", today)
+
+	var attrs DayAttributes
+	payload, _ = b.get("what-day-is-it/Asia/Tokyo/attributes")
+	assert.NoError(t, json.Unmarshal([]byte(payload), &attrs))
+	assert.Equal(t, DayAttributes{"Asia/Tokyo", "2026-10-20", "2026-W43", 293}, attrs)
+
+	// Only the day that changed goes out again.
+	b.set("what-day-is-it/Asia/Tokyo/today", "untouched")
+
+	wait, err = s.publishDue(mustParseTime("2026-10-20T00:00:00Z"))
+	assert.NoError(t, err)
+	assert.Equal(t, 15*time.Hour, wait)
+
+	today, _ = b.get("what-day-is-it/UTC/today")
+	assert.Equal(t, "Tuesday", today)
+
+	today, _ = b.get("what-day-is-it/Asia/Tokyo/today")
+	assert.Equal(t, "untouched", today)
+}
+
+func TestPublisherReconnects(t *testing.T) {
+	b := newTestBroker(t)
+	defer b.close()
+
+	// The publisher finds out that the connection is gone when it pings.
+	p := NewPublisher(Options{Broker: b.addr(), ClientID: "test", KeepAlive: 20 * time.Millisecond}, []string{"UTC"})
+	p.MinBackoff = time.Millisecond
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		p.Run(stop)
+		close(done)
+	}()
+
+	isToday := func() bool {
+		today, _ := b.get("what-day-is-it/UTC/today")
+		return today == clock.Clock().UTC().Weekday().String()
+	}
+
+	waitFor(t, isToday, "the first day")
+
+	// The broker forgets everything and hangs up.
+	b.set("what-day-is-it/UTC/today", "")
+	b.kick()
+
+	waitFor(t, func() bool { return b.connectCount() == 2 }, "the publisher to reconnect")
+	waitFor(t, isToday, "the day to be published again")
+
+	close(stop)
+	<-done
+
+	status, _ := b.get("what-day-is-it/status")
+	assert.Equal(t, "offline", status)
+}
+
+func TestPublisherBackoff(t *testing.T) {
+	p := NewPublisher(Options{}, nil)
+
+	assert.Equal(t, time.Second, p.backoff(0))
+	assert.Equal(t, 2*time.Second, p.backoff(1))
+	assert.Equal(t, 64*time.Second, p.backoff(6))
+	assert.Equal(t, 5*time.Minute, p.backoff(9))
+	assert.Equal(t, 5*time.Minute, p.backoff(100))
+
+	for i := 0; i < 100; i++ {
+		delay := jitter(time.Minute)
+		assert.True(t, delay >= 30*time.Second && delay < time.Minute, delay.String())
+	}
+}
@@ -0,0 +1,464 @@
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const jsonContentType = "application/json"
+
+// Operations with this tag are called by other services, like Twilio, instead
+// of API clients.
+const webhooksTag = "webhooks"
+
+// methods is the order that operations on the same path are generated in.
+var methods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+}
+
+// initialisms are words that Go spells in all caps.
+var initialisms = map[string]bool{
+	"api":  true,
+	"html": true,
+	"id":   true,
+	"json": true,
+	"ok":   true,
+	"sid":  true,
+	"url":  true,
+}
+
+var wordexp = regexp.MustCompile(`[A-Za-z][a-z0-9]*`)
+
+// goName turns a name like "paused_until" or "getSubscription" in to an
+// exported Go name like "PausedUntil" or "GetSubscription".
+func goName(name string) string {
+	var out strings.Builder
+
+	for _, word := range wordexp.FindAllString(name, -1) {
+		if lower := strings.ToLower(word); initialisms[lower] {
+			out.WriteString(strings.ToUpper(word))
+		} else {
+			out.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+
+	return out.String()
+}
+
+// lowerName turns a name in to an unexported Go name for a parameter, like
+// "id" or "phoneNumber".
+func lowerName(name string) string {
+	words := wordexp.FindAllString(name, -1)
+
+	if len(words) == 0 {
+		return ""
+	}
+
+	return strings.ToLower(words[0]) + goName(strings.Join(words[1:], "_"))
+}
+
+type generator struct {
+	doc     *Document
+	buf     bytes.Buffer
+	imports map[string]bool
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// comment writes str as a Go comment.
+func (g *generator) comment(indent, str string) {
+	for _, line := range strings.Split(strings.TrimSpace(str), "\n") {
+		g.printf("%s// %s\n", indent, line)
+	}
+}
+
+func (g *generator) schema(name string) (*Schema, error) {
+	if schema, ok := g.doc.Components.Schemas[name]; ok {
+		return schema, nil
+	}
+
+	return nil, fmt.Errorf("openapi: unknown schema %s", name)
+}
+
+// goType returns the Go type for values of s. Optional values are pointers so
+// that they can be left out, unless the schema says otherwise.
+func (g *generator) goType(s *Schema, required bool) (string, error) {
+	ptr := ""
+
+	if !required && !s.SkipOptionalPointer {
+		ptr = "*"
+	}
+
+	if s.Ref != "" {
+		if _, err := g.schema(s.RefName()); err != nil {
+			return "", err
+		}
+
+		return ptr + s.RefName(), nil
+	}
+
+	switch s.Type {
+	case "string":
+		if s.Format == "date-time" {
+			g.imports["time"] = true
+			return ptr + "time.Time", nil
+		}
+
+		return ptr + "string", nil
+	case "integer":
+		return ptr + "int", nil
+	case "number":
+		return ptr + "float64", nil
+	case "boolean":
+		return ptr + "bool", nil
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("openapi: array without items")
+		}
+
+		elem, err := g.goType(s.Items, true)
+		return "[]" + elem, err
+	case "object":
+		if s.AdditionalProperties == nil {
+			return "", fmt.Errorf("openapi: objects must be named schemas or maps")
+		}
+
+		elem, err := g.goType(s.AdditionalProperties, true)
+		return "map[string]" + elem, err
+	case "":
+		// Anything goes, and it can already be nil.
+		return "interface{}", nil
+	default:
+		return "", fmt.Errorf("openapi: unsupported type %q", s.Type)
+	}
+}
+
+func (g *generator) generateType(name string, s *Schema) error {
+	if s.Type != "object" || s.AdditionalProperties != nil {
+		return fmt.Errorf("openapi: schema %s must be an object with properties", name)
+	}
+
+	if s.Description != "" {
+		g.comment("", name+" is "+strings.ToLower(s.Description[:1])+s.Description[1:])
+	}
+
+	if s.Deprecated {
+		g.printf("//\n// Deprecated: see the API documentation.\n")
+	}
+
+	g.printf("type %s struct {\n", name)
+
+	for i, prop := range s.Properties {
+		required := s.IsRequired(prop.Name)
+
+		typ, err := g.goType(prop.Schema, required)
+
+		if err != nil {
+			return fmt.Errorf("%v in %s.%s", err, name, prop.Name)
+		}
+
+		if i > 0 {
+			g.printf("\n")
+		}
+
+		if prop.Schema.Description != "" {
+			g.comment("\t", prop.Schema.Description)
+		}
+
+		tag := prop.Name
+
+		if !required {
+			tag += ",omitempty"
+		}
+
+		g.printf("\t%s %s `json:\"%s\"`\n", goName(prop.Name), typ, tag)
+	}
+
+	g.printf("}\n\n")
+	return nil
+}
+
+// jsonSchema returns the JSON schema of content, or false if content isn't
+// something that the client knows how to send or receive. Content that can
+// also be something other than JSON is fine, since the client asks for JSON.
+func jsonSchema(content map[string]MediaType) (*Schema, bool) {
+	if len(content) == 0 {
+		return nil, true
+	}
+
+	if mt, ok := content[jsonContentType]; ok && mt.Schema != nil && mt.Schema.Ref != "" {
+		return mt.Schema, true
+	}
+
+	return nil, false
+}
+
+// successResponse returns the response that the operation gives when it
+// works.
+func (g *generator) successResponse(method, path string, op *Operation) (Response, error) {
+	for _, code := range []string{"200", "201", "204"} {
+		if resp, ok := op.Responses[code]; ok {
+			return g.doc.ResolveResponse(resp)
+		}
+	}
+
+	return Response{}, fmt.Errorf("openapi: %s %s has no success response", method, path)
+}
+
+func (g *generator) generateOperation(method, path string, op *Operation) error {
+	for _, tag := range op.Tags {
+		if tag == webhooksTag {
+			return nil
+		}
+	}
+
+	resp, err := g.successResponse(method, path, op)
+
+	if err != nil {
+		return err
+	}
+
+	out, ok := jsonSchema(resp.Content)
+
+	if !ok {
+		// Pages and webhooks aren't for API clients.
+		return nil
+	}
+
+	var in *Schema
+
+	if op.RequestBody != nil {
+		if in, ok = jsonSchema(op.RequestBody.Content); !ok || in == nil {
+			return nil
+		}
+	}
+
+	name := goName(op.OperationID)
+
+	var args []string
+	var query []Parameter
+
+	pathExpr := `"` + path + `"`
+
+	for _, param := range op.Parameters {
+		param, err := g.doc.ResolveParameter(param)
+
+		if err != nil {
+			return err
+		}
+
+		switch param.In {
+		case "path":
+			arg := lowerName(param.Name)
+			args = append(args, arg+" string")
+			pathExpr = strings.Replace(pathExpr, "{"+param.Name+"}", `"+url.PathEscape(`+arg+`)+"`, 1)
+			g.imports["net/url"] = true
+		case "query":
+			query = append(query, param)
+		default:
+			return fmt.Errorf("openapi: %s %s has a parameter in %s", method, path, param.In)
+		}
+	}
+
+	pathExpr = strings.TrimSuffix(pathExpr, `+""`)
+
+	if in != nil {
+		if _, err := g.schema(in.RefName()); err != nil {
+			return err
+		}
+
+		args = append(args, "in *"+in.RefName())
+	}
+
+	if len(query) > 0 {
+		if err := g.generateParams(name, query); err != nil {
+			return err
+		}
+
+		args = append(args, "params *"+name+"Params")
+	}
+
+	summary := op.Summary
+
+	if summary == "" {
+		summary = fmt.Sprintf("calls %s %s.", method, path)
+	}
+
+	g.comment("", name+" "+strings.ToLower(summary[:1])+summary[1:])
+
+	if op.Deprecated {
+		g.printf("//\n// Deprecated: see the API documentation.\n")
+	}
+
+	result := "error"
+
+	if out != nil {
+		if _, err := g.schema(out.RefName()); err != nil {
+			return err
+		}
+
+		result = "(*" + out.RefName() + ", error)"
+	}
+
+	g.printf("func (c *Client) %s(%s) %s {\n", name, strings.Join(append([]string{"ctx context.Context"}, args...), ", "), result)
+
+	queryExpr := "nil"
+
+	if len(query) > 0 {
+		queryExpr = "query"
+		g.printf("query := url.Values{}\n\n")
+		g.printf("if params != nil {\n")
+
+		for _, param := range query {
+			field := "params." + goName(param.Name)
+			g.printf("if %s != nil {\n", field)
+
+			switch param.Schema.Type {
+			case "integer":
+				g.imports["strconv"] = true
+				g.printf("query.Set(%q, strconv.Itoa(*%s))\n", param.Name, field)
+			case "boolean":
+				g.imports["strconv"] = true
+				g.printf("query.Set(%q, strconv.FormatBool(*%s))\n", param.Name, field)
+			default:
+				g.printf("query.Set(%q, *%s)\n", param.Name, field)
+			}
+
+			g.printf("}\n")
+		}
+
+		g.printf("}\n\n")
+		g.imports["net/url"] = true
+	}
+
+	inExpr := "nil"
+
+	if in != nil {
+		inExpr = "in"
+	}
+
+	if out == nil {
+		g.printf("return c.do(ctx, %q, %s, %s, %s, nil)\n}\n\n", method, pathExpr, queryExpr, inExpr)
+		return nil
+	}
+
+	g.printf("var out %s\n\n", out.RefName())
+	g.printf("if err := c.do(ctx, %q, %s, %s, %s, &out); err != nil {\nreturn nil, err\n}\n\n", method, pathExpr, queryExpr, inExpr)
+	g.printf("return &out, nil\n}\n\n")
+
+	return nil
+}
+
+func (g *generator) generateParams(name string, params []Parameter) error {
+	g.printf("// %sParams are the optional query parameters for %s.\n", name, name)
+	g.printf("type %sParams struct {\n", name)
+
+	for i, param := range params {
+		typ, err := g.goType(param.Schema, false)
+
+		if err != nil {
+			return fmt.Errorf("%v in parameter %s", err, param.Name)
+		}
+
+		if i > 0 {
+			g.printf("\n")
+		}
+
+		if param.Description != "" {
+			g.comment("\t", param.Description)
+		}
+
+		g.printf("\t%s %s\n", goName(param.Name), typ)
+	}
+
+	g.printf("}\n\n")
+	return nil
+}
+
+// GenerateClient returns Go source for package pkg with a type for each of
+// the schemas in doc and a method on Client for each operation that takes and
+// returns JSON. The package has to provide Client and its do method.
+func GenerateClient(doc *Document, pkg string) ([]byte, error) {
+	g := generator{
+		doc:     doc,
+		imports: map[string]bool{"context": true},
+	}
+
+	var names []string
+
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := g.generateType(name, doc.Components.Schemas[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	var paths []string
+
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		item := doc.Paths[path]
+
+		for method := range item {
+			if !isKnownMethod(method) {
+				return nil, fmt.Errorf("openapi: unsupported method %s on %s", method, path)
+			}
+		}
+
+		for _, method := range methods {
+			if op, ok := item[strings.ToLower(method)]; ok {
+				if err := g.generateOperation(method, path, op); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	var imports []string
+
+	for imp := range g.imports {
+		imports = append(imports, fmt.Sprintf("%q", imp))
+	}
+
+	sort.Strings(imports)
+
+	var out bytes.Buffer
+
+	fmt.Fprintf(&out, "// Code generated by openapi-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	fmt.Fprintf(&out, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	out.Write(g.buf.Bytes())
+
+	return format.Source(out.Bytes())
+}
+
+func isKnownMethod(method string) bool {
+	for _, m := range methods {
+		if strings.ToLower(m) == method {
+			return true
+		}
+	}
+
+	return false
+}
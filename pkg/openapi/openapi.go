@@ -0,0 +1,213 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Document is the part of an OpenAPI 3 document that we use. Parse rejects
+// anything else so that typos don't go unnoticed.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem maps lower case HTTP methods to the operation for each.
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+type Parameter struct {
+	Ref         string  `json:"$ref,omitempty"`
+	Name        string  `json:"name,omitempty"`
+	In          string  `json:"in,omitempty"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]Response       `json:"responses,omitempty"`
+	Parameters      map[string]Parameter      `json:"parameters,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type        string `json:"type"`
+	Scheme      string `json:"scheme,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type Schema struct {
+	Ref                  string     `json:"$ref,omitempty"`
+	Type                 string     `json:"type,omitempty"`
+	Format               string     `json:"format,omitempty"`
+	Description          string     `json:"description,omitempty"`
+	Enum                 []string   `json:"enum,omitempty"`
+	Properties           Properties `json:"properties,omitempty"`
+	Required             []string   `json:"required,omitempty"`
+	Items                *Schema    `json:"items,omitempty"`
+	AdditionalProperties *Schema    `json:"additionalProperties,omitempty"`
+	Deprecated           bool       `json:"deprecated,omitempty"`
+
+	// Leaves an optional value as a plain Go type, so that fields which were
+	// required once stay compatible with the code that sets them.
+	SkipOptionalPointer bool `json:"x-go-type-skip-optional-pointer,omitempty"`
+}
+
+const (
+	schemaRefPrefix    = "#/components/schemas/"
+	responseRefPrefix  = "#/components/responses/"
+	parameterRefPrefix = "#/components/parameters/"
+)
+
+// ResolveParameter returns the parameter that p refers to, or p itself if it
+// isn't a reference.
+func (d *Document) ResolveParameter(p Parameter) (Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+
+	if param, ok := d.Components.Parameters[strings.TrimPrefix(p.Ref, parameterRefPrefix)]; ok && strings.HasPrefix(p.Ref, parameterRefPrefix) {
+		return param, nil
+	}
+
+	return p, fmt.Errorf("openapi: unknown parameter %s", p.Ref)
+}
+
+// ResolveResponse returns the response that r refers to, or r itself if it
+// isn't a reference.
+func (d *Document) ResolveResponse(r Response) (Response, error) {
+	if r.Ref == "" {
+		return r, nil
+	}
+
+	if resp, ok := d.Components.Responses[strings.TrimPrefix(r.Ref, responseRefPrefix)]; ok && strings.HasPrefix(r.Ref, responseRefPrefix) {
+		return resp, nil
+	}
+
+	return r, fmt.Errorf("openapi: unknown response %s", r.Ref)
+}
+
+// RefName returns the name of the schema that s refers to, or "" if it isn't a
+// reference.
+func (s *Schema) RefName() string {
+	return strings.TrimPrefix(s.Ref, schemaRefPrefix)
+}
+
+// IsRequired indicates that the named property is always present.
+func (s *Schema) IsRequired(name string) bool {
+	for _, required := range s.Required {
+		if required == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Property struct {
+	Name   string
+	Schema *Schema
+}
+
+// Properties are kept in the order they're written in so that generated
+// code reads the same way as the document.
+type Properties []Property
+
+func (p *Properties) UnmarshalJSON(buf []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.DisallowUnknownFields()
+
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("openapi: properties must be an object")
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+
+		if err != nil {
+			return err
+		}
+
+		var schema Schema
+
+		if err := dec.Decode(&schema); err != nil {
+			return err
+		}
+
+		*p = append(*p, Property{tok.(string), &schema})
+	}
+
+	_, err := dec.Token()
+	return err
+}
+
+// Get returns the named property, or nil if there isn't one.
+func (p Properties) Get(name string) *Schema {
+	for _, prop := range p {
+		if prop.Name == name {
+			return prop.Schema
+		}
+	}
+
+	return nil
+}
+
+// Parse reads a JSON OpenAPI document.
+func Parse(buf []byte) (*Document, error) {
+	var doc Document
+
+	dec := json.NewDecoder(bytes.NewReader(buf))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("openapi: %v", err)
+	}
+
+	return &doc, nil
+}
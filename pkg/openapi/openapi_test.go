@@ -0,0 +1,119 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"paused_until":    "PausedUntil",
+		"getSubscription": "GetSubscription",
+		"getOpenAPI":      "GetOpenAPI",
+		"account_sid":     "AccountSID",
+		"ok":              "OK",
+		"for":             "For",
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, goName(name))
+	}
+}
+
+func TestLowerName(t *testing.T) {
+	tests := map[string]string{
+		"id":           "id",
+		"number":       "number",
+		"phone_number": "phoneNumber",
+	}
+
+	for name, expected := range tests {
+		assert.Equal(t, expected, lowerName(name))
+	}
+}
+
+func TestParse(t *testing.T) {
+	doc, err := Parse([]byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Test", "version": "1"},
+		"paths": {},
+		"components": {
+			"schemas": {
+				"Thing": {"type": "object", "properties": {"b": {"type": "string"}, "a": {"type": "integer"}}}
+			}
+		}
+	}`))
+
+	assert.NoError(t, err)
+
+	props := doc.Components.Schemas["Thing"].Properties
+	assert.Equal(t, "b", props[0].Name)
+	assert.Equal(t, "a", props[1].Name)
+	assert.Equal(t, "integer", props.Get("a").Type)
+
+	// Typos are errors.
+	_, err = Parse([]byte(`{"openapi": "3.0.3", "paths": {}, "components": {"schemas": {"Thing": {"type": "object", "propreties": {}}}}}`))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte(`{"openapi": "3.0.3", "paths": {}, "components": {"schemas": {"Thing": {"type": "object", "properties": {"a": {"tpye": "string"}}}}}}`))
+	assert.Error(t, err)
+}
+
+func TestGenerateClient(t *testing.T) {
+	doc, err := Parse([]byte(`{
+		"openapi": "3.0.3",
+		"info": {"title": "Test", "version": "1"},
+		"paths": {
+			"/things/{id}": {
+				"get": {
+					"operationId": "getThing",
+					"summary": "Returns a thing.",
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {"200": {"description": "The thing.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}}}
+				}
+			},
+			"/hook": {
+				"post": {
+					"operationId": "receiveHook",
+					"tags": ["webhooks"],
+					"requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}},
+					"responses": {"200": {"description": "The thing.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}}}
+				}
+			},
+			"/page": {
+				"get": {
+					"operationId": "getPage",
+					"responses": {"200": {"description": "A page.", "content": {"text/html": {"schema": {"type": "string"}}}}}
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"Thing": {"type": "object", "properties": {"id": {"type": "string"}, "seen_at": {"type": "string", "format": "date-time"}, "extra": {}, "name": {"type": "string", "x-go-type-skip-optional-pointer": true}}, "required": ["id"]}
+			}
+		}
+	}`))
+
+	assert.NoError(t, err)
+
+	buf, err := GenerateClient(doc, "things")
+	assert.NoError(t, err)
+
+	src := string(buf)
+	assert.Contains(t, src, "package things")
+	assert.Contains(t, src, "ID string `json:\"id\"`")
+	assert.Contains(t, src, "SeenAt *time.Time `json:\"seen_at,omitempty\"`")
+	assert.Contains(t, src, "Extra interface{} `json:\"extra,omitempty\"`")
+	assert.Contains(t, src, "Name string `json:\"name,omitempty\"`")
+	assert.Contains(t, src, "// GetThing returns a thing.\nfunc (c *Client) GetThing(ctx context.Context, id string) (*Thing, error) {")
+	assert.Contains(t, src, `"/things/"+url.PathEscape(id)`)
+	assert.False(t, strings.Contains(src, "GetPage"), "pages aren't for clients")
+	assert.False(t, strings.Contains(src, "ReceiveHook"), "webhooks aren't for clients")
+
+	doc.Components.Schemas["Thing"].Properties[0].Schema.Ref = "#/components/schemas/Nothing"
+
+	_, err = GenerateClient(doc, "things")
+	assert.EqualError(t, err, "openapi: unknown schema Nothing in Thing.id")
+}
@@ -2,17 +2,31 @@ package server
 
 import (
 	"encoding/json"
-	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 
-	"github.com/bradhe/what-day-is-it/pkg/clock"
 	"github.com/bradhe/what-day-is-it/pkg/models"
-	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
 	"github.com/bradhe/what-day-is-it/pkg/twilio"
 )
 
+// verifyTwilioRequest makes sure that Twilio signed r, whose form has to be
+// parsed in to params. Twilio signs the URL that it was configured with, which
+// is on BaseURL rather than whatever host the request came in on. It writes
+// the error response if it didn't.
+func (s *Server) verifyTwilioRequest(w http.ResponseWriter, r *http.Request, params url.Values) bool {
+	u := strings.TrimSuffix(s.BaseURL, "/") + r.URL.RequestURI()
+
+	if err := twilio.VerifyRequest(s.TwilioAuthToken, u, r.Header.Get("X-Twilio-Signature"), params); err != nil {
+		logger.WithError(err).Warn("rejected Twilio webhook request")
+		writeError(w, http.StatusUnauthorized, ErrorCodeUnauthorized, "The request isn't signed by Twilio.")
+		return false
+	}
+
+	return true
+}
+
 type IncomingMessageRequest struct {
 	AccountSID string `json:"account_sid"`
 	From       string `json:"from"`
@@ -32,41 +46,54 @@ func (s *Server) PostIncomingMessage(w http.ResponseWriter, r *http.Request) {
 		logger.WithError(err).Error("failed to decode Twilio webhook request")
 		w.WriteHeader(http.StatusInternalServerError)
 		return
+	} else if !s.verifyTwilioRequest(w, r, vals) {
+		return
 	} else {
 		req.From = vals.Get("From")
 		req.Body = vals.Get("Body")
 		req.AccountSID = vals.Get("AccountSid")
 	}
 
-	if isStopMessage(req.Body) {
-		if phoneNumber, err := s.managers.PhoneNumbers().Get(req.From); err != nil {
-			logger.WithError(err).Error("failed to find phone number associated with Twilio webhook request")
-			w.WriteHeader(http.StatusInternalServerError)
-			return
-		} else {
-			// We update this record to mark it as not sendable.
-			if err := s.managers.PhoneNumbers().UpdateNotSendable(&phoneNumber); err != nil {
-				logger.WithError(err).Error("failed to update record as not sendable")
-				w.WriteHeader(http.StatusInternalServerError)
-				return
-			}
-		}
+	// Twilio sends WhatsApp messages here too, from addresses like
+	// whatsapp:+14155551234.
+	channel := models.ChannelSMS
 
-		// We can reply to the user now that they've been dropped.
-		logger.Info("user unsubscribed")
-		w.Write(twilio.TwiMLResponse(`Okay, I'll stop reminding you starting...NOW!`))
+	if models.IsWhatsAppAddress(req.From) {
+		channel = models.ChannelWhatsApp
+		req.From = models.WhatsAppAddress(req.From)
+	}
+
+	if reply, err := s.handleMessage(channel, req.From, req.Body); err != nil {
+		logger.WithError(err).Error("failed to handle message")
+		w.WriteHeader(http.StatusInternalServerError)
 	} else {
-		logger.Infof("unknown request from user: `%s`", req.Body)
-		w.Write(twilio.TwiMLResponse(`You do know you're talking to a robot right?`))
+		if channel == models.ChannelWhatsApp {
+			s.recordReceived(req.From)
+		}
+
+		w.Write(twilio.TwiMLResponse(reply))
 	}
 }
 
+const (
+	// VerifyByReply confirms a subscription by replying YES to a text.
+	VerifyByReply = "reply"
+
+	// VerifyByCode confirms a subscription by entering a code that we texted
+	// to the number in to the subscribe form.
+	VerifyByCode = "code"
+)
+
 type PostSubscribeRequest struct {
 	// The phone number to establish a subscription to.
 	Number string `json:"number"`
 
 	// The time zone that the user selected.
 	Timezone string `json:"timezone"`
+
+	// How the owner of the number will confirm the subscription. Either
+	// VerifyByReply, the default, or VerifyByCode.
+	Verification string `json:"verification,omitempty"`
 }
 
 type PostSubscribeResponse struct {
@@ -74,11 +101,31 @@ type PostSubscribeResponse struct {
 
 	Timezone string `json:"timezone,omitempty"`
 
+	// Indicates that we picked the timezone because the request didn't include
+	// a usable one.
+	TimezoneGuessed bool `json:"timezone_guessed,omitempty"`
+
 	Error string `json:"error,omitempty"`
 
 	Subscribed bool `json:"subscribed"`
+
+	// Where the number is in its subscription lifecycle.
+	Status models.Status `json:"status,omitempty"`
+
+	// Indicates that the number has to confirm by SMS before it's subscribed.
+	ConfirmationRequired bool `json:"confirmation_required,omitempty"`
+
+	// Indicates that the code we texted to the number has to be sent to
+	// /api/subscribe/verify before it's subscribed.
+	VerificationRequired bool `json:"verification_required,omitempty"`
+
+	// Lets the owner of the number manage their subscription through the API.
+	// Only handed out once they've proven they own it.
+	ManageToken string `json:"manage_token,omitempty"`
 }
 
+// PostSubscribe is the original subscribe endpoint. It's kept around for
+// compatibility; new clients should use PostSubscription.
 func (s *Server) PostSubscribe(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
@@ -98,6 +145,17 @@ func (s *Server) PostSubscribe(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Verification != "" && req.Verification != VerifyByReply && req.Verification != VerifyByCode {
+		logger.WithField("verification", req.Verification).Error("invalid verification method")
+		w.WriteHeader(http.StatusBadRequest)
+
+		resp.Subscribed = false
+		resp.Error = "Unknown verification method."
+		w.Write(Dump(resp))
+
+		return
+	}
+
 	if num := models.CleanPhoneNumber(req.Number); !models.IsCleanPhoneNumber(num) {
 		logger.Error("invalid phone number")
 		w.WriteHeader(http.StatusPreconditionFailed)
@@ -107,47 +165,46 @@ func (s *Server) PostSubscribe(w http.ResponseWriter, r *http.Request) {
 
 		w.Write(Dump(resp))
 	} else {
-		phoneNumber := models.PhoneNumber{
-			Number:   num,
-			Timezone: s.timezoneOrDefault(req.Timezone),
-
-			// They just signed up so we'll always be sendable in this case.
-			IsSendable: true,
-		}
-
-		if err := s.managers.PhoneNumbers().Create(phoneNumber); err != nil {
-			if err == managers.ErrRecordExists {
-				// TODO: They resubscribed so we should update their record I guess.
+		phoneNumber, verification, err := s.subscribe(models.ChannelSMS, num, req.Timezone, nil, req.Verification)
 
-				resp.Number = num
-				resp.Timezone = phoneNumber.Timezone
-				resp.Subscribed = true
-				resp.Error = ""
+		if err == errTooSoon {
+			w.WriteHeader(http.StatusTooManyRequests)
 
-				w.Write(Dump(resp))
-			} else {
-				logger.WithError(err).Error("failed to save phone number")
-				w.WriteHeader(http.StatusInternalServerError)
+			resp.Subscribed = false
+			resp.Error = "We just sent a confirmation. Wait a minute before asking for another."
 
-				resp.Subscribed = false
-				resp.Error = "An internal error occured."
-
-				w.Write(Dump(resp))
-			}
-		} else {
-			s.sender.Send(num, "Yo! Okay, every morning I'll text you what day it is. Just say STOP to make me stop.")
-			s.sender.Send(num, fmt.Sprintf("Today is %s by the way.", clock.GetDayInZone(clock.MustLoadLocation(phoneNumber.Timezone))))
-
-			// We'll update this record so we don't send something again later...
-			s.managers.PhoneNumbers().UpdateSent(&phoneNumber, clock.Clock())
+			w.Write(Dump(resp))
+			return
+		} else if err != nil {
+			logger.WithError(err).Error("failed to save phone number")
+			w.WriteHeader(http.StatusInternalServerError)
 
-			resp.Number = num
-			resp.Timezone = phoneNumber.Timezone
-			resp.Subscribed = true
-			resp.Error = ""
+			resp.Subscribed = false
+			resp.Error = "An internal error occured."
 
 			w.Write(Dump(resp))
+			return
+		}
+
+		resp.Number = num
+		resp.Timezone = phoneNumber.Timezone
+		resp.TimezoneGuessed = phoneNumber.TimezoneGuessed
+		resp.Status = phoneNumber.Status
+		resp.Subscribed = phoneNumber.IsSendable()
+		resp.Error = ""
+
+		switch phoneNumber.Status {
+		case models.StatusPending:
+			resp.VerificationRequired = verification == VerifyByCode
+			resp.ConfirmationRequired = !resp.VerificationRequired
+		case models.StatusStopped, models.StatusBounced:
+			resp.ConfirmationRequired = true
+			resp.Error = "You asked us to stop texting you. Text START to us to resubscribe."
+		case models.StatusBanned:
+			resp.Error = "This number can't be subscribed."
 		}
+
+		w.Write(Dump(resp))
 	}
 }
 
@@ -0,0 +1,702 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/discord"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/slack"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/bradhe/what-day-is-it/pkg/tokens"
+	"github.com/bradhe/what-day-is-it/pkg/webhook"
+	"github.com/gorilla/mux"
+)
+
+var errBanned = errors.New("server: banned")
+
+// ManageTokenLifetime is how long a manage token is good for.
+var ManageTokenLifetime = 30 * 24 * time.Hour
+
+const manageTokenPurpose = "manage"
+
+// Machine-readable codes for everything that can go wrong in the v1 API.
+const (
+	ErrorCodeInvalidRequest      = "invalid_request"
+	ErrorCodeInvalidPhoneNumber  = "invalid_phone_number"
+	ErrorCodeInvalidChannel      = "invalid_channel"
+	ErrorCodeInvalidWebhookURL   = "invalid_webhook_url"
+	ErrorCodeInvalidEmail        = "invalid_email"
+	ErrorCodeInvalidVerification = "invalid_verification"
+	ErrorCodeInvalidTimezone     = "invalid_timezone"
+	ErrorCodeAmbiguousTimezone   = "ambiguous_timezone"
+	ErrorCodeInvalidDeliveryTime = "invalid_delivery_time"
+	ErrorCodeInvalidDays         = "invalid_days"
+	ErrorCodeInvalidPause        = "invalid_pause"
+	ErrorCodeUnauthorized        = "unauthorized"
+	ErrorCodeTokenExpired        = "token_expired"
+	ErrorCodeForbidden           = "forbidden"
+	ErrorCodeNotFound            = "not_found"
+	ErrorCodeCodeExpired         = "code_expired"
+	ErrorCodeTooManyAttempts     = "too_many_attempts"
+	ErrorCodeTooSoon             = "too_soon"
+	ErrorCodeWrongCode           = "wrong_code"
+	ErrorCodeStopped             = "stopped"
+	ErrorCodeBanned              = "banned"
+	ErrorCodeNotPausable         = "not_pausable"
+	ErrorCodeInternal            = "internal_error"
+)
+
+type APIError struct {
+	// One of the ErrorCode constants.
+	Code string `json:"code"`
+
+	// Something to show a person.
+	Message string `json:"message"`
+}
+
+// ErrorResponse is the body of every unsuccessful v1 API response.
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(Dump(ErrorResponse{APIError{code, message}}))
+}
+
+// requestError is something wrong with what was asked for, along with the
+// status to respond with.
+type requestError struct {
+	Status int
+	APIError
+}
+
+func (e *requestError) Error() string {
+	return e.Message
+}
+
+func newRequestError(status int, code, message string) *requestError {
+	return &requestError{status, APIError{code, message}}
+}
+
+func writeRequestError(w http.ResponseWriter, err *requestError) {
+	writeError(w, err.Status, err.Code, err.Message)
+}
+
+func writeJSON(w http.ResponseWriter, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(Dump(obj))
+}
+
+// manageToken returns a token that lets whoever holds it manage num's
+// subscription, or nothing if we can't sign tokens.
+func (s *Server) manageToken(num string) string {
+	signer := tokens.NewSigner(s.SigningSecret)
+
+	if !signer.IsConfigured() {
+		return ""
+	}
+
+	now := clock.Clock()
+
+	return signer.Sign(tokens.Claims{
+		Purpose:   manageTokenPurpose,
+		Subject:   num,
+		ExpiresAt: now.Add(ManageTokenLifetime),
+		IssuedAt:  now,
+	})
+}
+
+// authorizeSubscription makes sure the request carries a manage token for
+// num, or the admin token. It writes the error response if it doesn't. The
+// claims are nil for the admin token.
+func (s *Server) authorizeSubscription(w http.ResponseWriter, r *http.Request, num string) (*tokens.Claims, bool) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if token == "" {
+		writeError(w, http.StatusUnauthorized, ErrorCodeUnauthorized, "A manage token is required.")
+		return nil, false
+	}
+
+	if s.AdminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.AdminToken)) == 1 {
+		return nil, true
+	}
+
+	claims, err := tokens.NewSigner(s.SigningSecret).Verify(token, manageTokenPurpose, *clock.Clock())
+
+	switch {
+	case err == tokens.ErrExpired:
+		writeError(w, http.StatusUnauthorized, ErrorCodeTokenExpired, "The manage token has expired.")
+		return nil, false
+	case err != nil:
+		writeError(w, http.StatusUnauthorized, ErrorCodeUnauthorized, "The manage token isn't valid.")
+		return nil, false
+	case claims.Subject != num:
+		writeError(w, http.StatusForbidden, ErrorCodeForbidden, "The manage token is for a different phone number.")
+		return nil, false
+	}
+
+	return &claims, true
+}
+
+// isRevoked indicates that claims were issued before phoneNumber last
+// stopped. Unsubscribing throws away every token handed out before then, so
+// the next owner of a number doesn't inherit them. Times are compared to the
+// second, which is as precise as the stored ones are.
+func isRevoked(claims tokens.Claims, phoneNumber models.PhoneNumber) bool {
+	stoppedAt, ok := phoneNumber.StatusChangedAt[models.StatusStopped]
+
+	if !ok {
+		return false
+	}
+
+	return claims.IssuedAt == nil || !claims.IssuedAt.Truncate(time.Second).After(stoppedAt.Truncate(time.Second))
+}
+
+type Subscription struct {
+	// The phone number, or an ID like slack:0123456789abcdef for subscriptions
+	// on other channels.
+	Number string `json:"number"`
+
+	// How the message is delivered, like sms or slack.
+	Channel models.Channel `json:"channel"`
+
+	Status models.Status `json:"status"`
+
+	Timezone string `json:"timezone"`
+
+	// Indicates that we picked the timezone because nobody told us one.
+	TimezoneGuessed bool `json:"timezone_guessed"`
+
+	// Local time of day that the message goes out, like "08:00".
+	DeliveryTime string `json:"delivery_time"`
+
+	// The days of the week that the message goes out, like "monday".
+	Days []string `json:"days"`
+
+	// When deliveries start again, for paused subscriptions that will resume
+	// on their own.
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+
+	NextDeliveryAt *time.Time `json:"next_delivery_at,omitempty"`
+}
+
+func optionalTime(t *time.Time) *time.Time {
+	if t == nil || t.IsZero() || t.Unix() == 0 {
+		return nil
+	}
+
+	return t
+}
+
+// dayNames lists the days in w, like "monday".
+func dayNames(w clock.Weekdays) []string {
+	var names []string
+
+	for _, d := range w.Days() {
+		names = append(names, strings.ToLower(d.String()))
+	}
+
+	return names
+}
+
+func newSubscription(phoneNumber models.PhoneNumber) Subscription {
+	schedule := phoneNumber.DeliverySchedule()
+
+	sub := Subscription{
+		Number:          phoneNumber.Number,
+		Channel:         phoneNumber.DeliveryChannel(),
+		Status:          phoneNumber.Status,
+		Timezone:        phoneNumber.Timezone,
+		TimezoneGuessed: phoneNumber.TimezoneGuessed,
+		DeliveryTime:    clock.FormatTimeOfDay(schedule.Minute),
+		PausedUntil:     phoneNumber.PausedUntil,
+		LastSentAt:      optionalTime(phoneNumber.LastSentAt),
+		Days:            dayNames(schedule.Days),
+	}
+
+	if phoneNumber.IsSendable() {
+		sub.NextDeliveryAt = optionalTime(phoneNumber.SendDeadline)
+	}
+
+	return sub
+}
+
+type PostSubscriptionRequest struct {
+	// The phone number to text or call, for the sms and voice channels.
+	Number string `json:"number,omitempty"`
+
+	// Either sms, the default, voice, email, slack, discord or webhook.
+	Channel string `json:"channel,omitempty"`
+
+	// The address to email, for the email channel.
+	Email string `json:"email,omitempty"`
+
+	// The webhook to post to, for the slack, discord and webhook channels.
+	WebhookURL string `json:"webhook_url,omitempty"`
+
+	// Local time of day to send at, like "07:30". The default is 08:00.
+	DeliveryTime string `json:"delivery_time,omitempty"`
+
+	// A timezone name, city or abbreviation. We'll guess if it's missing.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Either VerifyByReply, the default, or VerifyByCode.
+	Verification string `json:"verification,omitempty"`
+}
+
+// webhookChannels check the webhook URLs that subscriptions on each channel
+// post to.
+var webhookChannels = map[models.Channel]func(string) bool{
+	models.ChannelSlack:   slack.IsWebhookURL,
+	models.ChannelDiscord: discord.IsWebhookURL,
+	models.ChannelWebhook: webhook.IsURL,
+}
+
+type PostSubscriptionResponse struct {
+	Subscription Subscription `json:"subscription"`
+
+	// Indicates that the number has to reply YES to the text we sent it.
+	ConfirmationRequired bool `json:"confirmation_required"`
+
+	// Indicates that the code we texted has to be sent to the verify
+	// endpoint.
+	VerificationRequired bool `json:"verification_required"`
+}
+
+func (s *Server) PostSubscription(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req PostSubscriptionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "The request body must be JSON.")
+		return
+	}
+
+	if req.Verification != "" && req.Verification != VerifyByReply && req.Verification != VerifyByCode {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidVerification, "Verification must be reply or code.")
+		return
+	}
+
+	channel, ok := models.ParseChannel(req.Channel)
+
+	if !ok {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidChannel, fmt.Sprintf("Unknown channel %s.", req.Channel))
+		return
+	}
+
+	var address string
+
+	if isWebhookURL, ok := webhookChannels[channel]; ok {
+		if !isWebhookURL(req.WebhookURL) {
+			message := fmt.Sprintf("That isn't a %s webhook URL.", channel)
+
+			if channel == models.ChannelWebhook {
+				message = "Webhook URLs have to be https, and can't be on a private network."
+			}
+
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidWebhookURL, message)
+			return
+		}
+
+		// Nobody can reply to a webhook, so the code is the only way to
+		// confirm.
+		address = req.WebhookURL
+		req.Verification = VerifyByCode
+	} else if channel == models.ChannelEmail {
+		address = models.CleanEmailAddress(req.Email)
+
+		if !models.IsCleanEmailAddress(address) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidEmail, "Invalid email address.")
+			return
+		}
+
+		// We don't read replies to email.
+		req.Verification = VerifyByCode
+	} else if channel == models.ChannelSMS || channel == models.ChannelVoice {
+		address = models.CleanPhoneNumber(req.Number)
+
+		if !models.IsCleanPhoneNumber(address) {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidPhoneNumber, "Invalid phone number.")
+			return
+		}
+
+		// There's nothing to reply YES to on a call, so we read out a code
+		// instead.
+		if channel == models.ChannelVoice {
+			req.Verification = VerifyByCode
+		}
+	} else {
+		// Chats sign themselves up by messaging us.
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidChannel, fmt.Sprintf("Subscribe on %s by messaging us there.", channel))
+		return
+	}
+
+	var schedule *clock.Schedule
+
+	if req.DeliveryTime != "" {
+		if minute, err := clock.ParseTimeOfDay(req.DeliveryTime); err != nil {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidDeliveryTime, "Delivery time must look like 07:30.")
+			return
+		} else {
+			schedule = &clock.Schedule{Minute: minute, Days: clock.DefaultSchedule.Days}
+		}
+	}
+
+	timezone := req.Timezone
+
+	if timezone != "" {
+		if tz, rerr := resolveTimezone(timezone); rerr != nil {
+			writeRequestError(w, rerr)
+			return
+		} else {
+			timezone = tz
+		}
+	}
+
+	phoneNumber, verification, err := s.subscribe(channel, address, timezone, schedule, req.Verification)
+
+	if err == errTooSoon {
+		writeError(w, http.StatusTooManyRequests, ErrorCodeTooSoon, "We just sent a confirmation. Wait a minute before asking for another.")
+		return
+	} else if err != nil {
+		logger.WithError(err).Error("failed to save phone number")
+		writeError(w, http.StatusInternalServerError, ErrorCodeInternal, "An internal error occured.")
+		return
+	}
+
+	switch phoneNumber.Status {
+	case models.StatusStopped, models.StatusBounced:
+		writeError(w, http.StatusConflict, ErrorCodeStopped, "This number asked us to stop texting it. Text START to us to resubscribe.")
+	case models.StatusBanned:
+		writeError(w, http.StatusConflict, ErrorCodeBanned, "This number can't be subscribed.")
+	default:
+		resp := PostSubscriptionResponse{Subscription: newSubscription(phoneNumber)}
+
+		if phoneNumber.IsAwaitingConfirmation() {
+			resp.VerificationRequired = verification == VerifyByCode
+			resp.ConfirmationRequired = !resp.VerificationRequired
+		}
+
+		writeJSON(w, resp)
+	}
+}
+
+type PostSubscriptionVerifyRequest struct {
+	// The code that we texted to the number.
+	Code string `json:"code"`
+}
+
+type PostSubscriptionVerifyResponse struct {
+	Subscription Subscription `json:"subscription"`
+
+	// Authorizes requests to manage the subscription. Empty if the server
+	// can't sign tokens.
+	ManageToken string `json:"manage_token,omitempty"`
+
+	// Signs everything we post to a webhook subscription. We only hand it
+	// out when the subscription is verified.
+	WebhookSecret string `json:"webhook_secret,omitempty"`
+}
+
+func (s *Server) PostSubscriptionVerify(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req PostSubscriptionVerifyRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "The request body must be JSON.")
+		return
+	}
+
+	num := models.CleanSubscriberID(mux.Vars(r)["number"])
+	phoneNumber, err := s.verify(num, req.Code)
+
+	switch err {
+	case nil:
+		writeJSON(w, PostSubscriptionVerifyResponse{
+			Subscription:  newSubscription(phoneNumber),
+			ManageToken:   s.manageToken(num),
+			WebhookSecret: phoneNumber.WebhookSecret,
+		})
+	case errCodeExpired:
+		writeError(w, http.StatusGone, ErrorCodeCodeExpired, "That code has expired. Sign up again to get a new one.")
+	case errTooManyAttempts:
+		writeError(w, http.StatusTooManyRequests, ErrorCodeTooManyAttempts, "Too many wrong codes. Sign up again in a few minutes to get a new one.")
+	case errWrongCode:
+		writeError(w, http.StatusUnprocessableEntity, ErrorCodeWrongCode, "That code isn't right.")
+	case errSubscriptionExpired:
+		writeError(w, http.StatusGone, ErrorCodeNotFound, "The subscription expired before it was verified. Sign up again.")
+	case errBanned:
+		writeError(w, http.StatusConflict, ErrorCodeBanned, "This number can't be subscribed.")
+	default:
+		writeError(w, http.StatusInternalServerError, ErrorCodeInternal, "An internal error occured.")
+	}
+}
+
+// getSubscription authorizes the request and looks up the subscription in its
+// path, writing the error response if either fails.
+func (s *Server) getSubscription(w http.ResponseWriter, r *http.Request) (models.PhoneNumber, bool) {
+	num := models.CleanSubscriberID(mux.Vars(r)["number"])
+
+	if !models.IsValidSubscriberID(num) {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidPhoneNumber, "Invalid phone number.")
+		return models.PhoneNumber{}, false
+	}
+
+	claims, ok := s.authorizeSubscription(w, r, num)
+
+	if !ok {
+		return models.PhoneNumber{}, false
+	}
+
+	phoneNumber, err := s.managers.PhoneNumbers().Get(num)
+
+	if err == managers.ErrRecordNotFound {
+		writeError(w, http.StatusNotFound, ErrorCodeNotFound, "There's no subscription for that phone number.")
+		return phoneNumber, false
+	} else if err != nil {
+		logger.WithError(err).Error("failed to find phone number")
+		writeError(w, http.StatusInternalServerError, ErrorCodeInternal, "An internal error occured.")
+		return phoneNumber, false
+	}
+
+	if claims != nil && isRevoked(*claims, phoneNumber) {
+		writeError(w, http.StatusUnauthorized, ErrorCodeUnauthorized, "The manage token has been revoked.")
+		return models.PhoneNumber{}, false
+	}
+
+	return phoneNumber, true
+}
+
+// resolveTimezone turns a zone name, city or abbreviation in to a zone name.
+func resolveTimezone(str string) (string, *requestError) {
+	candidates := clock.LookupPlace(str)
+
+	switch len(candidates) {
+	case 0:
+		return "", newRequestError(http.StatusBadRequest, ErrorCodeInvalidTimezone, fmt.Sprintf("Unknown timezone %s.", str))
+	case 1:
+		return candidates[0], nil
+	default:
+		return "", newRequestError(http.StatusBadRequest, ErrorCodeAmbiguousTimezone, fmt.Sprintf("%s could mean %s.", str, strings.Join(candidates, " or ")))
+	}
+}
+
+func (s *Server) GetSubscription(w http.ResponseWriter, r *http.Request) {
+	if phoneNumber, ok := s.getSubscription(w, r); ok {
+		writeJSON(w, newSubscription(phoneNumber))
+	}
+}
+
+type PatchSubscriptionRequest struct {
+	// A timezone name, city or abbreviation.
+	Timezone *string `json:"timezone,omitempty"`
+
+	// Local time of day to send at, like "07:30".
+	DeliveryTime *string `json:"delivery_time,omitempty"`
+
+	// Days of the week to send on, like "monday" or "mon".
+	Days *[]string `json:"days,omitempty"`
+
+	// Pauses or resumes deliveries.
+	Paused *bool `json:"paused,omitempty"`
+
+	// Pauses deliveries until this date, like "2026-11-01".
+	PausedUntil *string `json:"paused_until,omitempty"`
+}
+
+// subscriptionUpdate is a checked set of changes to make to a subscription.
+type subscriptionUpdate struct {
+	Timezone       string
+	UpdateTimezone bool
+
+	Schedule clock.Schedule
+
+	Pause       bool
+	PausedUntil *time.Time
+	Resume      bool
+}
+
+// planUpdate checks the changes in req against phoneNumber without making any
+// of them.
+func planUpdate(phoneNumber models.PhoneNumber, req PatchSubscriptionRequest) (subscriptionUpdate, *requestError) {
+	u := subscriptionUpdate{
+		Timezone: phoneNumber.Timezone,
+		Schedule: phoneNumber.DeliverySchedule(),
+	}
+
+	if req.Timezone != nil {
+		timezone, rerr := resolveTimezone(*req.Timezone)
+
+		if rerr != nil {
+			return u, rerr
+		}
+
+		// Saying where you are, even if it's where we guessed, stops us guessing.
+		u.Timezone = timezone
+		u.UpdateTimezone = timezone != phoneNumber.Timezone || phoneNumber.TimezoneGuessed
+	}
+
+	if req.DeliveryTime != nil {
+		if minute, err := clock.ParseTimeOfDay(*req.DeliveryTime); err != nil {
+			return u, newRequestError(http.StatusBadRequest, ErrorCodeInvalidDeliveryTime, "Delivery time must look like 07:30.")
+		} else {
+			u.Schedule.Minute = minute
+		}
+	}
+
+	if req.Days != nil {
+		var days []time.Weekday
+
+		for _, str := range *req.Days {
+			if d, err := clock.ParseWeekday(str); err != nil {
+				return u, newRequestError(http.StatusBadRequest, ErrorCodeInvalidDays, fmt.Sprintf("%s isn't a day of the week.", str))
+			} else {
+				days = append(days, d)
+			}
+		}
+
+		if len(days) == 0 {
+			return u, newRequestError(http.StatusBadRequest, ErrorCodeInvalidDays, "Pick at least one day. Pause the subscription to stop it for a while.")
+		}
+
+		u.Schedule.Days = clock.NewWeekdays(days...)
+	}
+
+	u.Pause = req.PausedUntil != nil || (req.Paused != nil && *req.Paused)
+	u.Resume = req.Paused != nil && !*req.Paused && req.PausedUntil == nil
+
+	if u.Pause || u.Resume {
+		if phoneNumber.Status != models.StatusActive && phoneNumber.Status != models.StatusPaused {
+			return u, newRequestError(http.StatusConflict, ErrorCodeNotPausable, "Only active subscriptions can be paused or resumed.")
+		}
+	}
+
+	if req.PausedUntil != nil {
+		var err error
+
+		if u.PausedUntil, err = parseResumeDate("until "+*req.PausedUntil, clock.Clock(), clock.MustLoadLocation(u.Timezone)); err != nil {
+			return u, newRequestError(http.StatusBadRequest, ErrorCodeInvalidPause, fmt.Sprintf("Paused until must be a date in the next %d days, like 2026-11-01.", MaxPauseDays))
+		}
+	}
+
+	return u, nil
+}
+
+// applyUpdate makes the changes in u, recording each one as done by actor.
+func (s *Server) applyUpdate(phoneNumber *models.PhoneNumber, u subscriptionUpdate, actor string) error {
+	if u.UpdateTimezone {
+		previous := phoneNumber.Timezone
+
+		if err := s.managers.PhoneNumbers().UpdateTimezone(phoneNumber, u.Timezone, clock.Clock()); err != nil {
+			return err
+		}
+
+		s.recordEvent(phoneNumber.Number, models.EventTimezoneChanged, actor, map[string]string{
+			"from": previous,
+			"to":   u.Timezone,
+		})
+	}
+
+	if u.Schedule != phoneNumber.DeliverySchedule() {
+		if err := s.managers.PhoneNumbers().UpdateSchedule(phoneNumber, u.Schedule, clock.Clock()); err != nil {
+			return err
+		}
+
+		s.recordEvent(phoneNumber.Number, models.EventScheduleChanged, actor, map[string]string{
+			"delivery_time": clock.FormatTimeOfDay(u.Schedule.Minute),
+			"days":          strings.Join(dayNames(u.Schedule.Days), ","),
+		})
+	}
+
+	if u.Pause {
+		return s.pause(phoneNumber, u.PausedUntil, actor)
+	} else if u.Resume && phoneNumber.Status == models.StatusPaused {
+		return s.resume(phoneNumber, actor)
+	}
+
+	return nil
+}
+
+func (s *Server) PatchSubscription(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	phoneNumber, ok := s.getSubscription(w, r)
+
+	if !ok {
+		return
+	}
+
+	var req PatchSubscriptionRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "The request body must be JSON.")
+		return
+	}
+
+	u, rerr := planUpdate(phoneNumber, req)
+
+	if rerr != nil {
+		writeRequestError(w, rerr)
+		return
+	}
+
+	if err := s.applyUpdate(&phoneNumber, u, models.ActorWeb); err != nil {
+		logger.WithError(err).Error("failed to update subscription")
+		writeError(w, http.StatusInternalServerError, ErrorCodeInternal, "An internal error occured.")
+		return
+	}
+
+	writeJSON(w, newSubscription(phoneNumber))
+}
+
+// unsubscribe stops all deliveries to the phone number. Numbers that are
+// already stopped are left alone.
+func (s *Server) unsubscribe(phoneNumber *models.PhoneNumber, actor string) error {
+	switch phoneNumber.Status {
+	case models.StatusStopped:
+		return nil
+	case models.StatusBanned:
+		// Unsubscribing would lift the ban.
+		return errBanned
+	}
+
+	if err := s.managers.PhoneNumbers().UpdateStatus(phoneNumber, models.StatusStopped, clock.Clock()); err != nil {
+		logger.WithError(err).Error("failed to unsubscribe phone number")
+		return err
+	}
+
+	s.recordEvent(phoneNumber.Number, models.EventUnsubscribed, actor, nil)
+
+	logger.Info("user unsubscribed")
+	return nil
+}
+
+func (s *Server) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+	phoneNumber, ok := s.getSubscription(w, r)
+
+	if !ok {
+		return
+	}
+
+	switch err := s.unsubscribe(&phoneNumber, models.ActorWeb); err {
+	case nil:
+		writeJSON(w, newSubscription(phoneNumber))
+	case errBanned:
+		writeError(w, http.StatusConflict, ErrorCodeBanned, "This number can't be changed.")
+	default:
+		writeError(w, http.StatusInternalServerError, ErrorCodeInternal, "An internal error occured.")
+	}
+}
@@ -0,0 +1,408 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/bradhe/what-day-is-it/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAPIServer() (*Server, *recordingSender) {
+	sender := &recordingSender{}
+
+	s := NewServer(memory.New(), sender, false, "")
+	s.DefaultTimeZone = "UTC"
+	s.SigningSecret = []byte("sekrit")
+	s.TwilioAuthToken = "twilio-sekrit"
+
+	return s, sender
+}
+
+func doAPIRequest(s *Server, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+func decodeAPIError(w *httptest.ResponseRecorder) APIError {
+	var resp ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return resp.Error
+}
+
+func TestSubscriptionLifecycle(t *testing.T) {
+	s, sender := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"number": "(415) 555-1234", "timezone": "Tokyo", "verification": "code"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var created PostSubscriptionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.True(t, created.VerificationRequired)
+		assert.Equal(t, models.StatusPending, created.Subscription.Status)
+		assert.Equal(t, "Asia/Tokyo", created.Subscription.Timezone)
+
+		code := codeexp.FindStringSubmatch(sender.Sent()[0].Body)[1]
+
+		w = doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions/+14155551234/verify", "", `{"code": "nope"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Equal(t, ErrorCodeWrongCode, decodeAPIError(w).Code)
+
+		w = doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions/+14155551234/verify", "", `{"code": "`+code+`"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var verified PostSubscriptionVerifyResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &verified))
+		assert.Equal(t, models.StatusActive, verified.Subscription.Status)
+		assert.NotEmpty(t, verified.ManageToken)
+
+		token := verified.ManageToken
+
+		w = doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/+14155551234", token, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var sub Subscription
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sub))
+		assert.Equal(t, "08:00", sub.DeliveryTime)
+		assert.Len(t, sub.Days, 7)
+		assert.Equal(t, "2026-10-21T08:00:00+09:00", sub.NextDeliveryAt.Format("2006-01-02T15:04:05-07:00"))
+
+		w = doAPIRequest(s, http.MethodPatch, "/api/v1/subscriptions/+14155551234", token, `{"timezone": "America/Chicago", "delivery_time": "06:45", "days": ["mon", "Wednesday", "fri"]}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		sub = Subscription{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sub))
+		assert.Equal(t, "America/Chicago", sub.Timezone)
+		assert.Equal(t, "06:45", sub.DeliveryTime)
+		assert.Equal(t, []string{"monday", "wednesday", "friday"}, sub.Days)
+		assert.Equal(t, "2026-10-21T06:45:00-05:00", sub.NextDeliveryAt.Format("2006-01-02T15:04:05-07:00"))
+
+		w = doAPIRequest(s, http.MethodPatch, "/api/v1/subscriptions/+14155551234", token, `{"paused_until": "2026-11-01"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		sub = Subscription{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sub))
+		assert.Equal(t, models.StatusPaused, sub.Status)
+		assert.Equal(t, "2026-11-01T00:00:00-05:00", sub.PausedUntil.Format("2006-01-02T15:04:05-07:00"))
+		assert.Nil(t, sub.NextDeliveryAt)
+
+		w = doAPIRequest(s, http.MethodPatch, "/api/v1/subscriptions/+14155551234", token, `{"paused": false}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		sub = Subscription{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sub))
+		assert.Equal(t, models.StatusActive, sub.Status)
+
+		w = doAPIRequest(s, http.MethodDelete, "/api/v1/subscriptions/+14155551234", token, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		sub = Subscription{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &sub))
+		assert.Equal(t, models.StatusStopped, sub.Status)
+
+		// Unsubscribing revokes the token.
+		w = doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/+14155551234", token, "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, ErrorCodeUnauthorized, decodeAPIError(w).Code)
+
+		// Deleting twice is fine.
+		s.AdminToken = "admin"
+		w = doAPIRequest(s, http.MethodDelete, "/api/v1/subscriptions/+14155551234", "admin", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		// Stopped numbers can't be paused.
+		w = doAPIRequest(s, http.MethodPatch, "/api/v1/subscriptions/+14155551234", "admin", `{"paused": true}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, ErrorCodeNotPausable, decodeAPIError(w).Code)
+
+		events, _ := s.managers.Events().List("+14155551234", 20)
+		assert.Equal(t, models.EventUnsubscribed, events[0].Type)
+	})
+}
+
+func TestSubscriptionOnEachChannel(t *testing.T) {
+	tests := []struct {
+		channel models.Channel
+		body    string
+
+		// Where the code and the welcome go.
+		to      string
+		welcome string
+
+		// Anything else to check once the subscription is verified.
+		check func(t *testing.T, s *Server, sent []sentMessage, verified PostSubscriptionVerifyResponse)
+	}{
+		{
+			models.ChannelSlack,
+			`{"channel": "slack", "webhook_url": "` + testWebhookURL + `", "timezone": "Berlin"}`,
+			testWebhookURL,
+			"Hi! Every morning I'll post what day it is in Europe/Berlin here. Today is Monday.",
+			nil,
+		},
+		{
+			models.ChannelDiscord,
+			`{"channel": "discord", "webhook_url": "` + testDiscordWebhookURL + `", "timezone": "Tokyo", "delivery_time": "07:15"}`,
+			testDiscordWebhookURL,
+			"Hi! Every morning I'll post what day it is in Asia/Tokyo here. Today is Tuesday.",
+			func(t *testing.T, s *Server, sent []sentMessage, verified PostSubscriptionVerifyResponse) {
+				assert.Equal(t, "07:15", verified.Subscription.DeliveryTime)
+				assert.Equal(t, "2026-10-21T07:15:00+09:00", verified.Subscription.NextDeliveryAt.Format("2006-01-02T15:04:05-07:00"))
+			},
+		},
+		{
+			// Asking to confirm by reply doesn't work for email.
+			models.ChannelEmail,
+			`{"channel": "email", "email": " Someone@Example.com", "timezone": "Berlin", "verification": "reply"}`,
+			"someone@example.com",
+			"Hi! Every morning I'll email you what day it is in Europe/Berlin. Today is Monday by the way.",
+			func(t *testing.T, s *Server, sent []sentMessage, verified PostSubscriptionVerifyResponse) {
+				// The address is the ID, whatever case it's in.
+				w := doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/Someone@example.com", verified.ManageToken, "")
+				assert.Equal(t, http.StatusOK, w.Code)
+			},
+		},
+		{
+			models.ChannelWebhook,
+			`{"channel": "webhook", "webhook_url": "` + testGenericWebhookURL + `", "timezone": "Tokyo"}`,
+			testGenericWebhookURL,
+			"Hi! Every morning I'll post what day it is in Asia/Tokyo here. Today is Tuesday.",
+			func(t *testing.T, s *Server, sent []sentMessage, verified PostSubscriptionVerifyResponse) {
+				assert.Regexp(t, `^whsec_[0-9a-f]{64}$`, verified.WebhookSecret)
+
+				sub, err := s.WebhookSubscription(testGenericWebhookURL)
+
+				if assert.NoError(t, err) {
+					assert.Equal(t, webhook.Subscription{
+						ID:       verified.Subscription.Number,
+						Secret:   verified.WebhookSecret,
+						Timezone: "Asia/Tokyo",
+					}, sub)
+				}
+			},
+		},
+		{
+			models.ChannelVoice,
+			`{"channel": "voice", "number": "(415) 555-1234", "timezone": "Chicago"}`,
+			"+14155551234",
+			"Hi! Every morning I'll call you and tell you what day it is. Today is Monday. To stop the calls, call this number and press 9.",
+			func(t *testing.T, s *Server, sent []sentMessage, verified PostSubscriptionVerifyResponse) {
+				assert.Regexp(t, spokenCodeexp, sent[0].Body)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.channel), func(t *testing.T) {
+			s, sms := newTestAPIServer()
+			sender := &recordingSender{}
+			s.Senders[test.channel] = sender
+
+			withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+				w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", test.body)
+				assert.Equal(t, http.StatusOK, w.Code)
+
+				// Webhooks only get their secret once they're verified.
+				assert.NotContains(t, w.Body.String(), "whsec_")
+
+				var created PostSubscriptionResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+				assert.True(t, created.VerificationRequired)
+				assert.Equal(t, test.channel, created.Subscription.Channel)
+				assert.Equal(t, models.SubscriberID(test.channel, test.to), created.Subscription.Number)
+
+				if !assert.Len(t, sender.Sent(), 1) {
+					return
+				}
+
+				assert.Equal(t, test.to, sender.Sent()[0].To)
+				code := sentCode(sender.Sent()[0].Body)
+
+				w = doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions/"+created.Subscription.Number+"/verify", "", `{"code": "`+code+`"}`)
+				assert.Equal(t, http.StatusOK, w.Code)
+
+				var verified PostSubscriptionVerifyResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &verified))
+				assert.Equal(t, models.StatusActive, verified.Subscription.Status)
+
+				w = doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/"+created.Subscription.Number, verified.ManageToken, "")
+				assert.Equal(t, http.StatusOK, w.Code)
+
+				if assert.Len(t, sender.Sent(), 2) {
+					assert.Equal(t, test.welcome, sender.Sent()[1].Body)
+				}
+
+				assert.Empty(t, sms.Sent())
+
+				if test.check != nil {
+					test.check(t, s, sender.Sent(), verified)
+				}
+			})
+		})
+	}
+}
+
+func TestManageTokensIssuedAfterStoppingWork(t *testing.T) {
+	s, _ := newTestAPIServer()
+	emails := &recordingSender{}
+	s.Senders[models.ChannelEmail] = emails
+
+	stoppedAt := mustParseTime("2026-10-19T17:00:00Z")
+	assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{
+		Number:          "someone@example.com",
+		Channel:         models.ChannelEmail,
+		Address:         "someone@example.com",
+		Timezone:        "UTC",
+		Status:          models.StatusStopped,
+		StatusChangedAt: map[models.Status]time.Time{models.StatusStopped: *stoppedAt},
+	}))
+
+	var old string
+
+	withClockTime(t, mustParseTime("2026-10-19T16:00:00Z"), func(t *testing.T) {
+		old = s.manageToken("someone@example.com")
+	})
+
+	withClockTime(t, mustParseTime("2026-10-19T18:00:00Z"), func(t *testing.T) {
+		w := doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/someone@example.com", old, "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		// Coming back gets them a new one.
+		doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"channel": "email", "email": "someone@example.com"}`)
+		code := codeexp.FindStringSubmatch(emails.Sent()[0].Body)[1]
+
+		w = doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions/someone@example.com/verify", "", `{"code": "`+code+`"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var verified PostSubscriptionVerifyResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &verified))
+
+		w = doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/someone@example.com", verified.ManageToken, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestPostSubscriptionKeepsDeliveryTime(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "UTC", Status: models.StatusActive}))
+
+		// Changing it takes the manage token.
+		doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"number": "+14155551234", "delivery_time": "05:00"}`)
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get("+14155551234")
+		assert.NoError(t, err)
+		assert.Nil(t, phoneNumber.Schedule)
+	})
+}
+
+func TestSubscriptionAuthorization(t *testing.T) {
+	s, _ := newTestAPIServer()
+	s.AdminToken = "admin"
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "UTC", Status: models.StatusActive}))
+
+		token := s.manageToken("+14155551234")
+
+		tests := []struct {
+			name   string
+			path   string
+			token  string
+			status int
+			code   string
+		}{
+			{"no token", "/api/v1/subscriptions/+14155551234", "", http.StatusUnauthorized, ErrorCodeUnauthorized},
+			{"bogus token", "/api/v1/subscriptions/+14155551234", "bogus", http.StatusUnauthorized, ErrorCodeUnauthorized},
+			{"someone else's token", "/api/v1/subscriptions/+14155551234", s.manageToken("+14155559999"), http.StatusForbidden, ErrorCodeForbidden},
+			{"own token", "/api/v1/subscriptions/+14155551234", token, http.StatusOK, ""},
+			{"admin token", "/api/v1/subscriptions/+14155551234", "admin", http.StatusOK, ""},
+			{"unknown number", "/api/v1/subscriptions/+14155559999", "admin", http.StatusNotFound, ErrorCodeNotFound},
+			{"invalid number", "/api/v1/subscriptions/nope", "admin", http.StatusBadRequest, ErrorCodeInvalidPhoneNumber},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				w := doAPIRequest(s, http.MethodGet, test.path, test.token, "")
+				assert.Equal(t, test.status, w.Code)
+				assert.Equal(t, test.code, decodeAPIError(w).Code)
+			})
+		}
+	})
+}
+
+func TestPatchSubscriptionValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code string
+	}{
+		{"not json", `nope`, ErrorCodeInvalidRequest},
+		{"unknown timezone", `{"timezone": "Atlantis"}`, ErrorCodeInvalidTimezone},
+		{"ambiguous timezone", `{"timezone": "Portland"}`, ErrorCodeAmbiguousTimezone},
+		{"bad delivery time", `{"delivery_time": "8am"}`, ErrorCodeInvalidDeliveryTime},
+		{"bad day", `{"days": ["someday"]}`, ErrorCodeInvalidDays},
+		{"no days", `{"days": []}`, ErrorCodeInvalidDays},
+		{"pause in the past", `{"paused_until": "2020-01-01"}`, ErrorCodeInvalidPause},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+				s, _ := newTestAPIServer()
+				assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "UTC", Status: models.StatusActive}))
+
+				w := doAPIRequest(s, http.MethodPatch, "/api/v1/subscriptions/+14155551234", s.manageToken("+14155551234"), test.body)
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Equal(t, test.code, decodeAPIError(w).Code)
+
+				// Nothing changes if anything is wrong.
+				phoneNumber, _ := s.managers.PhoneNumbers().Get("+14155551234")
+				assert.Equal(t, "UTC", phoneNumber.Timezone)
+				assert.Nil(t, phoneNumber.Schedule)
+			})
+		})
+	}
+}
+
+func TestExpiredManageToken(t *testing.T) {
+	s, _ := newTestAPIServer()
+	assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "UTC", Status: models.StatusActive}))
+
+	var token string
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		token = s.manageToken("+14155551234")
+	})
+
+	withClockTime(t, mustParseTime("2026-12-01T17:00:00Z"), func(t *testing.T) {
+		w := doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/+14155551234", token, "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, ErrorCodeTokenExpired, decodeAPIError(w).Code)
+	})
+}
+
+func TestPostSubscriptionStopped(t *testing.T) {
+	s, sender := newTestAPIServer()
+	assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "UTC", Status: models.StatusStopped}))
+
+	w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"number": "+14155551234"}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, ErrorCodeStopped, decodeAPIError(w).Code)
+	assert.Empty(t, sender.Sent())
+
+	w = doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"number": "123"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrorCodeInvalidPhoneNumber, decodeAPIError(w).Code)
+}
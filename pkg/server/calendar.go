@@ -0,0 +1,195 @@
+package server
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// How many days of the past the calendar feed keeps around.
+	CalendarDaysBefore = 7
+
+	// How many days ahead the calendar feed goes.
+	CalendarDaysAfter = 90
+)
+
+// icalWriter writes the content lines of an iCalendar object as RFC 5545
+// wants them, which is folded at 75 octets with CRLF line endings.
+type icalWriter struct {
+	strings.Builder
+}
+
+func (w *icalWriter) line(name, value string) {
+	line := name + ":" + value
+
+	for len(line) > 75 {
+		// Don't split a character across lines.
+		n := 75
+
+		for !utf8.RuneStart(line[n]) {
+			n--
+		}
+
+		w.WriteString(line[:n] + "\r\n ")
+		line = line[n:]
+	}
+
+	w.WriteString(line + "\r\n")
+}
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+func icalText(str string) string {
+	return icalTextEscaper.Replace(str)
+}
+
+func icalOffset(seconds int) string {
+	sign := "+"
+
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+
+	return fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+}
+
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405"
+)
+
+// writeTimezone writes a VTIMEZONE for loc that covers from until to, with
+// an observance for each time that the offset changes.
+func writeTimezone(w *icalWriter, loc *time.Location, from, to time.Time) {
+	w.line("BEGIN", "VTIMEZONE")
+	w.line("TZID", loc.String())
+
+	start := from.In(loc)
+	name, offset := start.Zone()
+
+	kind := "STANDARD"
+
+	if clock.IsDST(start) {
+		kind = "DAYLIGHT"
+	}
+
+	w.line("BEGIN", kind)
+	w.line("DTSTART", start.Format(icalDateTime))
+	w.line("TZOFFSETFROM", icalOffset(offset))
+	w.line("TZOFFSETTO", icalOffset(offset))
+	w.line("TZNAME", icalText(name))
+	w.line("END", kind)
+
+	for _, transition := range clock.Transitions(loc, from, to) {
+		kind = "STANDARD"
+
+		if transition.DST {
+			kind = "DAYLIGHT"
+		}
+
+		// Observances start at the local time from before the change.
+		onset := transition.At.UTC().Add(time.Duration(transition.OffsetBefore) * time.Second)
+
+		w.line("BEGIN", kind)
+		w.line("DTSTART", onset.Format(icalDateTime))
+		w.line("TZOFFSETFROM", icalOffset(transition.OffsetBefore))
+		w.line("TZOFFSETTO", icalOffset(transition.OffsetAfter))
+		w.line("TZNAME", icalText(transition.Name))
+		w.line("END", kind)
+	}
+
+	w.line("END", "VTIMEZONE")
+}
+
+// calendarHost is the domain that event UIDs are in.
+func (s *Server) calendarHost() string {
+	if u, err := url.Parse(s.BaseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return "what-day-is-today.com"
+}
+
+// calendar returns an iCalendar feed with an all-day event for each day
+// around now in loc.
+func (s *Server) calendar(loc *time.Location, now time.Time) string {
+	today := clock.StartOfDay(now.In(loc))
+	first := today.AddDate(0, 0, -CalendarDaysBefore)
+	last := today.AddDate(0, 0, CalendarDaysAfter)
+
+	zone := clock.Zone{Name: loc.String()}
+	slug := strings.ToLower(strings.Replace(loc.String(), "/", "-", -1))
+
+	var w icalWriter
+
+	w.line("BEGIN", "VCALENDAR")
+	w.line("VERSION", "2.0")
+	w.line("PRODID", "-//What day is it//Calendar//EN")
+	w.line("CALSCALE", "GREGORIAN")
+	w.line("METHOD", "PUBLISH")
+	w.line("X-WR-CALNAME", icalText("What day is it? ("+zone.DisplayName()+")"))
+	w.line("X-WR-TIMEZONE", loc.String())
+	w.line("REFRESH-INTERVAL;VALUE=DURATION", "P1D")
+	w.line("X-PUBLISHED-TTL", "P1D")
+
+	writeTimezone(&w, loc, first, last)
+
+	// The stamp only changes once a day so that the feed does too.
+	stamp := today.UTC().Format(icalDateTime) + "Z"
+
+	for day := first; day.Before(last); day = clock.NextMidnight(day) {
+		next := clock.NextMidnight(day)
+		daysInYear := time.Date(day.Year(), time.December, 31, 0, 0, 0, 0, loc).YearDay()
+		_, week := day.ISOWeek()
+
+		w.line("BEGIN", "VEVENT")
+		w.line("UID", day.Format(icalDate)+"-"+slug+"@"+s.calendarHost())
+		w.line("DTSTAMP", stamp)
+		w.line("DTSTART;VALUE=DATE", day.Format(icalDate))
+		w.line("DTEND;VALUE=DATE", next.Format(icalDate))
+		w.line("SUMMARY", day.Weekday().String())
+		w.line("DESCRIPTION", icalText(fmt.Sprintf("Day %d of %d, with %d left. Week %d.", day.YearDay(), daysInYear, daysInYear-day.YearDay(), week)))
+		w.line("TRANSP", "TRANSPARENT")
+		w.line("END", "VEVENT")
+	}
+
+	w.line("END", "VCALENDAR")
+
+	return w.String()
+}
+
+func (s *Server) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	timezone, rerr := resolveTimezone(mux.Vars(r)["tz"])
+
+	if rerr != nil {
+		writeRequestError(w, rerr)
+		return
+	}
+
+	loc := clock.MustLoadLocation(timezone)
+	now := clock.Clock().In(loc)
+	body := s.calendar(loc, now)
+
+	etag := fmt.Sprintf(`"%x"`, sha1.Sum([]byte(body)))
+	expires := clock.NextMidnight(now)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", expires.Sub(now)/time.Second))
+	w.Header().Set("Expires", expires.UTC().Format(http.TimeFormat))
+
+	if matchesETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write([]byte(body))
+}
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCalendar(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		w := doAPIRequest(s, http.MethodGet, "/api/calendar/Asia/Tokyo.ics", "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=79200", w.Header().Get("Cache-Control"))
+
+		body := w.Body.String()
+		assert.True(t, strings.HasPrefix(body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		assert.True(t, strings.HasSuffix(body, "END:VCALENDAR\r\n"))
+		assert.Contains(t, body, "X-WR-TIMEZONE:Asia/Tokyo\r\n")
+		assert.Contains(t, body, "BEGIN:VTIMEZONE\r\nTZID:Asia/Tokyo\r\nBEGIN:STANDARD\r\nDTSTART:20261013T000000\r\nTZOFFSETFROM:+0900\r\nTZOFFSETTO:+0900\r\nTZNAME:JST\r\nEND:STANDARD\r\nEND:VTIMEZONE\r\n")
+		assert.Equal(t, CalendarDaysBefore+CalendarDaysAfter, strings.Count(body, "BEGIN:VEVENT"))
+
+		// It's already Tuesday in Tokyo.
+		assert.Contains(t, body, "BEGIN:VEVENT\r\n"+
+			"UID:20261020-asia-tokyo@what-day-is-today.com\r\n"+
+			"DTSTAMP:20261019T150000Z\r\n"+
+			"DTSTART;VALUE=DATE:20261020\r\n"+
+			"DTEND;VALUE=DATE:20261021\r\n"+
+			"SUMMARY:Tuesday\r\n"+
+			"DESCRIPTION:Day 293 of 365\\, with 72 left. Week 43.\r\n"+
+			"TRANSP:TRANSPARENT\r\n"+
+			"END:VEVENT\r\n")
+
+		for _, line := range strings.Split(body, "\r\n") {
+			assert.True(t, len(line) <= 75, line)
+		}
+
+		etag := w.Header().Get("ETag")
+		assert.NotEmpty(t, etag)
+
+		req := doAPIRequest(s, http.MethodGet, "/api/calendar/tokyo.ics", "", "")
+		assert.Equal(t, etag, req.Header().Get("ETag"))
+	})
+}
+
+func TestGetCalendarTransitions(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		w := doAPIRequest(s, http.MethodGet, "/api/calendar/America/Los_Angeles.ics", "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		body := w.Body.String()
+		assert.Contains(t, body, "BEGIN:DAYLIGHT\r\nDTSTART:20261012T000000\r\nTZOFFSETFROM:-0700\r\nTZOFFSETTO:-0700\r\nTZNAME:PDT\r\nEND:DAYLIGHT\r\n")
+		assert.Contains(t, body, "BEGIN:STANDARD\r\nDTSTART:20261101T020000\r\nTZOFFSETFROM:-0700\r\nTZOFFSETTO:-0800\r\nTZNAME:PST\r\nEND:STANDARD\r\n")
+
+		// The day the clocks change is still one event.
+		assert.Contains(t, body, "DTSTART;VALUE=DATE:20261101\r\nDTEND;VALUE=DATE:20261102\r\nSUMMARY:Sunday\r\n")
+	})
+}
+
+func TestGetCalendarNotModified(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	var etag string
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		etag = doAPIRequest(s, http.MethodGet, "/api/calendar/UTC.ics", "", "").Header().Get("ETag")
+	})
+
+	withClockTime(t, mustParseTime("2026-10-19T23:00:00Z"), func(t *testing.T) {
+		req := doTodayRequest(s, "/api/calendar/UTC.ics", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, req.Code)
+	})
+
+	withClockTime(t, mustParseTime("2026-10-20T01:00:00Z"), func(t *testing.T) {
+		req := doTodayRequest(s, "/api/calendar/UTC.ics", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusOK, req.Code)
+	})
+}
+
+func TestGetCalendarBadTimezone(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	w := doAPIRequest(s, http.MethodGet, "/api/calendar/Atlantis.ics", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrorCodeInvalidTimezone, decodeAPIError(w).Code)
+}
+
+func TestICalWriterFolds(t *testing.T) {
+	var w icalWriter
+
+	// The é would straddle the 75th octet, so it goes on the next line.
+	w.line("DESCRIPTION", strings.Repeat("a", 62)+"é"+strings.Repeat("b", 10))
+	assert.Equal(t, "DESCRIPTION:"+strings.Repeat("a", 62)+"\r\n é"+strings.Repeat("b", 10)+"\r\n", w.String())
+}
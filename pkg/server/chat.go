@@ -0,0 +1,29 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+)
+
+// whatDayAnswer answers a chat command like /whatday Tokyo. It also reports
+// whether the answer is worth showing to everybody, as opposed to help or a
+// complaint about the timezone that only the person asking needs to see.
+func (s *Server) whatDayAnswer(command, text string) (string, bool) {
+	if text == "help" {
+		return fmt.Sprintf("Say %s to find out what day it is, or %s and a timezone or city, like %s Tokyo.", command, command, command), false
+	}
+
+	if text == "" {
+		text = s.DefaultTimeZone
+	}
+
+	timezone, rerr := resolveTimezone(text)
+
+	if rerr != nil {
+		return fmt.Sprintf("%s Try a timezone or a city, like %s Tokyo.", rerr.Message, command), false
+	}
+
+	now := clock.Clock().In(clock.MustLoadLocation(timezone))
+	return fmt.Sprintf("It's %s in %s.", now.Format("Monday, January 2"), clock.Zone{Name: timezone}.DisplayName()), true
+}
@@ -0,0 +1,169 @@
+package server
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const unknownCommandMessage = `You do know you're talking to a robot right? Reply COMMANDS to see what I understand.`
+
+// commandRequest is a parsed inbound message that's been matched to a command.
+type commandRequest struct {
+	// The phone number that sent the message.
+	From string
+
+	// The name the command was invoked with, which might be an alias.
+	Name string
+
+	// Everything after the command name, split on whitespace.
+	Args []string
+}
+
+// Rest returns all of the arguments joined back together, for commands that
+// accept free text.
+func (req commandRequest) Rest() string {
+	return strings.Join(req.Args, " ")
+}
+
+type commandFunc func(s *Server, req commandRequest) (string, error)
+
+type command struct {
+	// The canonical name of the command. Matched case-insensitively.
+	Name string
+
+	// Other names that the command will also respond to.
+	Aliases []string
+
+	// Describes the arguments, e.g. "TIME <hh:mm>".
+	Usage string
+
+	// A short, SMS-sized description of what the command does.
+	Help string
+
+	// The bounds on the number of arguments. MaxArgs < 0 means unlimited.
+	MinArgs int
+	MaxArgs int
+
+	// Only numbers listed in Server.AdminNumbers may run this command.
+	AdminOnly bool
+
+	Handler commandFunc
+}
+
+func (c *command) usage() string {
+	if c.Usage == "" {
+		return strings.ToUpper(c.Name)
+	}
+
+	return c.Usage
+}
+
+type commandRouter struct {
+	commands []*command
+	byName   map[string]*command
+}
+
+func newCommandRouter() *commandRouter {
+	r := &commandRouter{
+		byName: make(map[string]*command),
+	}
+
+	r.Register(&command{
+		Name:    "commands",
+		Aliases: []string{"menu"},
+		Help:    "Lists the commands I understand.",
+		Handler: r.listCommands,
+	})
+
+	r.Register(&command{
+		Name:    "help",
+		Usage:   "HELP <command>",
+		Help:    "Explains how to use a command.",
+		MinArgs: 1,
+		MaxArgs: 1,
+		Handler: r.describeCommand,
+	})
+
+	return r
+}
+
+// Register adds cmd to the router. It panics if the name or any of the
+// aliases have already been taken, since that's always a programming error.
+func (r *commandRouter) Register(cmd *command) {
+	names := append([]string{cmd.Name}, cmd.Aliases...)
+
+	for _, name := range names {
+		name = strings.ToLower(name)
+
+		if _, ok := r.byName[name]; ok {
+			panic(fmt.Sprintf("server: command `%s` registered twice", name))
+		}
+
+		r.byName[name] = cmd
+	}
+
+	r.commands = append(r.commands, cmd)
+}
+
+func (r *commandRouter) lookup(name string) (*command, bool) {
+	cmd, ok := r.byName[strings.ToLower(name)]
+	return cmd, ok
+}
+
+// Route finds the command named by the first word of body and runs it,
+// returning the reply to send back to the sender.
+func (r *commandRouter) Route(s *Server, from, body string) (string, error) {
+	fields := strings.Fields(body)
+
+	if len(fields) < 1 {
+		return unknownCommandMessage, nil
+	}
+
+	cmd, ok := r.lookup(fields[0])
+
+	if !ok || (cmd.AdminOnly && !s.isAdmin(from)) {
+		logger.Infof("unknown request from user: `%s`", body)
+		return unknownCommandMessage, nil
+	}
+
+	args := fields[1:]
+
+	if len(args) < cmd.MinArgs || (cmd.MaxArgs >= 0 && len(args) > cmd.MaxArgs) {
+		return fmt.Sprintf("Usage: %s", cmd.usage()), nil
+	}
+
+	logger.WithField("command", cmd.Name).Info("running command")
+
+	return cmd.Handler(s, commandRequest{
+		From: from,
+		Name: strings.ToLower(fields[0]),
+		Args: args,
+	})
+}
+
+func (r *commandRouter) listCommands(s *Server, req commandRequest) (string, error) {
+	var names []string
+
+	for _, cmd := range r.commands {
+		if cmd.AdminOnly && !s.isAdmin(req.From) {
+			continue
+		}
+
+		names = append(names, strings.ToUpper(cmd.Name))
+	}
+
+	sort.Strings(names)
+
+	return fmt.Sprintf("I understand: %s. Reply HELP <command> for details.", strings.Join(names, ", ")), nil
+}
+
+func (r *commandRouter) describeCommand(s *Server, req commandRequest) (string, error) {
+	cmd, ok := r.lookup(req.Args[0])
+
+	if !ok || (cmd.AdminOnly && !s.isAdmin(req.From)) {
+		return fmt.Sprintf("I don't know the %s command. Reply COMMANDS to see what I understand.", strings.ToUpper(req.Args[0])), nil
+	}
+
+	return fmt.Sprintf("%s: %s", cmd.usage(), cmd.Help), nil
+}
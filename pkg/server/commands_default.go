@@ -0,0 +1,181 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+const notSubscribedMessage = `I don't have you down as a subscriber. Sign up at https://what-day-is-today.com to get a text every morning.`
+
+func registerDefaultCommands(r *commandRouter) {
+	r.Register(&command{
+		Name:    "status",
+		Help:    "Tells you whether you're subscribed and when I'll text you.",
+		Handler: statusCommand,
+	})
+
+	r.Register(&command{
+		Name:    "today",
+		Aliases: []string{"day"},
+		Help:    "Tells you what day it is right now.",
+		Handler: todayCommand,
+	})
+
+	r.Register(&command{
+		Name:      "lookup",
+		Usage:     "LOOKUP <number>",
+		Help:      "Shows the subscription for a phone number.",
+		MinArgs:   1,
+		MaxArgs:   -1,
+		AdminOnly: true,
+		Handler:   lookupCommand,
+	})
+
+	r.Register(&command{
+		Name:      "ban",
+		Usage:     "BAN <number>",
+		Help:      "Stops a phone number from ever getting texts again.",
+		MinArgs:   1,
+		MaxArgs:   -1,
+		AdminOnly: true,
+		Handler:   banCommand,
+	})
+
+	r.Register(&command{
+		Name:      "unban",
+		Usage:     "UNBAN <number>",
+		Help:      "Lifts a ban. The number has to text START to subscribe again.",
+		MinArgs:   1,
+		MaxArgs:   -1,
+		AdminOnly: true,
+		Handler:   unbanCommand,
+	})
+}
+
+func statusCommand(s *Server, req commandRequest) (string, error) {
+	phoneNumber, err := s.managers.PhoneNumbers().Get(req.From)
+
+	if err == managers.ErrRecordNotFound {
+		return notSubscribedMessage, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	switch phoneNumber.Status {
+	case models.StatusPending:
+		return "You haven't confirmed your subscription yet. Reply YES to confirm.", nil
+	case models.StatusActive:
+		return fmt.Sprintf("You're subscribed! I'll text you %s %s time.", describeSchedule(phoneNumber.DeliverySchedule()), phoneNumber.Timezone), nil
+	case models.StatusPaused:
+		if phoneNumber.PausedUntil != nil {
+			return fmt.Sprintf("Your texts are paused until %s. Reply RESUME to start getting them again.", formatResumeDate(phoneNumber.PausedUntil.In(clock.MustLoadLocation(phoneNumber.Timezone)))), nil
+		}
+
+		return "Your texts are paused right now. Reply RESUME to start getting them again.", nil
+	case models.StatusBanned:
+		return bannedMessage, nil
+	default:
+		return "You're unsubscribed right now. Reply START to start getting texts again.", nil
+	}
+}
+
+// describeSchedule says when a schedule goes out, like "every day at 8am" or
+// "at 6:30pm on Saturday and Sunday".
+func describeSchedule(schedule clock.Schedule) string {
+	t := time.Date(2000, 1, 1, schedule.Minute/60, schedule.Minute%60, 0, 0, time.UTC)
+
+	at := t.Format("3:04pm")
+
+	if t.Minute() == 0 {
+		at = t.Format("3pm")
+	}
+
+	if schedule.Days.IsEveryDay() {
+		return "every day at " + at
+	}
+
+	var names []string
+
+	for _, d := range schedule.Days.Days() {
+		names = append(names, d.String())
+	}
+
+	if len(names) > 1 {
+		names = append(names[:len(names)-2], names[len(names)-2]+" and "+names[len(names)-1])
+	}
+
+	return fmt.Sprintf("at %s on %s", at, strings.Join(names, ", "))
+}
+
+func todayCommand(s *Server, req commandRequest) (string, error) {
+	timezone := s.DefaultTimeZone
+
+	if phoneNumber, err := s.managers.PhoneNumbers().Get(req.From); err == nil {
+		timezone = phoneNumber.Timezone
+	} else if err != managers.ErrRecordNotFound {
+		return "", err
+	}
+
+	return fmt.Sprintf("Today is %s.", clock.GetDayInZone(clock.MustLoadLocation(timezone))), nil
+}
+
+func lookupCommand(s *Server, req commandRequest) (string, error) {
+	num := models.CleanPhoneNumber(req.Rest())
+
+	phoneNumber, err := s.managers.PhoneNumbers().Get(num)
+
+	if err == managers.ErrRecordNotFound {
+		return fmt.Sprintf("%s isn't subscribed.", num), nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s: status=%s since=%s timezone=%s last_sent_at=%s", phoneNumber.Number, phoneNumber.Status, formatOptionalTime(phoneNumber.StatusSince()), phoneNumber.Timezone, formatOptionalTime(phoneNumber.LastSentAt)), nil
+}
+
+func banCommand(s *Server, req commandRequest) (string, error) {
+	return updateStatusCommand(s, req, models.StatusBanned)
+}
+
+func unbanCommand(s *Server, req commandRequest) (string, error) {
+	return updateStatusCommand(s, req, models.StatusStopped)
+}
+
+// updateStatusCommand moves the number in the command's arguments to status,
+// if it's allowed to go there.
+func updateStatusCommand(s *Server, req commandRequest, status models.Status) (string, error) {
+	num := models.CleanPhoneNumber(req.Rest())
+
+	phoneNumber, err := s.managers.PhoneNumbers().Get(num)
+
+	if err == managers.ErrRecordNotFound {
+		return fmt.Sprintf("%s isn't subscribed.", num), nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if phoneNumber.Status == status {
+		return fmt.Sprintf("%s is already %s.", num, status), nil
+	} else if !phoneNumber.Status.CanTransitionTo(status) {
+		return fmt.Sprintf("%s is %s, so it can't be %s.", num, phoneNumber.Status, status), nil
+	}
+
+	previous := phoneNumber.Status
+
+	if err := s.managers.PhoneNumbers().UpdateStatus(&phoneNumber, status, clock.Clock()); err != nil {
+		return "", err
+	}
+
+	s.recordEvent(num, models.EventAdminAction, models.AdminActor(req.From), map[string]string{
+		"command": req.Name,
+		"from":    string(previous),
+		"to":      string(status),
+	})
+
+	return fmt.Sprintf("%s is %s now.", num, status), nil
+}
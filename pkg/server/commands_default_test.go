@@ -0,0 +1,46 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBanAndUnbanCommands(t *testing.T) {
+	withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+		s := newTestCommandServer()
+		registerDefaultCommands(s.commands)
+
+		assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusActive}))
+
+		reply, err := s.commands.Route(s, "+15554443333", "ban +15554443333")
+		assert.NoError(t, err)
+		assert.Equal(t, unknownCommandMessage, reply)
+
+		reply, err = s.commands.Route(s, "+15550001111", "ban (555) 444-3333")
+		assert.NoError(t, err)
+		assert.Equal(t, "+15554443333 is banned now.", reply)
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get("+15554443333")
+		assert.NoError(t, err)
+		assert.Equal(t, models.StatusBanned, phoneNumber.Status)
+		assert.Equal(t, "2015-05-01T19:00:00Z", formatOptionalTime(phoneNumber.StatusSince()))
+
+		reply, err = s.commands.Route(s, "+15554443333", "status")
+		assert.NoError(t, err)
+		assert.Equal(t, bannedMessage, reply)
+
+		reply, err = s.commands.Route(s, "+15550001111", "lookup +15554443333")
+		assert.NoError(t, err)
+		assert.Equal(t, "+15554443333: status=banned since=2015-05-01T19:00:00Z timezone=UTC last_sent_at=never", reply)
+
+		reply, err = s.commands.Route(s, "+15550001111", "unban +15554443333")
+		assert.NoError(t, err)
+		assert.Equal(t, "+15554443333 is stopped now.", reply)
+
+		reply, err = s.commands.Route(s, "+15550001111", "unban +15554443333")
+		assert.NoError(t, err)
+		assert.Equal(t, "+15554443333 is already stopped.", reply)
+	})
+}
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/bradhe/what-day-is-it/pkg/tokens"
+)
+
+func registerManageCommands(r *commandRouter) {
+	r.Register(&command{
+		Name:    "manage",
+		Aliases: []string{"settings"},
+		Help:    "Sends a link to change your settings on the web.",
+		Handler: manageCommand,
+	})
+}
+
+func manageCommand(s *Server, req commandRequest) (string, error) {
+	if !tokens.NewSigner(s.SigningSecret).IsConfigured() {
+		return "Sorry, changing your settings on the web isn't available right now. Reply COMMANDS to see what you can do by text.", nil
+	}
+
+	phoneNumber, err := s.managers.PhoneNumbers().Get(req.From)
+
+	if err == managers.ErrRecordNotFound {
+		return notSubscribedMessage, nil
+	} else if err != nil {
+		return "", err
+	} else if phoneNumber.Status == models.StatusBanned {
+		return bannedMessage, nil
+	}
+
+	return fmt.Sprintf("Change your settings here: %s The link works once and expires in %d minutes.", s.manageLink(phoneNumber.Number), int(ManageLinkLifetime.Minutes())), nil
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+const pauseUsage = "PAUSE 7d or PAUSE UNTIL yyyy-mm-dd"
+
+func registerPauseCommands(r *commandRouter) {
+	r.Register(&command{
+		Name:    "pause",
+		Aliases: []string{"snooze"},
+		Usage:   pauseUsage,
+		Help:    "Stops the texts for a while without unsubscribing you.",
+		MaxArgs: 2,
+		Handler: pauseCommand,
+	})
+
+	r.Register(&command{
+		Name:    "resume",
+		Help:    "Starts the texts again after a pause.",
+		Handler: resumeCommand,
+	})
+}
+
+func pauseCommand(s *Server, req commandRequest) (string, error) {
+	phoneNumber, err := s.managers.PhoneNumbers().Get(req.From)
+
+	if err == managers.ErrRecordNotFound {
+		return notSubscribedMessage, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	until, err := parseResumeDate(req.Rest(), clock.Clock(), clock.MustLoadLocation(phoneNumber.Timezone))
+
+	switch err {
+	case nil:
+		// Handled below.
+	case errPauseTooLong:
+		return fmt.Sprintf("I can only pause for up to %d days. Reply STOP if you're done with me.", MaxPauseDays), nil
+	default:
+		return fmt.Sprintf("I didn't get that. Try %s.", pauseUsage), nil
+	}
+
+	switch err := s.pause(&phoneNumber, until, models.ActorSubscriber); err {
+	case nil:
+		// Handled below.
+	case errNotPausable:
+		return "You're not getting texts right now, so there's nothing to pause.", nil
+	default:
+		return "", err
+	}
+
+	if until == nil {
+		return "Okay, I'll stop texting you until you reply RESUME.", nil
+	}
+
+	return fmt.Sprintf("Okay, I'll stop texting you until %s. Reply RESUME to start again sooner.", formatResumeDate(*until)), nil
+}
+
+func resumeCommand(s *Server, req commandRequest) (string, error) {
+	phoneNumber, err := s.managers.PhoneNumbers().Get(req.From)
+
+	if err == managers.ErrRecordNotFound {
+		return notSubscribedMessage, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	switch err := s.resume(&phoneNumber, models.ActorSubscriber); err {
+	case nil:
+		// Handled below.
+	case errNotPaused:
+		return "You're not paused. Reply STATUS to see what's going on.", nil
+	default:
+		return "", err
+	}
+
+	// They're getting today's message in the reply so don't send another.
+	s.managers.PhoneNumbers().UpdateSent(&phoneNumber, clock.Clock())
+
+	return WelcomeBackMessage(phoneNumber), nil
+}
@@ -0,0 +1,79 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPauseCommand(t *testing.T) {
+	tests := []struct {
+		name   string
+		status models.Status
+		body   string
+		reply  string
+		after  models.Status
+	}{
+		{"for a week", models.StatusActive, "pause 7d", "Okay, I'll stop texting you until Monday, October 26. Reply RESUME to start again sooner.", models.StatusPaused},
+		{"until a date", models.StatusActive, "PAUSE UNTIL 2026-11-01", "Okay, I'll stop texting you until Sunday, November 1. Reply RESUME to start again sooner.", models.StatusPaused},
+		{"indefinitely", models.StatusActive, "snooze", "Okay, I'll stop texting you until you reply RESUME.", models.StatusPaused},
+		{"nonsense", models.StatusActive, "pause for a bit", "Usage: PAUSE 7d or PAUSE UNTIL yyyy-mm-dd", models.StatusActive},
+		{"bad date", models.StatusActive, "pause until someday", "I didn't get that. Try PAUSE 7d or PAUSE UNTIL yyyy-mm-dd.", models.StatusActive},
+		{"too long", models.StatusActive, "pause 60w", "I can only pause for up to 365 days. Reply STOP if you're done with me.", models.StatusActive},
+		{"while stopped", models.StatusStopped, "pause 7d", "You're not getting texts right now, so there's nothing to pause.", models.StatusStopped},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+				s := newTestCommandServer()
+				registerPauseCommands(s.commands)
+
+				assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+15554443333", Timezone: "America/Los_Angeles", Status: test.status}))
+
+				reply, err := s.commands.Route(s, "+15554443333", test.body)
+				assert.NoError(t, err)
+				assert.Equal(t, test.reply, reply)
+
+				phoneNumber, _ := s.managers.PhoneNumbers().Get("+15554443333")
+				assert.Equal(t, test.after, phoneNumber.Status)
+			})
+		})
+	}
+}
+
+func TestResumeCommand(t *testing.T) {
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		s := newTestCommandServer()
+		registerDefaultCommands(s.commands)
+		registerPauseCommands(s.commands)
+
+		reply, err := s.commands.Route(s, "+15554443333", "resume")
+		assert.NoError(t, err)
+		assert.Equal(t, notSubscribedMessage, reply)
+
+		assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+15554443333", Timezone: "America/Los_Angeles", Status: models.StatusActive}))
+
+		reply, err = s.commands.Route(s, "+15554443333", "resume")
+		assert.NoError(t, err)
+		assert.Equal(t, "You're not paused. Reply STATUS to see what's going on.", reply)
+
+		_, err = s.commands.Route(s, "+15554443333", "pause until 2026-11-01")
+		assert.NoError(t, err)
+
+		reply, err = s.commands.Route(s, "+15554443333", "status")
+		assert.NoError(t, err)
+		assert.Equal(t, "Your texts are paused until Sunday, November 1. Reply RESUME to start getting them again.", reply)
+
+		reply, err = s.commands.Route(s, "+15554443333", "resume")
+		assert.NoError(t, err)
+		assert.Equal(t, "Welcome back! Today is Monday.", reply)
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get("+15554443333")
+		assert.NoError(t, err)
+		assert.True(t, phoneNumber.IsSendable())
+		assert.Nil(t, phoneNumber.PausedUntil)
+		assert.NotNil(t, phoneNumber.LastSentAt)
+	})
+}
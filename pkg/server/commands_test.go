@@ -0,0 +1,93 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCommandServer() *Server {
+	s := &Server{
+		DefaultTimeZone: "UTC",
+		AdminNumbers:    []string{"+15550001111"},
+		managers:        memory.New(),
+		commands:        newCommandRouter(),
+	}
+
+	s.commands.Register(&command{
+		Name:    "echo",
+		Aliases: []string{"say"},
+		Usage:   "ECHO <text>",
+		Help:    "Repeats what you said.",
+		MinArgs: 1,
+		MaxArgs: -1,
+		Handler: func(s *Server, req commandRequest) (string, error) {
+			return req.Name + ": " + req.Rest(), nil
+		},
+	})
+
+	s.commands.Register(&command{
+		Name:      "secret",
+		Help:      "Admins only.",
+		AdminOnly: true,
+		Handler: func(s *Server, req commandRequest) (string, error) {
+			return "the secret", nil
+		},
+	})
+
+	return s
+}
+
+func TestCommandRouterRoute(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		body     string
+		expected string
+	}{
+		{"by name", "+15554443333", "echo hello world", "echo: hello world"},
+		{"case insensitive", "+15554443333", "ECHO hello", "echo: hello"},
+		{"by alias", "+15554443333", "Say hi", "say: hi"},
+		{"too few arguments", "+15554443333", "echo", "Usage: ECHO <text>"},
+		{"too many arguments", "+15554443333", "help echo please", "Usage: HELP <command>"},
+		{"unknown command", "+15554443333", "what day is it", unknownCommandMessage},
+		{"empty message", "+15554443333", "   ", unknownCommandMessage},
+		{"admin only as admin", "+15550001111", "secret", "the secret"},
+		{"admin only as someone else", "+15554443333", "secret", unknownCommandMessage},
+		{"help for a command", "+15554443333", "help say", "ECHO <text>: Repeats what you said."},
+		{"help for admin command as someone else", "+15554443333", "help secret", "I don't know the SECRET command. Reply COMMANDS to see what I understand."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestCommandServer()
+
+			reply, err := s.commands.Route(s, test.from, test.body)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, reply)
+		})
+	}
+}
+
+func TestCommandRouterListCommandsHidesAdminCommands(t *testing.T) {
+	s := newTestCommandServer()
+
+	reply, err := s.commands.Route(s, "+15554443333", "commands")
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(reply, "ECHO"))
+	assert.False(t, strings.Contains(reply, "SECRET"))
+
+	reply, err = s.commands.Route(s, "+15550001111", "commands")
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(reply, "SECRET"))
+}
+
+func TestCommandRouterRegisterPanicsOnDuplicates(t *testing.T) {
+	r := newCommandRouter()
+
+	assert.Panics(t, func() {
+		r.Register(&command{Name: "menu"})
+	})
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+func registerTimezoneCommands(r *commandRouter) {
+	r.Register(&command{
+		Name:    "tz",
+		Aliases: []string{"timezone"},
+		Usage:   "TZ <city or zone>",
+		Help:    "Changes your timezone, e.g. TZ Tokyo or TZ Europe/Berlin.",
+		MaxArgs: -1,
+		Handler: timezoneCommand,
+	})
+}
+
+func timezoneCommand(s *Server, req commandRequest) (string, error) {
+	phoneNumber, err := s.managers.PhoneNumbers().Get(req.From)
+
+	if err == managers.ErrRecordNotFound {
+		return notSubscribedMessage, nil
+	} else if err != nil {
+		return "", err
+	}
+
+	if len(req.Args) < 1 {
+		return fmt.Sprintf("Your timezone is %s. Reply TZ <city> to change it.", phoneNumber.Timezone), nil
+	}
+
+	place := req.Rest()
+	candidates := clock.LookupPlace(place)
+
+	switch len(candidates) {
+	case 0:
+		return fmt.Sprintf("I couldn't figure out where %s is. Try a city like TZ Tokyo or a zone like TZ Europe/Berlin.", place), nil
+	case 1:
+		// Handled below.
+	default:
+		var options []string
+
+		for _, candidate := range candidates {
+			options = append(options, "TZ "+candidate)
+		}
+
+		return fmt.Sprintf("%s could mean a few places. Reply %s.", place, strings.Join(options, " or ")), nil
+	}
+
+	timezone, previous := candidates[0], phoneNumber.Timezone
+
+	if err := s.managers.PhoneNumbers().UpdateTimezone(&phoneNumber, timezone, clock.Clock()); err != nil {
+		logger.WithError(err).Error("failed to update timezone")
+		return "", err
+	}
+
+	s.recordEvent(phoneNumber.Number, models.EventTimezoneChanged, models.ActorSubscriber, map[string]string{
+		"from": previous,
+		"to":   timezone,
+	})
+
+	logger.WithField("timezone", timezone).Info("user changed timezone")
+
+	return fmt.Sprintf("Got it, you're on %s time now. It's %s there.", timezone, clock.GetDayInZone(clock.MustLoadLocation(timezone))), nil
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimezoneCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		reply    string
+		timezone string
+	}{
+		{"city", "tz Tokyo", "Got it, you're on Asia/Tokyo time now. It's Saturday there.", "Asia/Tokyo"},
+		{"abbreviation", "TZ cet", "Got it, you're on Europe/Berlin time now. It's Friday there.", "Europe/Berlin"},
+		{"zone name", "timezone America/New_York", "Got it, you're on America/New_York time now. It's Friday there.", "America/New_York"},
+		{"ambiguous", "tz Portland", "Portland could mean a few places. Reply TZ America/New_York or TZ America/Los_Angeles.", "UTC"},
+		{"unknown", "tz Atlantis", "I couldn't figure out where Atlantis is. Try a city like TZ Tokyo or a zone like TZ Europe/Berlin.", "UTC"},
+		{"no arguments", "tz", "Your timezone is UTC. Reply TZ <city> to change it.", "UTC"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+				s := newTestCommandServer()
+				registerTimezoneCommands(s.commands)
+
+				assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", TimezoneGuessed: true, Status: models.StatusActive}))
+
+				reply, err := s.commands.Route(s, "+15554443333", test.body)
+				assert.NoError(t, err)
+				assert.Equal(t, test.reply, reply)
+
+				phoneNumber, _ := s.managers.PhoneNumbers().Get("+15554443333")
+				assert.Equal(t, test.timezone, phoneNumber.Timezone)
+
+				// Picking a timezone means we don't have to guess anymore.
+				assert.Equal(t, test.timezone == "UTC", phoneNumber.TimezoneGuessed)
+			})
+		})
+	}
+}
+
+func TestTimezoneCommandRequiresSubscription(t *testing.T) {
+	s := newTestCommandServer()
+	registerTimezoneCommands(s.commands)
+
+	reply, err := s.commands.Route(s, "+15554443333", "tz Tokyo")
+	assert.NoError(t, err)
+	assert.Equal(t, notSubscribedMessage, reply)
+}
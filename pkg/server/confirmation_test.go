@@ -0,0 +1,81 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostSubscribeRequiresConfirmation(t *testing.T) {
+	withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+		sender := &recordingSender{}
+		s := &Server{DefaultTimeZone: "UTC", ConfirmationWindow: time.Hour, managers: memory.New(), Senders: Senders{models.ChannelSMS: sender}}
+
+		code, resp := postSubscribe(s, `{"number": "+14155551234", "timezone": "America/Los_Angeles"}`)
+		assert.Equal(t, http.StatusOK, code)
+		assert.False(t, resp.Subscribed)
+		assert.True(t, resp.ConfirmationRequired)
+		assert.Equal(t, []sentMessage{{"+14155551234", confirmationRequestMessage}}, sender.Sent())
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get("+14155551234")
+		assert.NoError(t, err)
+		assert.False(t, phoneNumber.IsSendable())
+		assert.True(t, phoneNumber.IsAwaitingConfirmation())
+		assert.Equal(t, "2015-05-01T20:00:00Z", phoneNumber.ConfirmationExpiresAt.Format(time.RFC3339))
+
+		// Signing up again right away doesn't text them again.
+		code, _ = postSubscribe(s, `{"number": "+14155551234", "timezone": "Asia/Tokyo"}`)
+		assert.Equal(t, http.StatusTooManyRequests, code)
+		assert.Len(t, sender.Sent(), 1)
+
+		// A bit later it just asks again, and nothing changes until they
+		// confirm.
+		withClockTime(t, mustParseTime("2015-05-01T19:01:00Z"), func(t *testing.T) {
+			_, resp = postSubscribe(s, `{"number": "+14155551234", "timezone": "Asia/Tokyo"}`)
+			assert.True(t, resp.ConfirmationRequired)
+			assert.Equal(t, "America/Los_Angeles", resp.Timezone)
+			assert.Len(t, sender.Sent(), 2)
+		})
+
+		reply, err := s.handleKeyword(parseKeyword("YES"), models.ChannelSMS, "+14155551234")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(reply, "Yo!"))
+		assert.True(t, strings.Contains(reply, "Today is Friday by the way."))
+
+		phoneNumber, err = s.managers.PhoneNumbers().Get("+14155551234")
+		assert.NoError(t, err)
+		assert.True(t, phoneNumber.IsSendable())
+		assert.False(t, phoneNumber.IsAwaitingConfirmation())
+		assert.Equal(t, "2015-05-01T19:00:00Z", phoneNumber.StatusSince().UTC().Format(time.RFC3339))
+		assert.Nil(t, phoneNumber.ConfirmationExpiresAt)
+		assert.NotNil(t, phoneNumber.LastSentAt)
+		assert.Equal(t, "America/Los_Angeles", phoneNumber.Timezone)
+	})
+}
+
+func TestUnconfirmedSubscriptionsExpire(t *testing.T) {
+	s := &Server{DefaultTimeZone: "UTC", ConfirmationWindow: time.Hour, managers: memory.New(), Senders: Senders{models.ChannelSMS: &recordingSender{}}}
+
+	withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+		postSubscribe(s, `{"number": "+14155551234", "timezone": "America/Los_Angeles"}`)
+	})
+
+	withClockTime(t, mustParseTime("2015-05-01T20:00:00Z"), func(t *testing.T) {
+		_, err := s.managers.PhoneNumbers().Get("+14155551234")
+		assert.Equal(t, managers.ErrRecordNotFound, err)
+
+		// Once it's gone they can sign up from scratch.
+		_, resp := postSubscribe(s, `{"number": "+14155551234", "timezone": "America/Los_Angeles"}`)
+		assert.True(t, resp.ConfirmationRequired)
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get("+14155551234")
+		assert.NoError(t, err)
+		assert.Equal(t, "2015-05-01T21:00:00Z", phoneNumber.ConfirmationExpiresAt.Format(time.RFC3339))
+	})
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/discord"
+)
+
+// DiscordCommand is the name that the app's slash command is registered
+// under.
+const DiscordCommand = "whatday"
+
+// Discord never sends an interaction anywhere near this big.
+const maxDiscordInteractionSize = 64 * 1024
+
+// discordAnswer answers an interaction. Discord pings the endpoint when it's
+// set up, and after that it's commands.
+func (s *Server) discordAnswer(interaction discord.Interaction) (discord.InteractionResponse, bool) {
+	switch {
+	case interaction.Type == discord.InteractionPing:
+		return discord.InteractionResponse{Type: discord.ResponsePong}, true
+	case interaction.Type != discord.InteractionApplicationCommand || interaction.Data == nil || interaction.Data.Name != DiscordCommand:
+		return discord.InteractionResponse{}, false
+	}
+
+	answer, public := s.whatDayAnswer("/"+DiscordCommand, strings.TrimSpace(interaction.Data.Option("timezone")))
+	callback := discord.InteractionCallback{Content: answer}
+
+	if !public {
+		callback.Flags = discord.FlagEphemeral
+	}
+
+	return discord.InteractionResponse{Type: discord.ResponseChannelMessage, Data: &callback}, true
+}
+
+// PostDiscordInteraction handles interactions with the Discord app, once we're
+// sure that they came from Discord.
+func (s *Server) PostDiscordInteraction(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxDiscordInteractionSize))
+
+	if err != nil {
+		logger.WithError(err).Error("failed to read interaction")
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Couldn't read the request body.")
+		return
+	}
+
+	signature := r.Header.Get("X-Signature-Ed25519")
+	timestamp := r.Header.Get("X-Signature-Timestamp")
+
+	// Discord checks that we turn away requests with bad signatures before
+	// it'll let the endpoint be used, so this has to be a 401.
+	if err := discord.VerifyRequest(s.DiscordPublicKey, signature, timestamp, body, *clock.Clock()); err != nil {
+		logger.WithError(err).Warn("rejected interaction")
+		writeError(w, http.StatusUnauthorized, ErrorCodeUnauthorized, "The request isn't signed by Discord.")
+		return
+	}
+
+	var interaction discord.Interaction
+
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "The request body must be JSON.")
+		return
+	}
+
+	if resp, ok := s.discordAnswer(interaction); ok {
+		writeJSON(w, resp)
+	} else {
+		logger.WithField("type", interaction.Type).Warn("unknown interaction")
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "We don't know that command.")
+	}
+}
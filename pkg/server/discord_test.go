@@ -0,0 +1,116 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/discord"
+	"github.com/stretchr/testify/assert"
+)
+
+const testDiscordWebhookURL = "https://discord.com/api/webhooks/123456789012345678/abc-DEF_123"
+
+// doDiscordInteraction sends an interaction signed with key, like Discord
+// would.
+func doDiscordInteraction(s *Server, key ed25519.PrivateKey, body string) *httptest.ResponseRecorder {
+	timestamp := strconv.FormatInt(clock.Clock().Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/discord/interactions", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-Timestamp", timestamp)
+	req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, []byte(timestamp+body))))
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+func newTestDiscordServer(t *testing.T) (*Server, ed25519.PrivateKey) {
+	public, private, err := ed25519.GenerateKey(nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, _ := newTestAPIServer()
+	s.DiscordPublicKey = public
+
+	return s, private
+}
+
+func TestPostDiscordInteraction(t *testing.T) {
+	s, key := newTestDiscordServer(t)
+
+	cases := []struct {
+		body string
+		resp discord.InteractionResponse
+	}{
+		{
+			`{"type": 1}`,
+			discord.InteractionResponse{Type: discord.ResponsePong},
+		},
+		{
+			`{"type": 2, "data": {"name": "whatday"}}`,
+			discord.InteractionResponse{Type: discord.ResponseChannelMessage, Data: &discord.InteractionCallback{Content: "It's Monday, October 19 in UTC."}},
+		},
+		{
+			`{"type": 2, "data": {"name": "whatday", "options": [{"name": "timezone", "type": 3, "value": "Tokyo"}]}}`,
+			discord.InteractionResponse{Type: discord.ResponseChannelMessage, Data: &discord.InteractionCallback{Content: "It's Tuesday, October 20 in Tokyo."}},
+		},
+		{
+			`{"type": 2, "data": {"name": "whatday", "options": [{"name": "timezone", "type": 3, "value": "Atlantis"}]}}`,
+			discord.InteractionResponse{Type: discord.ResponseChannelMessage, Data: &discord.InteractionCallback{Content: "Unknown timezone Atlantis. Try a timezone or a city, like /whatday Tokyo.", Flags: discord.FlagEphemeral}},
+		},
+	}
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		for _, c := range cases {
+			w := doDiscordInteraction(s, key, c.body)
+			assert.Equal(t, http.StatusOK, w.Code, c.body)
+
+			var resp discord.InteractionResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, c.resp, resp, c.body)
+		}
+
+		w := doDiscordInteraction(s, key, `{"type": 2, "data": {"name": "whatweek"}}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPostDiscordInteractionRequiresSignature(t *testing.T) {
+	s, _ := newTestDiscordServer(t)
+	_, other, _ := ed25519.GenerateKey(nil)
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		w := doDiscordInteraction(s, other, `{"type": 1}`)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, ErrorCodeUnauthorized, decodeAPIError(w).Code)
+
+		// Interactions are off without a key.
+		s.DiscordPublicKey = nil
+
+		w = doDiscordInteraction(s, other, `{"type": 1}`)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+func TestPostSubscriptionDeliveryTime(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"channel": "discord", "webhook_url": "`+testDiscordWebhookURL+`", "delivery_time": "25:00"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrorCodeInvalidDeliveryTime, decodeAPIError(w).Code)
+
+	w = doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"channel": "discord", "webhook_url": "`+testWebhookURL+`"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrorCodeInvalidWebhookURL, decodeAPIError(w).Code)
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmailSubscriptionErrors(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	for _, body := range []string{
+		`{"channel": "email"}`,
+		`{"channel": "email", "email": "someone@example"}`,
+		`{"channel": "email", "email": "Someone <someone@example.com>"}`,
+		`{"channel": "email", "number": "+14155551234"}`,
+	} {
+		w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+		assert.Equal(t, ErrorCodeInvalidEmail, decodeAPIError(w).Code, body)
+	}
+}
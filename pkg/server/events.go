@@ -0,0 +1,132 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/gorilla/mux"
+)
+
+// DefaultEventLimit is how many events we return when nobody asks for a
+// specific number of them.
+const DefaultEventLimit = 50
+
+// MaxEventLimit keeps a single request from reading a number's entire history.
+const MaxEventLimit = 1000
+
+// RecordEvent adds an event to number's history. History is nice to have, so
+// failing to record it shouldn't fail whatever we were in the middle of.
+func RecordEvent(m managers.Managers, number string, eventType models.EventType, actor string, metadata map[string]string) {
+	event := models.Event{
+		Number:   number,
+		Type:     eventType,
+		At:       *clock.Clock(),
+		Actor:    actor,
+		Metadata: metadata,
+	}
+
+	if err := m.Events().Append(event); err != nil {
+		logger.WithError(err).WithField("type", string(eventType)).Error("failed to record event")
+	}
+}
+
+func (s *Server) recordEvent(number string, eventType models.EventType, actor string, metadata map[string]string) {
+	RecordEvent(s.managers, number, eventType, actor, metadata)
+}
+
+// requireAdmin only lets requests carrying the admin token through to h. The
+// admin API is turned off entirely if there's no token.
+func (s *Server) requireAdmin(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+		if s.AdminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.AdminToken)) != 1 {
+			logger.Warn("unauthorized admin request")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		h(w, r)
+	}
+}
+
+type EventResponse struct {
+	Type     models.EventType  `json:"type"`
+	At       time.Time         `json:"at"`
+	Actor    string            `json:"actor"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type GetEventsResponse struct {
+	Number string `json:"number,omitempty"`
+
+	// Newest first.
+	Events []EventResponse `json:"events"`
+
+	Error string `json:"error,omitempty"`
+}
+
+func (s *Server) GetEvents(w http.ResponseWriter, r *http.Request) {
+	var resp GetEventsResponse
+
+	num := models.CleanSubscriberID(mux.Vars(r)["number"])
+
+	if !models.IsValidSubscriberID(num) {
+		logger.Error("invalid phone number")
+		w.WriteHeader(http.StatusBadRequest)
+
+		resp.Error = "Invalid phone number."
+		w.Write(Dump(resp))
+
+		return
+	}
+
+	limit := DefaultEventLimit
+
+	if str := r.URL.Query().Get("limit"); str != "" {
+		if n, err := strconv.Atoi(str); err != nil || n < 1 {
+			w.WriteHeader(http.StatusBadRequest)
+
+			resp.Error = "Invalid limit."
+			w.Write(Dump(resp))
+
+			return
+		} else if n < MaxEventLimit {
+			limit = n
+		} else {
+			limit = MaxEventLimit
+		}
+	}
+
+	events, err := s.managers.Events().List(num, limit)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to list events")
+		w.WriteHeader(http.StatusInternalServerError)
+
+		resp.Error = "An internal error occured."
+		w.Write(Dump(resp))
+
+		return
+	}
+
+	resp.Number = num
+	resp.Events = make([]EventResponse, len(events))
+
+	for i, event := range events {
+		resp.Events[i] = EventResponse{
+			Type:     event.Type,
+			At:       event.At.UTC(),
+			Actor:    event.Actor,
+			Metadata: event.Metadata,
+		}
+	}
+
+	w.Write(Dump(resp))
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func getEvents(s *Server, path, token string) (int, GetEventsResponse) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	var resp GetEventsResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	return w.Code, resp
+}
+
+func TestEventsAreRecorded(t *testing.T) {
+	s := NewServer(memory.New(), &recordingSender{}, false, "")
+	s.AdminNumbers = []string{"+15550001111"}
+
+	withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+		_, _, err := s.subscribe(models.ChannelSMS, "+14155551234", "", nil, VerifyByReply)
+		assert.NoError(t, err)
+
+		_, err = s.handleKeyword(keywordStart, models.ChannelSMS, "+14155551234")
+		assert.NoError(t, err)
+	})
+
+	withClockTime(t, mustParseTime("2015-05-01T20:00:00Z"), func(t *testing.T) {
+		_, err := s.commands.Route(s, "+14155551234", "tz Tokyo")
+		assert.NoError(t, err)
+	})
+
+	withClockTime(t, mustParseTime("2015-05-01T21:00:00Z"), func(t *testing.T) {
+		_, err := s.handleKeyword(keywordStop, models.ChannelSMS, "+14155551234")
+		assert.NoError(t, err)
+	})
+
+	withClockTime(t, mustParseTime("2015-05-01T22:00:00Z"), func(t *testing.T) {
+		_, err := s.commands.Route(s, "+15550001111", "ban +14155551234")
+		assert.NoError(t, err)
+	})
+
+	events, err := s.managers.Events().List("+14155551234", 10)
+	assert.NoError(t, err)
+
+	var types []models.EventType
+	var actors []string
+
+	for _, event := range events {
+		types = append(types, event.Type)
+		actors = append(actors, event.Actor)
+	}
+
+	assert.Equal(t, []models.EventType{models.EventAdminAction, models.EventStopReceived, models.EventTimezoneChanged, models.EventConfirmed, models.EventSubscribed}, types)
+	assert.Equal(t, []string{"admin:+15550001111", models.ActorSubscriber, models.ActorSubscriber, models.ActorSubscriber, models.ActorWeb}, actors)
+	assert.Equal(t, map[string]string{"command": "ban", "from": "stopped", "to": "banned"}, events[0].Metadata)
+	assert.Equal(t, map[string]string{"from": "America/Los_Angeles", "to": "Asia/Tokyo"}, events[2].Metadata)
+
+	events, err = s.managers.Events().List("+14155551234", 1)
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+func TestGetEvents(t *testing.T) {
+	s := NewServer(memory.New(), &recordingSender{}, false, "")
+
+	withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+		s.recordEvent("+14155551234", models.EventSubscribed, models.ActorWeb, map[string]string{"timezone": "UTC"})
+		s.recordEvent("+14155551234", models.EventConfirmed, models.ActorSubscriber, nil)
+	})
+
+	// The admin API is off until there's a token.
+	code, _ := getEvents(s, "/api/admin/phone-numbers/+14155551234/events", "")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	s.AdminToken = "sekrit"
+
+	code, _ = getEvents(s, "/api/admin/phone-numbers/+14155551234/events", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	code, resp := getEvents(s, "/api/admin/phone-numbers/+14155551234/events", "sekrit")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "+14155551234", resp.Number)
+	assert.Equal(t, []EventResponse{
+		{Type: models.EventConfirmed, At: *mustParseTime("2015-05-01T19:00:00Z"), Actor: models.ActorSubscriber},
+		{Type: models.EventSubscribed, At: *mustParseTime("2015-05-01T19:00:00Z"), Actor: models.ActorWeb, Metadata: map[string]string{"timezone": "UTC"}},
+	}, resp.Events)
+
+	code, resp = getEvents(s, "/api/admin/phone-numbers/4155551234/events?limit=1", "sekrit")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, resp.Events, 1)
+
+	code, _ = getEvents(s, "/api/admin/phone-numbers/+14155551234/events?limit=zero", "sekrit")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, resp = getEvents(s, "/api/admin/phone-numbers/nope/events", "sekrit")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "Invalid phone number.", resp.Error)
+}
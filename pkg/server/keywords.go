@@ -0,0 +1,137 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+type keyword int
+
+const (
+	keywordNone keyword = iota
+	keywordStop
+	keywordStart
+	keywordHelp
+)
+
+// Carriers require that we honor all of these, regardless of case, when they
+// make up the entire message.
+var keywords = map[string]keyword{
+	"stop":        keywordStop,
+	"stopall":     keywordStop,
+	"unsubscribe": keywordStop,
+	"cancel":      keywordStop,
+	"end":         keywordStop,
+	"quit":        keywordStop,
+	"start":       keywordStart,
+	"yes":         keywordStart,
+	"unstop":      keywordStart,
+	"help":        keywordHelp,
+	"info":        keywordHelp,
+}
+
+const (
+	stopConfirmation  = `Okay, I'll stop reminding you starting...NOW! Reply START if you change your mind.`
+	startConfirmation = `Welcome back! Every morning I'll text you what day it is. Reply HELP for help or STOP to make me stop.`
+	bannedMessage     = `This number can't subscribe to What Day Is It.`
+	helpMessage       = `What Day Is It: I text you the day of the week every morning. Reply COMMANDS for more, STOP to unsubscribe or START to resubscribe. Msg & data rates may apply.`
+)
+
+// keywordReply is what we say back to k on channel. Callers can't reply to
+// anything, so they get their own wording.
+func keywordReply(k keyword, channel models.Channel) string {
+	if channel == models.ChannelVoice {
+		switch k {
+		case keywordStop:
+			return voiceStopConfirmation
+		case keywordStart:
+			return voiceStartConfirmation
+		default:
+			return voiceHelpMessage
+		}
+	}
+
+	switch k {
+	case keywordStop:
+		return stopConfirmation
+	case keywordStart:
+		return startConfirmation
+	default:
+		return helpMessage
+	}
+}
+
+func parseKeyword(str string) keyword {
+	// People like to be emphatic when they want us to go away.
+	str = strings.ToLower(strings.Trim(str, " \t\r\n.!"))
+
+	if k, ok := keywords[str]; ok {
+		return k
+	}
+
+	return keywordNone
+}
+
+// handleKeyword applies the opt-out, opt-in or help keyword sent from address
+// on channel and returns the confirmation message that carriers require us to
+// reply with.
+func (s *Server) handleKeyword(k keyword, channel models.Channel, address string) (string, error) {
+	from := models.SubscriberID(channel, address)
+
+	switch k {
+	case keywordStop:
+		if phoneNumber, err := s.managers.PhoneNumbers().Get(from); err == managers.ErrRecordNotFound {
+			// We never knew them in the first place, but they still get a confirmation.
+			logger.Warn("stop message received from unknown phone number")
+		} else if err != nil {
+			logger.WithError(err).Error("failed to find phone number associated with Twilio webhook request")
+			return "", err
+		} else if phoneNumber.Status == models.StatusBanned {
+			// STOP is how a ban gets lifted, so they can't do it themselves.
+			logger.Info("banned phone number asked us to stop")
+		} else if err := s.managers.PhoneNumbers().UpdateStatus(&phoneNumber, models.StatusStopped, clock.Clock()); err != nil {
+			logger.WithError(err).Error("failed to update record as stopped")
+			return "", err
+		} else {
+			s.recordEvent(from, models.EventStopReceived, models.ActorSubscriber, nil)
+		}
+
+		logger.Info("user unsubscribed")
+		return keywordReply(keywordStop, channel), nil
+	case keywordStart:
+		if phoneNumber, err := s.managers.PhoneNumbers().Get(from); err == managers.ErrRecordNotFound {
+			return s.subscribeByMessage(channel, address)
+		} else if err == nil && phoneNumber.IsAwaitingConfirmation() {
+			return s.confirmBySMS(phoneNumber)
+		} else if err == nil && phoneNumber.Status == models.StatusBanned {
+			logger.Warn("banned phone number tried to resubscribe")
+			return bannedMessage, nil
+		} else if err != nil {
+			logger.WithError(err).Error("failed to find phone number associated with Twilio webhook request")
+			return "", err
+		} else if err := s.managers.PhoneNumbers().Resubscribe(&phoneNumber, clock.Clock()); err != nil {
+			logger.WithError(err).Error("failed to update record as sendable")
+			return "", err
+		} else {
+			s.recordEvent(from, models.EventResubscribed, models.ActorSubscriber, nil)
+		}
+
+		logger.Info("user resubscribed")
+		return keywordReply(keywordStart, channel), nil
+	default:
+		return keywordReply(keywordHelp, channel), nil
+	}
+}
+
+// handleMessage runs the keyword or command in body, sent from address on
+// channel, and returns the reply.
+func (s *Server) handleMessage(channel models.Channel, address, body string) (string, error) {
+	if k := parseKeyword(body); k != keywordNone {
+		return s.handleKeyword(k, channel, address)
+	}
+
+	return s.commands.Route(s, models.SubscriberID(channel, address), body)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyword(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected keyword
+	}{
+		{"stop", keywordStop},
+		{"STOP", keywordStop},
+		{"  Stop.  ", keywordStop},
+		{"stopall", keywordStop},
+		{"Unsubscribe", keywordStop},
+		{"CANCEL", keywordStop},
+		{"end", keywordStop},
+		{"Quit!", keywordStop},
+		{"start", keywordStart},
+		{"YES", keywordStart},
+		{"unstop", keywordStart},
+		{"HELP", keywordHelp},
+		{"info", keywordHelp},
+		{"", keywordNone},
+		{"please stop", keywordNone},
+		{"what day is it", keywordNone},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, parseKeyword(test.body), "parsing %q", test.body)
+	}
+}
+
+func TestHandleKeyword(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *models.PhoneNumber
+		keyword  keyword
+		reply    string
+		status   models.Status
+	}{
+		{"stop", &models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusActive}, keywordStop, stopConfirmation, models.StatusStopped},
+		{"stop from unknown number", nil, keywordStop, stopConfirmation, ""},
+		{"stop while paused", &models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusPaused}, keywordStop, stopConfirmation, models.StatusStopped},
+		{"stop while banned", &models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusBanned}, keywordStop, stopConfirmation, models.StatusBanned},
+		{"start", &models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusStopped}, keywordStart, startConfirmation, models.StatusActive},
+		{"start after bouncing", &models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusBounced}, keywordStart, startConfirmation, models.StatusActive},
+		{"start while banned", &models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusBanned}, keywordStart, bannedMessage, models.StatusBanned},
+		{"help", &models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusActive}, keywordHelp, helpMessage, models.StatusActive},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Server{managers: memory.New()}
+
+			if test.existing != nil {
+				assert.NoError(t, s.managers.PhoneNumbers().Create(*test.existing))
+			}
+
+			reply, err := s.handleKeyword(test.keyword, models.ChannelSMS, "+15554443333")
+			assert.NoError(t, err)
+			assert.Equal(t, test.reply, reply)
+
+			if test.existing != nil {
+				phoneNumber, err := s.managers.PhoneNumbers().Get("+15554443333")
+				assert.NoError(t, err)
+				assert.Equal(t, test.status, phoneNumber.Status)
+			}
+		})
+	}
+}
+
+func TestHandleKeywordStartFromUnknownNumberSubscribes(t *testing.T) {
+	withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+		s := &Server{DefaultTimeZone: "UTC", managers: memory.New()}
+
+		reply, err := s.handleKeyword(keywordStart, models.ChannelSMS, "+14155551234")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(reply, "Yo!"))
+		assert.True(t, strings.Contains(reply, "Today is Friday by the way."))
+		assert.True(t, strings.Contains(reply, "I'm guessing you're on America/Los_Angeles time."))
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get("+14155551234")
+		assert.NoError(t, err)
+		assert.True(t, phoneNumber.IsSendable())
+		assert.True(t, phoneNumber.TimezoneGuessed)
+		assert.Equal(t, "America/Los_Angeles", phoneNumber.Timezone)
+		assert.Equal(t, "2015-05-01T19:00:00Z", phoneNumber.LastSentAt.Format(time.RFC3339))
+	})
+}
+
+func TestHandleKeywordStartFromUnknownChatSubscribes(t *testing.T) {
+	withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+		s := &Server{DefaultTimeZone: "UTC", managers: memory.New()}
+
+		reply, err := s.handleKeyword(keywordStart, models.ChannelWhatsApp, "whatsapp:+14155551234")
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(reply, "Yo!"))
+		assert.True(t, strings.Contains(reply, "Today is Friday by the way."))
+		assert.True(t, strings.Contains(reply, "I'm guessing you're on America/Los_Angeles time."))
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get(models.SubscriberID(models.ChannelWhatsApp, "+14155551234"))
+		assert.NoError(t, err)
+		assert.True(t, phoneNumber.IsSendable())
+		assert.True(t, phoneNumber.TimezoneGuessed)
+		assert.Equal(t, "America/Los_Angeles", phoneNumber.Timezone)
+		assert.Equal(t, "2015-05-01T19:00:00Z", phoneNumber.LastSentAt.Format(time.RFC3339))
+	})
+}
+
+func TestTimezoneFor(t *testing.T) {
+	s := &Server{DefaultTimeZone: "America/Los_Angeles"}
+
+	tests := []struct {
+		number    string
+		requested string
+		timezone  string
+		guessed   bool
+	}{
+		{"+447700900123", "Asia/Tokyo", "Asia/Tokyo", false},
+		{"+447700900123", "", "Europe/London", true},
+		{"+447700900123", "Not/A_Zone", "Europe/London", true},
+		{"+447700900123", "US/Pacific", "America/Los_Angeles", false},
+		{"+447700900123", "Asia/Calcutta", "Asia/Kolkata", false},
+		{"+447700900123", "Local", "Europe/London", true},
+		{"+15555551234", "", "America/Los_Angeles", true},
+	}
+
+	for _, test := range tests {
+		timezone, guessed := s.timezoneFor(test.number, test.requested)
+		assert.Equal(t, test.timezone, timezone)
+		assert.Equal(t, test.guessed, guessed)
+	}
+}
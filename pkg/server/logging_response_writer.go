@@ -24,6 +24,14 @@ func (w *loggingResponseWriter) Write(buf []byte) (int, error) {
 	return n, err
 }
 
+// Flush sends anything that's buffered to the client, for responses that
+// stream.
+func (w *loggingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
 func newLoggingResponseWriter(base http.ResponseWriter) *loggingResponseWriter {
 	return &loggingResponseWriter{base, 0, 0}
 }
@@ -0,0 +1,262 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/bradhe/what-day-is-it/pkg/tokens"
+)
+
+// ManageLinkLifetime is how long a link from the MANAGE command is good for.
+var ManageLinkLifetime = 15 * time.Minute
+
+const manageLinkPurpose = "manage-link"
+
+// manageLinkToken returns a single-use token for num's settings page. Showing
+// the page doesn't use it up, only saving the form does.
+func (s *Server) manageLinkToken(num string) string {
+	return tokens.NewSigner(s.SigningSecret).Sign(tokens.Claims{
+		Purpose:   manageLinkPurpose,
+		Subject:   num,
+		ExpiresAt: clock.Clock().Add(ManageLinkLifetime),
+		Nonce:     tokens.NewNonce(),
+	})
+}
+
+func (s *Server) manageLink(num string) string {
+	return s.BaseURL + "/manage?token=" + url.QueryEscape(s.manageLinkToken(num))
+}
+
+type manageDay struct {
+	Name    string
+	Checked bool
+}
+
+// managePage is everything the settings page shows.
+type managePage struct {
+	Number       string
+	Status       models.Status
+	Timezone     string
+	DeliveryTime string
+	Days         []manageDay
+
+	// Submitting the form spends this. Empty if it's been spent, and there's
+	// nothing left to do on the page.
+	Token string
+
+	Message string
+	Error   string
+}
+
+func newManagePage(phoneNumber models.PhoneNumber) managePage {
+	schedule := phoneNumber.DeliverySchedule()
+
+	page := managePage{
+		Number:       phoneNumber.Number,
+		Status:       phoneNumber.Status,
+		Timezone:     phoneNumber.Timezone,
+		DeliveryTime: clock.FormatTimeOfDay(schedule.Minute),
+	}
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		page.Days = append(page.Days, manageDay{d.String(), schedule.Days.Contains(d)})
+	}
+
+	return page
+}
+
+var manageTemplate = template.Must(template.New("manage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>What Day Is It? Settings</title>
+</head>
+<body>
+<h1>What Day Is It?</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{if .Message}}<p class="message">{{.Message}}</p>{{end}}
+{{if .Number}}
+{{if eq .Status "stopped"}}
+<p>{{.Number}} is unsubscribed. Text START to get texts again.</p>
+{{else if .Token}}
+<form method="POST" action="/manage">
+<input type="hidden" name="token" value="{{.Token}}">
+<p>Settings for {{.Number}}</p>
+<p><label>Timezone <input type="text" name="timezone" value="{{.Timezone}}"></label></p>
+<p><label>Time <input type="time" name="delivery_time" value="{{.DeliveryTime}}"></label></p>
+<fieldset>
+<legend>Days</legend>
+{{range .Days}}<label><input type="checkbox" name="days" value="{{.Name}}"{{if .Checked}} checked{{end}}> {{.Name}}</label>
+{{end}}</fieldset>
+<p><button type="submit" name="action" value="save">Save</button></p>
+<p><button type="submit" name="action" value="unsubscribe">Unsubscribe</button></p>
+</form>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+func renderManagePage(w http.ResponseWriter, status int, page managePage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	// The token in the URL shouldn't leak anywhere.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	w.WriteHeader(status)
+
+	if err := manageTemplate.Execute(w, page); err != nil {
+		logger.WithError(err).Error("failed to render manage page")
+	}
+}
+
+// checkManageToken checks the token and looks up the phone number it's for,
+// without using the token up. The error page has been rendered if it returns
+// false.
+func (s *Server) checkManageToken(w http.ResponseWriter, token string) (tokens.Claims, models.PhoneNumber, bool) {
+	claims, err := tokens.NewSigner(s.SigningSecret).Verify(token, manageLinkPurpose, *clock.Clock())
+
+	switch err {
+	case nil:
+		// Handled below.
+	case tokens.ErrExpired:
+		renderManagePage(w, http.StatusGone, managePage{Error: "This link has expired. Text MANAGE for a new one."})
+		return claims, models.PhoneNumber{}, false
+	default:
+		renderManagePage(w, http.StatusForbidden, managePage{Error: "This link isn't valid. Text MANAGE for a new one."})
+		return claims, models.PhoneNumber{}, false
+	}
+
+	if used, err := s.managers.Nonces().Used(claims.Nonce); err != nil {
+		logger.WithError(err).Error("failed to check manage token")
+		renderManagePage(w, http.StatusInternalServerError, managePage{Error: "Something went wrong. Try again in a bit."})
+		return claims, models.PhoneNumber{}, false
+	} else if used {
+		renderManagePage(w, http.StatusGone, managePage{Error: "This link has already been used. Text MANAGE for a new one."})
+		return claims, models.PhoneNumber{}, false
+	}
+
+	phoneNumber, err := s.managers.PhoneNumbers().Get(claims.Subject)
+
+	if err == managers.ErrRecordNotFound {
+		renderManagePage(w, http.StatusNotFound, managePage{Error: "There's no subscription for this number anymore."})
+		return claims, phoneNumber, false
+	} else if err != nil {
+		logger.WithError(err).Error("failed to find phone number")
+		renderManagePage(w, http.StatusInternalServerError, managePage{Error: "Something went wrong. Try again in a bit."})
+		return claims, phoneNumber, false
+	}
+
+	return claims, phoneNumber, true
+}
+
+// spendManageToken makes sure that a token can't be used again. Two of the
+// same form submitted at once can both get past checkManageToken, but only
+// one of them gets past this. The error page has been rendered if it returns
+// false.
+func (s *Server) spendManageToken(w http.ResponseWriter, claims tokens.Claims) bool {
+	if err := s.managers.Nonces().Use(claims.Nonce, &claims.ExpiresAt); err == managers.ErrRecordExists {
+		renderManagePage(w, http.StatusGone, managePage{Error: "This link has already been used. Text MANAGE for a new one."})
+		return false
+	} else if err != nil {
+		logger.WithError(err).Error("failed to spend manage token")
+		renderManagePage(w, http.StatusInternalServerError, managePage{Error: "Something went wrong. Try again in a bit."})
+		return false
+	}
+
+	return true
+}
+
+func (s *Server) GetManage(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	_, phoneNumber, ok := s.checkManageToken(w, token)
+
+	if !ok {
+		return
+	}
+
+	page := newManagePage(phoneNumber)
+	page.Token = token
+
+	renderManagePage(w, http.StatusOK, page)
+}
+
+func (s *Server) PostManage(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		renderManagePage(w, http.StatusBadRequest, managePage{Error: "Something was wrong with that form."})
+		return
+	}
+
+	token := r.PostForm.Get("token")
+	claims, phoneNumber, ok := s.checkManageToken(w, token)
+
+	if !ok {
+		return
+	}
+
+	if r.PostForm.Get("action") == "unsubscribe" {
+		if !s.spendManageToken(w, claims) {
+			return
+		}
+
+		switch err := s.unsubscribe(&phoneNumber, models.ActorWeb); err {
+		case nil:
+			renderManagePage(w, http.StatusOK, newManagePage(phoneNumber))
+		case errBanned:
+			renderManagePage(w, http.StatusConflict, managePage{Error: bannedMessage})
+		default:
+			renderManagePage(w, http.StatusInternalServerError, managePage{Error: "Something went wrong. Try again in a bit."})
+		}
+
+		return
+	}
+
+	var req PatchSubscriptionRequest
+
+	// Only the fields that changed, so that leaving the timezone alone doesn't
+	// count as confirming a guess.
+	if timezone := r.PostForm.Get("timezone"); timezone != "" && timezone != phoneNumber.Timezone {
+		req.Timezone = &timezone
+	}
+
+	deliveryTime := r.PostForm.Get("delivery_time")
+	req.DeliveryTime = &deliveryTime
+
+	days := r.PostForm["days"]
+	req.Days = &days
+
+	u, rerr := planUpdate(phoneNumber, req)
+
+	if rerr != nil {
+		// Nothing changed, so the same link is still good for fixing it.
+		page := newManagePage(phoneNumber)
+		page.Error = rerr.Message
+		page.Token = token
+
+		renderManagePage(w, rerr.Status, page)
+		return
+	}
+
+	if !s.spendManageToken(w, claims) {
+		return
+	}
+
+	if err := s.applyUpdate(&phoneNumber, u, models.ActorWeb); err != nil {
+		logger.WithError(err).Error("failed to update subscription")
+		renderManagePage(w, http.StatusInternalServerError, managePage{Error: "Something went wrong. Try again in a bit."})
+		return
+	}
+
+	page := newManagePage(phoneNumber)
+	page.Message = "Saved. Text MANAGE if you want to change anything else."
+
+	renderManagePage(w, http.StatusOK, page)
+}
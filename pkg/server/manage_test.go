@@ -0,0 +1,213 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	manageLinkexp  = regexp.MustCompile(`https://what-day-is-today\.com(/manage\?token=[^ ]+)`)
+	manageTokenexp = regexp.MustCompile(`name="token" value="([^"]+)"`)
+)
+
+func TestManageCommand(t *testing.T) {
+	s := newTestCommandServer()
+	registerManageCommands(s.commands)
+
+	reply, err := s.commands.Route(s, "+15554443333", "manage")
+	assert.NoError(t, err)
+	assert.Contains(t, reply, "isn't available right now")
+
+	s.BaseURL = DefaultBaseURL
+	s.SigningSecret = []byte("sekrit")
+
+	reply, err = s.commands.Route(s, "+15554443333", "manage")
+	assert.NoError(t, err)
+	assert.Equal(t, notSubscribedMessage, reply)
+
+	assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+15554443333", Timezone: "UTC", Status: models.StatusBanned}))
+
+	reply, err = s.commands.Route(s, "+15554443333", "settings")
+	assert.NoError(t, err)
+	assert.Equal(t, bannedMessage, reply)
+
+	assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+15554442222", Timezone: "UTC", Status: models.StatusActive}))
+
+	reply, err = s.commands.Route(s, "+15554442222", "MANAGE")
+	assert.NoError(t, err)
+	assert.Regexp(t, manageLinkexp, reply)
+	assert.Contains(t, reply, "expires in 15 minutes")
+}
+
+// getManageLink texts MANAGE from num and returns the path of the link we get
+// back.
+func getManageLink(t *testing.T, s *Server, num string) string {
+	reply, err := s.commands.Route(s, num, "manage")
+	assert.NoError(t, err)
+
+	match := manageLinkexp.FindStringSubmatch(reply)
+
+	if !assert.NotNil(t, match, reply) {
+		t.FailNow()
+	}
+
+	return match[1]
+}
+
+func postManage(s *Server, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/manage", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+func formToken(w *httptest.ResponseRecorder) string {
+	if match := manageTokenexp.FindStringSubmatch(w.Body.String()); match != nil {
+		return match[1]
+	}
+
+	return ""
+}
+
+func TestManagePage(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "UTC", TimezoneGuessed: true, Status: models.StatusActive}))
+
+		link := getManageLink(t, s, "+14155551234")
+
+		w := doAPIRequest(s, http.MethodGet, link, "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+		assert.Contains(t, w.Body.String(), `value="UTC"`)
+		assert.Contains(t, w.Body.String(), `value="08:00"`)
+
+		token := formToken(w)
+		assert.NotEmpty(t, token)
+
+		// Looking at the page doesn't use the link up.
+		w = doAPIRequest(s, http.MethodGet, link, "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, token, formToken(w))
+
+		// Leaving the timezone alone doesn't count as confirming it.
+		w = postManage(s, url.Values{
+			"token":         {token},
+			"action":        {"save"},
+			"timezone":      {"UTC"},
+			"delivery_time": {"06:30"},
+			"days":          {"Monday", "Friday"},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Saved.")
+		assert.Empty(t, formToken(w))
+
+		phoneNumber, _ := s.managers.PhoneNumbers().Get("+14155551234")
+		assert.True(t, phoneNumber.TimezoneGuessed)
+		assert.Equal(t, clock.Schedule{Minute: 390, Days: clock.NewWeekdays(1, 5)}, *phoneNumber.Schedule)
+
+		// Saving does.
+		w = doAPIRequest(s, http.MethodGet, link, "", "")
+		assert.Equal(t, http.StatusGone, w.Code)
+		assert.Contains(t, w.Body.String(), "already been used")
+
+		w = postManage(s, url.Values{"token": {token}, "timezone": {"Tokyo"}})
+		assert.Equal(t, http.StatusGone, w.Code)
+
+		// Mistakes are shown on the page, which can be submitted again.
+		w = doAPIRequest(s, http.MethodGet, getManageLink(t, s, "+14155551234"), "", "")
+		token = formToken(w)
+
+		w = postManage(s, url.Values{
+			"token":         {token},
+			"action":        {"save"},
+			"timezone":      {"Atlantis"},
+			"delivery_time": {"06:30"},
+			"days":          {"Monday"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Unknown timezone Atlantis.")
+		assert.Equal(t, token, formToken(w))
+
+		w = postManage(s, url.Values{
+			"token":         {token},
+			"action":        {"save"},
+			"timezone":      {"Tokyo"},
+			"delivery_time": {"07:00"},
+			"days":          {"Monday"},
+		})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		phoneNumber, _ = s.managers.PhoneNumbers().Get("+14155551234")
+		assert.Equal(t, "Asia/Tokyo", phoneNumber.Timezone)
+		assert.False(t, phoneNumber.TimezoneGuessed)
+
+		events, _ := s.managers.Events().List("+14155551234", 10)
+		assert.Equal(t, models.EventScheduleChanged, events[0].Type)
+		assert.Equal(t, models.EventTimezoneChanged, events[1].Type)
+		assert.Equal(t, models.ActorWeb, events[1].Actor)
+
+		next := doAPIRequest(s, http.MethodGet, getManageLink(t, s, "+14155551234"), "", "")
+		w = postManage(s, url.Values{"token": {formToken(next)}, "action": {"unsubscribe"}})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "is unsubscribed")
+		assert.Empty(t, formToken(w))
+
+		phoneNumber, _ = s.managers.PhoneNumbers().Get("+14155551234")
+		assert.Equal(t, models.StatusStopped, phoneNumber.Status)
+	})
+}
+
+func TestManagePageErrors(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	var link string
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "UTC", Status: models.StatusActive}))
+
+		link = getManageLink(t, s, "+14155551234")
+
+		w := doAPIRequest(s, http.MethodGet, "/manage?token=bogus", "", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), "isn&#39;t valid")
+
+		// Tokens for the API don't work here.
+		w = doAPIRequest(s, http.MethodGet, "/manage?token="+url.QueryEscape(s.manageToken("+14155551234")), "", "")
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		// Nothing changes when the form is wrong.
+		w = postManage(s, url.Values{
+			"token":         {formToken(doAPIRequest(s, http.MethodGet, getManageLink(t, s, "+14155551234"), "", ""))},
+			"timezone":      {"UTC"},
+			"delivery_time": {"06:30"},
+		})
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "Pick at least one day")
+
+		phoneNumber, _ := s.managers.PhoneNumbers().Get("+14155551234")
+		assert.Nil(t, phoneNumber.Schedule)
+	})
+
+	withClockTime(t, mustParseTime("2026-10-19T17:16:00Z"), func(t *testing.T) {
+		w := doAPIRequest(s, http.MethodGet, link, "", "")
+		assert.Equal(t, http.StatusGone, w.Code)
+		assert.Contains(t, w.Body.String(), "expired")
+	})
+}
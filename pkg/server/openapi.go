@@ -0,0 +1,1026 @@
+package server
+
+import (
+	"net/http"
+)
+
+// OpenAPISpec describes every endpoint that the server routes. It's served at
+// /api/openapi.json and pkg/client is generated from it, so run go generate
+// after changing it. The tests make sure it matches the router and the request
+// and response types.
+const OpenAPISpec = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "What Day Is It?",
+    "description": "Texts people what day it is, every morning.",
+    "version": "1.0.0"
+  },
+  "servers": [
+    {"url": "https://what-day-is-today.com"}
+  ],
+  "paths": {
+    "/api/health": {
+      "get": {
+        "operationId": "getHealth",
+        "summary": "Checks that the server is up.",
+        "tags": ["meta"],
+        "responses": {
+          "200": {
+            "description": "The server is up.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/GetHealthResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/openapi.json": {
+      "get": {
+        "operationId": "getOpenAPI",
+        "summary": "Returns this document.",
+        "tags": ["meta"],
+        "responses": {
+          "200": {
+            "description": "This document.",
+            "content": {"application/json": {"schema": {"type": "object"}}}
+          }
+        }
+      }
+    },
+    "/api/today": {
+      "get": {
+        "operationId": "getToday",
+        "summary": "Returns what day it is.",
+        "description": "Responds with plain text, day name first, unless the Accept header prefers JSON. Responses can be cached until the day ends, at the next local midnight, or until the clocks change if that comes first.",
+        "tags": ["meta"],
+        "parameters": [
+          {"name": "tz", "in": "query", "description": "A timezone name, city or abbreviation. Defaults to the server's timezone.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "What day it is in the timezone.",
+            "content": {
+              "application/json": {"schema": {"$ref": "#/components/schemas/TodayResponse"}},
+              "text/plain": {"schema": {"type": "string"}}
+            }
+          },
+          "304": {"description": "The day hasn't changed since the ETag in If-None-Match."},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/api/timezones": {
+      "get": {
+        "operationId": "listTimezones",
+        "summary": "Lists the timezones that subscriptions can use.",
+        "tags": ["meta"],
+        "parameters": [
+          {"name": "q", "in": "query", "description": "Only returns timezones that might be what this means, best matches first. Partial and misspelled names are fine, like berl.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The timezones.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/GetTimezonesResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/calendar/{tz}.ics": {
+      "get": {
+        "operationId": "getCalendar",
+        "summary": "Returns an iCalendar feed with an all-day event for each day.",
+        "description": "The feed covers the last week and the next 90 days, and can be cached until the next local midnight. Calendar apps can subscribe to it.",
+        "tags": ["meta"],
+        "parameters": [
+          {"name": "tz", "in": "path", "required": true, "description": "A timezone name, city or abbreviation. Names have slashes in them, like Asia/Tokyo.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The feed.",
+            "content": {"text/calendar": {"schema": {"type": "string"}}}
+          },
+          "304": {"description": "The feed hasn't changed since the ETag in If-None-Match."},
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/api/stream/rollover": {
+      "get": {
+        "operationId": "streamRollovers",
+        "summary": "Streams an event each time the day changes.",
+        "description": "A Server-Sent Events stream with a rollover event, whose data is a TodayResponse, for today in each timezone when it connects and then at each local midnight. Event IDs are when the day started, in Unix seconds, so streams that reconnect with Last-Event-ID only get the days that they missed. Idle streams get a heartbeat comment every 15 seconds.",
+        "tags": ["meta"],
+        "parameters": [
+          {"name": "tz", "in": "query", "description": "Timezone names, cities or abbreviations, separated by commas or repeated. Defaults to the server's timezone.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The stream.",
+            "content": {"text/event-stream": {"schema": {"type": "string"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"}
+        }
+      }
+    },
+    "/api/subscribe": {
+      "post": {
+        "operationId": "subscribe",
+        "summary": "Subscribes a phone number.",
+        "description": "The original subscribe endpoint. Use createSubscription instead.",
+        "tags": ["legacy"],
+        "deprecated": true,
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The phone number was subscribed, or is waiting to confirm. Numbers that can't be subscribed have an error.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeResponse"}}}
+          },
+          "400": {
+            "description": "The request couldn't be read.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeResponse"}}}
+          },
+          "412": {
+            "description": "The phone number isn't valid.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeResponse"}}}
+          },
+          "429": {
+            "description": "We sent the number a confirmation a moment ago.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/subscribe/verify": {
+      "post": {
+        "operationId": "subscribeVerify",
+        "summary": "Confirms a subscription with the code that we texted.",
+        "description": "The original verification endpoint. Use verifySubscription instead.",
+        "tags": ["legacy"],
+        "deprecated": true,
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeVerifyRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The phone number is subscribed.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeResponse"}}}
+          },
+          "410": {
+            "description": "The code or the subscription expired.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeResponse"}}}
+          },
+          "412": {
+            "description": "The code is wrong.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeResponse"}}}
+          },
+          "429": {
+            "description": "Too many wrong codes.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscribeResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/v1/subscriptions": {
+      "post": {
+        "operationId": "createSubscription",
+        "summary": "Subscribes a phone number, email address, Slack or Discord channel, or webhook.",
+        "description": "The number has to confirm before it gets texts, either by replying YES or by sending the code we text it to verifySubscription. Email addresses get the code by email, and Slack and Discord channels and webhooks get it posted to the webhook. Subscribing something we already send to gets it a code instead, and the timezone and delivery time only change once that code is verified. The same goes for stopped or bounced subscribers on channels that can't send START, which are resubscribed by the code. Stopped numbers that can text START get a 409.",
+        "tags": ["subscriptions"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscriptionRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The subscription.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscriptionResponse"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "409": {
+            "description": "The number asked us to stop texting it, the webhook stopped working, or it's banned.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          },
+          "429": {
+            "description": "We sent a confirmation a moment ago.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/v1/subscriptions/{number}": {
+      "get": {
+        "operationId": "getSubscription",
+        "summary": "Returns a subscription.",
+        "tags": ["subscriptions"],
+        "security": [{"manageToken": []}, {"adminToken": []}],
+        "parameters": [{"$ref": "#/components/parameters/Number"}],
+        "responses": {
+          "200": {
+            "description": "The subscription.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Subscription"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "403": {"$ref": "#/components/responses/Forbidden"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      },
+      "patch": {
+        "operationId": "updateSubscription",
+        "summary": "Changes a subscription's settings.",
+        "description": "Nothing changes unless everything in the request is valid.",
+        "tags": ["subscriptions"],
+        "security": [{"manageToken": []}, {"adminToken": []}],
+        "parameters": [{"$ref": "#/components/parameters/Number"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PatchSubscriptionRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The updated subscription.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Subscription"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "403": {"$ref": "#/components/responses/Forbidden"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "409": {
+            "description": "The subscription can't be paused or resumed.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          }
+        }
+      },
+      "delete": {
+        "operationId": "deleteSubscription",
+        "summary": "Unsubscribes a phone number.",
+        "tags": ["subscriptions"],
+        "security": [{"manageToken": []}, {"adminToken": []}],
+        "parameters": [{"$ref": "#/components/parameters/Number"}],
+        "responses": {
+          "200": {
+            "description": "The stopped subscription.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Subscription"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "403": {"$ref": "#/components/responses/Forbidden"},
+          "404": {"$ref": "#/components/responses/NotFound"},
+          "409": {
+            "description": "The number is banned.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/v1/subscriptions/{number}/verify": {
+      "post": {
+        "operationId": "verifySubscription",
+        "summary": "Confirms a subscription with the code that we texted.",
+        "description": "Numbers that confirmed by replying YES can get a manage token by calling createSubscription again and verifying the code that gets them.",
+        "tags": ["subscriptions"],
+        "parameters": [{"$ref": "#/components/parameters/Number"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscriptionVerifyRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The subscription, and a token for managing it.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostSubscriptionVerifyResponse"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "409": {
+            "description": "The number is banned.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          },
+          "410": {
+            "description": "The code or the subscription expired.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          },
+          "422": {
+            "description": "The code is wrong.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          },
+          "429": {
+            "description": "Too many wrong codes.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/v1/subscriptions/{number}/deliveries": {
+      "get": {
+        "operationId": "listDeliveries",
+        "summary": "Returns the most recent attempts at posting to a webhook subscription, newest first.",
+        "tags": ["subscriptions"],
+        "security": [{"manageToken": []}, {"adminToken": []}],
+        "parameters": [
+          {"$ref": "#/components/parameters/Number"},
+          {"name": "limit", "in": "query", "description": "How many attempts to return. Defaults to 50, up to 1000.", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The attempts.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/GetDeliveriesResponse"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {"$ref": "#/components/responses/Unauthorized"},
+          "403": {"$ref": "#/components/responses/Forbidden"},
+          "404": {"$ref": "#/components/responses/NotFound"}
+        }
+      }
+    },
+    "/api/incoming-message": {
+      "post": {
+        "operationId": "receiveMessage",
+        "summary": "Handles a text or WhatsApp message from a subscriber.",
+        "description": "Twilio's messaging webhook, for both SMS and WhatsApp.",
+        "tags": ["webhooks"],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "type": "object",
+                "properties": {
+                  "AccountSid": {"type": "string"},
+                  "From": {"type": "string", "description": "A phone number, or a WhatsApp address like whatsapp:+14155551234."},
+                  "Body": {"type": "string"}
+                }
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "TwiML with the reply.",
+            "content": {"application/xml": {"schema": {"type": "string"}}}
+          }
+        }
+      }
+    },
+    "/api/incoming-call": {
+      "post": {
+        "operationId": "answerCall",
+        "summary": "Tells a caller what day it is.",
+        "description": "Twilio's voice webhook. Callers hear the day in their timezone, then a menu to subscribe to a call every morning or stop them.",
+        "tags": ["webhooks"],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "type": "object",
+                "properties": {
+                  "AccountSid": {"type": "string"},
+                  "From": {"type": "string"}
+                }
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "TwiML that reads out the day and the menu.",
+            "content": {"application/xml": {"schema": {"type": "string"}}}
+          }
+        }
+      }
+    },
+    "/api/incoming-call/menu": {
+      "post": {
+        "operationId": "answerCallMenu",
+        "summary": "Handles a key pressed on the incoming call menu.",
+        "description": "1 subscribes the caller to a call every morning and 9 stops them. Anything else reads the menu again.",
+        "tags": ["webhooks"],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "type": "object",
+                "properties": {
+                  "AccountSid": {"type": "string"},
+                  "From": {"type": "string"},
+                  "Digits": {"type": "string", "description": "The key that the caller pressed."}
+                }
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "TwiML with the reply.",
+            "content": {"application/xml": {"schema": {"type": "string"}}}
+          }
+        }
+      }
+    },
+    "/api/slack/command": {
+      "post": {
+        "operationId": "answerSlackCommand",
+        "summary": "Answers a slash command like /whatday Tokyo.",
+        "description": "Slack's slash command request URL. Requests have to be signed with the Slack signing secret.",
+        "tags": ["webhooks"],
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/x-www-form-urlencoded": {
+              "schema": {
+                "type": "object",
+                "properties": {
+                  "command": {"type": "string"},
+                  "text": {"type": "string", "description": "A timezone or city. The server's default timezone if it's empty."}
+                }
+              }
+            }
+          }
+        },
+        "responses": {
+          "200": {
+            "description": "The answer to post.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SlackMessage"}}}
+          },
+          "401": {
+            "description": "The request isn't signed by Slack.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/discord/interactions": {
+      "post": {
+        "operationId": "answerDiscordInteraction",
+        "summary": "Answers the /whatday command in Discord.",
+        "description": "The Discord app's interactions endpoint URL. Requests have to be signed with the app's key.",
+        "tags": ["webhooks"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/DiscordInteraction"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The answer to post, or a pong.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/DiscordInteractionResponse"}}}
+          },
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {
+            "description": "The request isn't signed by Discord.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/telegram/webhook": {
+      "post": {
+        "operationId": "receiveTelegramUpdate",
+        "summary": "Answers a message sent to the Telegram bot.",
+        "description": "The bot's webhook, when it isn't polling for updates. Replies go out through the Bot API.",
+        "tags": ["webhooks"],
+        "parameters": [
+          {"name": "X-Telegram-Bot-Api-Secret-Token", "in": "header", "required": true, "description": "The secret token that the webhook was set up with.", "schema": {"type": "string"}}
+        ],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/TelegramUpdate"}}}
+        },
+        "responses": {
+          "200": {"description": "The update was handled."},
+          "400": {"$ref": "#/components/responses/BadRequest"},
+          "401": {
+            "description": "The request doesn't have the secret token.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+          }
+        }
+      }
+    },
+    "/manage": {
+      "get": {
+        "operationId": "getManagePage",
+        "summary": "Shows the settings page from a MANAGE link.",
+        "tags": ["pages"],
+        "parameters": [
+          {"name": "token", "in": "query", "required": true, "description": "The token from the link.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"$ref": "#/components/responses/Page"},
+          "403": {"$ref": "#/components/responses/Page"},
+          "410": {"$ref": "#/components/responses/Page"}
+        }
+      },
+      "post": {
+        "operationId": "postManagePage",
+        "summary": "Saves the settings page.",
+        "tags": ["pages"],
+        "requestBody": {
+          "required": true,
+          "content": {"application/x-www-form-urlencoded": {"schema": {"type": "object"}}}
+        },
+        "responses": {
+          "200": {"$ref": "#/components/responses/Page"},
+          "400": {"$ref": "#/components/responses/Page"},
+          "403": {"$ref": "#/components/responses/Page"},
+          "410": {"$ref": "#/components/responses/Page"}
+        }
+      }
+    },
+    "/unsubscribe": {
+      "get": {
+        "operationId": "getUnsubscribePage",
+        "summary": "Asks whether to stop emailing an address, from the link in an email.",
+        "tags": ["pages"],
+        "parameters": [
+          {"name": "token", "in": "query", "required": true, "description": "The token from the link.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {"$ref": "#/components/responses/Page"},
+          "403": {"$ref": "#/components/responses/Page"},
+          "404": {"$ref": "#/components/responses/Page"},
+          "410": {"$ref": "#/components/responses/Page"}
+        }
+      },
+      "post": {
+        "operationId": "postUnsubscribePage",
+        "summary": "Stops emailing an address.",
+        "description": "Also the RFC 8058 one-click unsubscribe target in the List-Unsubscribe header, which has the token in the query string.",
+        "tags": ["pages"],
+        "parameters": [
+          {"name": "token", "in": "query", "description": "The token from the link, if it isn't in the form.", "schema": {"type": "string"}}
+        ],
+        "requestBody": {
+          "content": {"application/x-www-form-urlencoded": {"schema": {"type": "object"}}}
+        },
+        "responses": {
+          "200": {"$ref": "#/components/responses/Page"},
+          "403": {"$ref": "#/components/responses/Page"},
+          "404": {"$ref": "#/components/responses/Page"},
+          "409": {"$ref": "#/components/responses/Page"},
+          "410": {"$ref": "#/components/responses/Page"}
+        }
+      }
+    },
+    "/api/admin/phone-numbers/{number}/events": {
+      "get": {
+        "operationId": "listEvents",
+        "summary": "Returns what's happened to a phone number, newest first.",
+        "tags": ["admin"],
+        "security": [{"adminToken": []}],
+        "parameters": [
+          {"$ref": "#/components/parameters/Number"},
+          {"name": "limit", "in": "query", "description": "How many events to return. Defaults to 50, up to 1000.", "schema": {"type": "integer"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The events.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/GetEventsResponse"}}}
+          },
+          "400": {
+            "description": "The phone number or limit isn't valid.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/GetEventsResponse"}}}
+          },
+          "401": {"description": "The admin token is missing or wrong."}
+        }
+      }
+    },
+    "/api/admin/phone-numbers/{number}/pause": {
+      "post": {
+        "operationId": "pauseSubscription",
+        "summary": "Stops texts to a phone number for a while.",
+        "tags": ["admin"],
+        "security": [{"adminToken": []}],
+        "parameters": [{"$ref": "#/components/parameters/Number"}],
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PostPauseRequest"}}}
+        },
+        "responses": {
+          "200": {
+            "description": "The paused subscription.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PauseResponse"}}}
+          },
+          "400": {
+            "description": "The pause isn't valid.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PauseResponse"}}}
+          },
+          "401": {"description": "The admin token is missing or wrong."},
+          "404": {
+            "description": "The phone number isn't subscribed.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PauseResponse"}}}
+          },
+          "409": {
+            "description": "The subscription isn't active.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PauseResponse"}}}
+          }
+        }
+      }
+    },
+    "/api/admin/phone-numbers/{number}/resume": {
+      "post": {
+        "operationId": "resumeSubscription",
+        "summary": "Starts texts to a paused phone number again.",
+        "tags": ["admin"],
+        "security": [{"adminToken": []}],
+        "parameters": [{"$ref": "#/components/parameters/Number"}],
+        "responses": {
+          "200": {
+            "description": "The resumed subscription.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PauseResponse"}}}
+          },
+          "401": {"description": "The admin token is missing or wrong."},
+          "404": {
+            "description": "The phone number isn't subscribed.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PauseResponse"}}}
+          },
+          "409": {
+            "description": "The subscription isn't paused.",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/PauseResponse"}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "securitySchemes": {
+      "manageToken": {
+        "type": "http",
+        "scheme": "bearer",
+        "description": "The manage_token from verifying the phone number. It only works for that number, and stops working when the number unsubscribes."
+      },
+      "adminToken": {
+        "type": "http",
+        "scheme": "bearer",
+        "description": "The server's admin token. It works for every number."
+      }
+    },
+    "parameters": {
+      "Number": {
+        "name": "number",
+        "in": "path",
+        "required": true,
+        "description": "The phone number, like +14155551234, or the email address or other subscriber ID.",
+        "schema": {"type": "string"}
+      }
+    },
+    "responses": {
+      "BadRequest": {
+        "description": "Something in the request isn't valid.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      },
+      "Unauthorized": {
+        "description": "The token is missing, wrong or expired.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      },
+      "Forbidden": {
+        "description": "The token is for a different phone number.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      },
+      "NotFound": {
+        "description": "There's no subscription for the phone number.",
+        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}
+      },
+      "Page": {
+        "description": "The settings page, or what went wrong.",
+        "content": {"text/html": {"schema": {"type": "string"}}}
+      }
+    },
+    "schemas": {
+      "APIError": {
+        "type": "object",
+        "properties": {
+          "code": {
+            "type": "string",
+            "description": "What went wrong, for machines.",
+            "enum": [
+              "invalid_request",
+              "invalid_phone_number",
+              "invalid_channel",
+              "invalid_webhook_url",
+              "invalid_email",
+              "invalid_verification",
+              "invalid_timezone",
+              "ambiguous_timezone",
+              "invalid_delivery_time",
+              "invalid_days",
+              "invalid_pause",
+              "unauthorized",
+              "token_expired",
+              "forbidden",
+              "not_found",
+              "code_expired",
+              "too_many_attempts",
+              "too_soon",
+              "wrong_code",
+              "stopped",
+              "banned",
+              "not_pausable",
+              "internal_error"
+            ]
+          },
+          "message": {"type": "string", "description": "What went wrong, for people."}
+        },
+        "required": ["code", "message"]
+      },
+      "DeliveryAttempt": {
+        "type": "object",
+        "description": "One try at posting to a webhook subscription.",
+        "properties": {
+          "delivery_id": {"type": "string", "description": "The same for every attempt at a delivery, and in the X-Webhook-Id header."},
+          "attempt": {"type": "integer", "description": "Which try this was, starting at 1."},
+          "at": {"type": "string", "format": "date-time"},
+          "succeeded": {"type": "boolean"},
+          "status_code": {"type": "integer", "description": "What the receiver responded with. Missing if it never did."},
+          "duration_ms": {"type": "integer"},
+          "error": {"type": "string", "description": "What went wrong, if anything."}
+        },
+        "required": ["delivery_id", "attempt", "at", "succeeded", "duration_ms"]
+      },
+      "DiscordInteraction": {
+        "type": "object",
+        "properties": {
+          "type": {"type": "integer", "description": "1 for a ping, 2 for a command."},
+          "data": {"$ref": "#/components/schemas/DiscordInteractionData"}
+        },
+        "required": ["type"]
+      },
+      "DiscordInteractionCallback": {
+        "type": "object",
+        "properties": {
+          "content": {"type": "string"},
+          "flags": {"type": "integer", "description": "64 if only the person that asked sees the answer."}
+        },
+        "required": ["content"]
+      },
+      "DiscordInteractionData": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string", "description": "The command, like whatday."},
+          "options": {"type": "array", "items": {"$ref": "#/components/schemas/DiscordInteractionOption"}}
+        },
+        "required": ["name"]
+      },
+      "DiscordInteractionOption": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string"},
+          "value": {"description": "What was given for the option, like Tokyo for timezone."}
+        },
+        "required": ["name", "value"]
+      },
+      "DiscordInteractionResponse": {
+        "type": "object",
+        "properties": {
+          "type": {"type": "integer", "description": "1 for a pong, 4 for a message."},
+          "data": {"$ref": "#/components/schemas/DiscordInteractionCallback"}
+        },
+        "required": ["type"]
+      },
+      "ErrorResponse": {
+        "type": "object",
+        "description": "The body of every unsuccessful v1 response.",
+        "properties": {
+          "error": {"$ref": "#/components/schemas/APIError"}
+        },
+        "required": ["error"]
+      },
+      "EventResponse": {
+        "type": "object",
+        "properties": {
+          "type": {
+            "type": "string",
+            "enum": [
+              "subscribed",
+              "confirmed",
+              "resubscribed",
+              "message_sent",
+              "delivery_failed",
+              "stop_received",
+              "paused",
+              "resumed",
+              "timezone_changed",
+              "schedule_changed",
+              "unsubscribed",
+              "admin_action",
+              "webhook_attempt"
+            ]
+          },
+          "at": {"type": "string", "format": "date-time"},
+          "actor": {"type": "string", "description": "Who did it: subscriber, web, system or admin:<number>."},
+          "metadata": {"type": "object", "additionalProperties": {"type": "string"}}
+        },
+        "required": ["type", "at", "actor"]
+      },
+      "GetEventsResponse": {
+        "type": "object",
+        "properties": {
+          "number": {"type": "string"},
+          "events": {"type": "array", "description": "Newest first.", "items": {"$ref": "#/components/schemas/EventResponse"}},
+          "error": {"type": "string"}
+        },
+        "required": ["events"]
+      },
+      "GetDeliveriesResponse": {
+        "type": "object",
+        "properties": {
+          "deliveries": {"type": "array", "description": "Newest first.", "items": {"$ref": "#/components/schemas/DeliveryAttempt"}}
+        },
+        "required": ["deliveries"]
+      },
+      "GetHealthResponse": {
+        "type": "object",
+        "properties": {
+          "ok": {"type": "boolean"}
+        },
+        "required": ["ok"]
+      },
+      "GetTimezonesResponse": {
+        "type": "object",
+        "properties": {
+          "timezones": {"type": "array", "items": {"$ref": "#/components/schemas/TimezoneResponse"}}
+        },
+        "required": ["timezones"]
+      },
+      "PatchSubscriptionRequest": {
+        "type": "object",
+        "description": "A change to a subscription's settings. Anything that's left out stays the same.",
+        "properties": {
+          "timezone": {"type": "string", "description": "A timezone name, city or abbreviation."},
+          "delivery_time": {"type": "string", "description": "Local time of day to send at, like 07:30."},
+          "days": {"type": "array", "description": "Days of the week to send on, like monday or mon.", "items": {"type": "string"}},
+          "paused": {"type": "boolean", "description": "Pauses or resumes deliveries."},
+          "paused_until": {"type": "string", "format": "date", "description": "Pauses deliveries until this date, like 2026-11-01."}
+        }
+      },
+      "PauseResponse": {
+        "type": "object",
+        "properties": {
+          "number": {"type": "string"},
+          "status": {"type": "string", "enum": ["pending", "active", "paused", "stopped", "bounced", "banned"]},
+          "paused_until": {"type": "string", "format": "date-time", "description": "When deliveries start again, if the pause ends on its own."},
+          "error": {"type": "string"}
+        }
+      },
+      "PostPauseRequest": {
+        "type": "object",
+        "description": "A pause. With neither for nor until it lasts until it's resumed.",
+        "properties": {
+          "for": {"type": "string", "description": "How long to pause for, like 7d or 2w."},
+          "until": {"type": "string", "format": "date", "description": "The date to pick back up on, like 2026-11-01. Takes precedence over for."}
+        }
+      },
+      "PostSubscribeRequest": {
+        "type": "object",
+        "properties": {
+          "number": {"type": "string", "description": "The phone number to subscribe."},
+          "timezone": {"type": "string", "description": "The timezone that the user selected."},
+          "verification": {"type": "string", "description": "How the owner of the number will confirm: by replying to a text, the default, or by sending us the code we text them.", "enum": ["reply", "code"]}
+        },
+        "required": ["number"]
+      },
+      "PostSubscribeResponse": {
+        "type": "object",
+        "properties": {
+          "number": {"type": "string"},
+          "timezone": {"type": "string"},
+          "timezone_guessed": {"type": "boolean", "description": "Indicates that we picked the timezone because the request didn't include a usable one."},
+          "error": {"type": "string"},
+          "subscribed": {"type": "boolean"},
+          "status": {"type": "string", "enum": ["pending", "active", "paused", "stopped", "bounced", "banned"]},
+          "confirmation_required": {"type": "boolean", "description": "Indicates that the number has to confirm by SMS before it's subscribed."},
+          "verification_required": {"type": "boolean", "description": "Indicates that the code we texted to the number has to be sent to subscribeVerify."},
+          "manage_token": {"type": "string", "description": "Lets the owner of the number manage their subscription through the API."}
+        },
+        "required": ["subscribed"]
+      },
+      "PostSubscribeVerifyRequest": {
+        "type": "object",
+        "properties": {
+          "number": {"type": "string", "description": "The phone number that the code was sent to."},
+          "code": {"type": "string", "description": "The code that we texted to the number."}
+        },
+        "required": ["number", "code"]
+      },
+      "PostSubscriptionRequest": {
+        "type": "object",
+        "properties": {
+          "number": {"type": "string", "description": "The phone number to text or call, for the sms and voice channels.", "x-go-type-skip-optional-pointer": true},
+          "channel": {"type": "string", "description": "How to deliver the message.", "enum": ["sms", "voice", "email", "slack", "discord", "webhook"]},
+          "email": {"type": "string", "description": "The address to email, for the email channel."},
+          "webhook_url": {"type": "string", "description": "The webhook to post to, for the slack, discord and webhook channels."},
+          "timezone": {"type": "string", "description": "A timezone name, city or abbreviation. We'll guess if it's missing."},
+          "delivery_time": {"type": "string", "description": "Local time of day to send at, like 07:30. The default is 08:00."},
+          "verification": {"type": "string", "description": "How the owner of the number will confirm: by replying to a text, the default, or by sending us the code we text them. Email addresses and webhooks always get a code.", "enum": ["reply", "code"]}
+        }
+      },
+      "PostSubscriptionResponse": {
+        "type": "object",
+        "properties": {
+          "subscription": {"$ref": "#/components/schemas/Subscription"},
+          "confirmation_required": {"type": "boolean", "description": "Indicates that the number has to reply YES to the text we sent it."},
+          "verification_required": {"type": "boolean", "description": "Indicates that the code we texted has to be sent to verifySubscription."}
+        },
+        "required": ["subscription", "confirmation_required", "verification_required"]
+      },
+      "PostSubscriptionVerifyRequest": {
+        "type": "object",
+        "properties": {
+          "code": {"type": "string", "description": "The code that we texted to the number."}
+        },
+        "required": ["code"]
+      },
+      "PostSubscriptionVerifyResponse": {
+        "type": "object",
+        "properties": {
+          "subscription": {"$ref": "#/components/schemas/Subscription"},
+          "manage_token": {"type": "string", "description": "Authorizes requests to manage the subscription. Missing if the server can't sign tokens."},
+          "webhook_secret": {"type": "string", "description": "Signs everything we post to a webhook subscription. Only webhook subscriptions have one, and it's only handed out here."}
+        },
+        "required": ["subscription"]
+      },
+      "SlackMessage": {
+        "type": "object",
+        "properties": {
+          "response_type": {"type": "string", "description": "Whether everybody in the channel sees the answer or just the person that asked.", "enum": ["in_channel", "ephemeral"]},
+          "text": {"type": "string"}
+        },
+        "required": ["text"]
+      },
+      "Subscription": {
+        "type": "object",
+        "properties": {
+          "number": {"type": "string", "description": "The phone number, or an ID like slack:0123456789abcdef for other channels."},
+          "channel": {"type": "string", "enum": ["sms", "voice", "email", "slack", "discord", "telegram", "whatsapp", "webhook"]},
+          "status": {"type": "string", "enum": ["pending", "active", "paused", "stopped", "bounced", "banned"]},
+          "timezone": {"type": "string"},
+          "timezone_guessed": {"type": "boolean", "description": "Indicates that we picked the timezone because nobody told us one."},
+          "delivery_time": {"type": "string", "description": "Local time of day that the message goes out, like 08:00."},
+          "days": {"type": "array", "description": "The days of the week that the message goes out, like monday.", "items": {"type": "string"}},
+          "paused_until": {"type": "string", "format": "date-time", "description": "When deliveries start again, for paused subscriptions that will resume on their own."},
+          "last_sent_at": {"type": "string", "format": "date-time"},
+          "next_delivery_at": {"type": "string", "format": "date-time"}
+        },
+        "required": ["number", "channel", "status", "timezone", "timezone_guessed", "delivery_time", "days"]
+      },
+      "TelegramChat": {
+        "type": "object",
+        "properties": {
+          "id": {"type": "integer"},
+          "type": {"type": "string", "description": "One of private, group, supergroup or channel."}
+        },
+        "required": ["id", "type"]
+      },
+      "TelegramMessage": {
+        "type": "object",
+        "properties": {
+          "message_id": {"type": "integer"},
+          "chat": {"$ref": "#/components/schemas/TelegramChat"},
+          "text": {"type": "string", "description": "What was sent, like /tz Tokyo."}
+        },
+        "required": ["message_id", "chat"]
+      },
+      "TelegramUpdate": {
+        "type": "object",
+        "properties": {
+          "update_id": {"type": "integer"},
+          "message": {"$ref": "#/components/schemas/TelegramMessage"}
+        },
+        "required": ["update_id"]
+      },
+      "TimezoneResponse": {
+        "type": "object",
+        "properties": {
+          "name": {"type": "string", "description": "The canonical IANA name, like Europe/Berlin."},
+          "display_name": {"type": "string", "description": "A name to show people, like Berlin (UTC+02:00)."},
+          "utc_offset": {"type": "string", "description": "The offset from UTC right now, like +02:00."},
+          "dst": {"type": "boolean", "description": "Indicates that daylight saving time is in effect right now."},
+          "cities": {"type": "array", "items": {"type": "string"}},
+          "abbreviations": {"type": "array", "items": {"type": "string"}},
+          "aliases": {"type": "array", "description": "Old names for the zone, like US/Pacific.", "items": {"type": "string"}}
+        },
+        "required": ["name", "display_name", "utc_offset", "dst", "cities", "abbreviations", "aliases"]
+      },
+      "TodayResponse": {
+        "type": "object",
+        "properties": {
+          "timezone": {"type": "string", "description": "The timezone name that the day is for."},
+          "day": {"type": "string", "description": "The name of the day, like Monday."},
+          "date": {"type": "string", "format": "date", "description": "The ISO 8601 date, like 2026-10-19."},
+          "iso_week": {"type": "string", "description": "The ISO 8601 week, like 2026-W43."},
+          "day_of_year": {"type": "integer"},
+          "days_left_in_year": {"type": "integer"},
+          "utc_offset": {"type": "string", "description": "The offset from UTC right now, like +09:00."},
+          "expires_at": {"type": "string", "format": "date-time", "description": "When this response stops being true, which is when the day ends or, if the clocks change first, when they do."}
+        },
+        "required": ["timezone", "day", "date", "iso_week", "day_of_year", "days_left_in_year", "utc_offset", "expires_at"]
+      }
+    }
+  }
+}
+`
+
+func (s *Server) GetOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(OpenAPISpec))
+}
@@ -0,0 +1,266 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/discord"
+	"github.com/bradhe/what-day-is-it/pkg/openapi"
+	"github.com/bradhe/what-day-is-it/pkg/slack"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/bradhe/what-day-is-it/pkg/telegram"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// openAPITypes are the types that each schema in the spec describes.
+var openAPITypes = map[string]interface{}{
+	"APIError":                       APIError{},
+	"DeliveryAttempt":                DeliveryAttempt{},
+	"DiscordInteraction":             discord.Interaction{},
+	"DiscordInteractionCallback":     discord.InteractionCallback{},
+	"DiscordInteractionData":         discord.InteractionData{},
+	"DiscordInteractionOption":       discord.InteractionOption{},
+	"DiscordInteractionResponse":     discord.InteractionResponse{},
+	"ErrorResponse":                  ErrorResponse{},
+	"EventResponse":                  EventResponse{},
+	"GetEventsResponse":              GetEventsResponse{},
+	"GetDeliveriesResponse":          GetDeliveriesResponse{},
+	"GetHealthResponse":              GetHealthResponse{},
+	"GetTimezonesResponse":           GetTimezonesResponse{},
+	"PatchSubscriptionRequest":       PatchSubscriptionRequest{},
+	"PauseResponse":                  PauseResponse{},
+	"PostPauseRequest":               PostPauseRequest{},
+	"PostSubscribeRequest":           PostSubscribeRequest{},
+	"PostSubscribeResponse":          PostSubscribeResponse{},
+	"PostSubscribeVerifyRequest":     PostSubscribeVerifyRequest{},
+	"PostSubscriptionRequest":        PostSubscriptionRequest{},
+	"PostSubscriptionResponse":       PostSubscriptionResponse{},
+	"PostSubscriptionVerifyRequest":  PostSubscriptionVerifyRequest{},
+	"PostSubscriptionVerifyResponse": PostSubscriptionVerifyResponse{},
+	"SlackMessage":                   slack.Message{},
+	"Subscription":                   Subscription{},
+	"TelegramChat":                   telegram.Chat{},
+	"TelegramMessage":                telegram.Message{},
+	"TelegramUpdate":                 telegram.Update{},
+	"TimezoneResponse":               TimezoneResponse{},
+	"TodayResponse":                  TodayResponse{},
+}
+
+func mustParseOpenAPISpec(t *testing.T) *openapi.Document {
+	doc, err := openapi.Parse([]byte(OpenAPISpec))
+
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	return doc
+}
+
+var routePatternexp = regexp.MustCompile(`\{(\w+):[^}]+\}`)
+
+func TestOpenAPISpecMatchesRouter(t *testing.T) {
+	doc := mustParseOpenAPISpec(t)
+	s := NewServer(memory.New(), &recordingSender{}, false, "")
+
+	var routed []string
+
+	err := s.apiHandler.(*mux.Router).Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+
+		if err != nil {
+			return err
+		}
+
+		// OpenAPI doesn't have patterns for path parameters.
+		path = routePatternexp.ReplaceAllString(path, "{$1}")
+
+		methods, err := route.GetMethods()
+
+		if err != nil {
+			// Routes that take any method have to document at least one.
+			assert.NotEmpty(t, doc.Paths[path], "%s isn't documented", path)
+
+			for method := range doc.Paths[path] {
+				routed = append(routed, strings.ToUpper(method)+" "+path)
+			}
+
+			return nil
+		}
+
+		for _, method := range methods {
+			routed = append(routed, method+" "+path)
+		}
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+
+	var documented []string
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(routed)
+	sort.Strings(documented)
+
+	assert.Equal(t, routed, documented)
+}
+
+// jsonFields returns the JSON fields of a struct type by name, along with
+// whether each one is left out when it's empty.
+func jsonFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if name != "" && name != "-" {
+			fields[name] = field
+		}
+	}
+
+	return fields
+}
+
+// openAPIType returns the schema type that values of typ are written as.
+func openAPIType(typ reflect.Type) string {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == reflect.TypeOf(time.Time{}) {
+		return "string"
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int64:
+		return "integer"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Slice:
+		return "array"
+	case reflect.Map:
+		return "object"
+	case reflect.Interface:
+		// Anything goes, so the spec doesn't give a type.
+		return ""
+	case reflect.Struct:
+		// Types from other packages get a prefix in the spec, like
+		// DiscordInteraction.
+		for name, obj := range openAPITypes {
+			if reflect.TypeOf(obj) == typ {
+				return "#/components/schemas/" + name
+			}
+		}
+
+		return "#/components/schemas/" + typ.Name()
+	default:
+		return typ.Kind().String()
+	}
+}
+
+func TestOpenAPISpecMatchesTypes(t *testing.T) {
+	doc := mustParseOpenAPISpec(t)
+
+	var names []string
+
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+
+	for name, obj := range openAPITypes {
+		schema, ok := doc.Components.Schemas[name]
+
+		if !assert.True(t, ok, "%s isn't in the spec", name) {
+			continue
+		}
+
+		fields := jsonFields(reflect.TypeOf(obj))
+
+		for _, prop := range schema.Properties {
+			if _, ok := fields[prop.Name]; !ok {
+				t.Errorf("%s.%s is in the spec but not the type", name, prop.Name)
+			}
+		}
+
+		for fieldName, field := range fields {
+			prop := schema.Properties.Get(fieldName)
+
+			if prop == nil {
+				t.Errorf("%s.%s is in the type but not the spec", name, fieldName)
+				continue
+			}
+
+			typ := prop.Type
+
+			if prop.Ref != "" {
+				typ = prop.Ref
+			}
+
+			assert.Equal(t, openAPIType(field.Type), typ, "%s.%s", name, fieldName)
+
+			if schema.IsRequired(fieldName) {
+				assert.NotContains(t, field.Tag.Get("json"), "omitempty", "%s.%s is required but can be left out", name, fieldName)
+			}
+		}
+	}
+
+	assert.Len(t, names, len(openAPITypes), "every schema needs a type")
+}
+
+func TestOpenAPISpecErrorCodes(t *testing.T) {
+	doc := mustParseOpenAPISpec(t)
+
+	codes := []string{
+		ErrorCodeInvalidRequest,
+		ErrorCodeInvalidPhoneNumber,
+		ErrorCodeInvalidChannel,
+		ErrorCodeInvalidWebhookURL,
+		ErrorCodeInvalidEmail,
+		ErrorCodeInvalidVerification,
+		ErrorCodeInvalidTimezone,
+		ErrorCodeAmbiguousTimezone,
+		ErrorCodeInvalidDeliveryTime,
+		ErrorCodeInvalidDays,
+		ErrorCodeInvalidPause,
+		ErrorCodeUnauthorized,
+		ErrorCodeTokenExpired,
+		ErrorCodeForbidden,
+		ErrorCodeNotFound,
+		ErrorCodeCodeExpired,
+		ErrorCodeTooManyAttempts,
+		ErrorCodeTooSoon,
+		ErrorCodeWrongCode,
+		ErrorCodeStopped,
+		ErrorCodeBanned,
+		ErrorCodeNotPausable,
+		ErrorCodeInternal,
+	}
+
+	assert.ElementsMatch(t, codes, doc.Components.Schemas["APIError"].Properties.Get("code").Enum)
+}
+
+func TestGetOpenAPI(t *testing.T) {
+	s := NewServer(memory.New(), &recordingSender{}, false, "")
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, OpenAPISpec, w.Body.String())
+}
@@ -0,0 +1,232 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/gorilla/mux"
+)
+
+// MaxPauseDays is as far out as anyone can pause. Beyond that they should
+// probably just STOP.
+const MaxPauseDays = 365
+
+var (
+	errInvalidPause = errors.New("server: invalid pause")
+	errPauseTooLong = errors.New("server: pause too long")
+	errNotPausable  = errors.New("server: not pausable")
+	errNotPaused    = errors.New("server: not paused")
+)
+
+var pauseForexp = regexp.MustCompile(`^([0-9]+) ?(d|days?|w|wks?|weeks?)$`)
+
+// parseResumeDate works out when a pause described by str should end: the
+// start of the day that deliveries pick back up, in loc. An empty str means
+// the pause doesn't end on its own, so there's no date.
+func parseResumeDate(str string, now *time.Time, loc *time.Location) (*time.Time, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+
+	if str == "" {
+		return nil, nil
+	}
+
+	local := now.In(loc)
+	today := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var resume time.Time
+
+	if strings.HasPrefix(str, "until ") {
+		t, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(strings.TrimPrefix(str, "until ")), loc)
+
+		if err != nil || !t.After(today) {
+			return nil, errInvalidPause
+		}
+
+		resume = t
+	} else if m := pauseForexp.FindStringSubmatch(str); m != nil {
+		n, err := strconv.Atoi(m[1])
+
+		if err != nil || n < 1 {
+			return nil, errInvalidPause
+		}
+
+		if strings.HasPrefix(m[2], "w") {
+			n *= 7
+		}
+
+		if n > MaxPauseDays {
+			return nil, errPauseTooLong
+		}
+
+		resume = today.AddDate(0, 0, n)
+	} else {
+		return nil, errInvalidPause
+	}
+
+	if resume.After(today.AddDate(0, 0, MaxPauseDays)) {
+		return nil, errPauseTooLong
+	}
+
+	return &resume, nil
+}
+
+// formatResumeDate is how we tell people when their texts start again.
+func formatResumeDate(t time.Time) string {
+	return t.Format("Monday, January 2")
+}
+
+// pause stops deliveries to the phone number until the given date, or until it
+// resumes if that's nil.
+func (s *Server) pause(phoneNumber *models.PhoneNumber, until *time.Time, actor string) error {
+	if phoneNumber.Status != models.StatusActive && phoneNumber.Status != models.StatusPaused {
+		return errNotPausable
+	}
+
+	if err := s.managers.PhoneNumbers().Pause(phoneNumber, until, clock.Clock()); err != nil {
+		logger.WithError(err).Error("failed to pause phone number")
+		return err
+	}
+
+	metadata := map[string]string{}
+
+	if until != nil {
+		metadata["until"] = until.Format(time.RFC3339)
+	}
+
+	s.recordEvent(phoneNumber.Number, models.EventPaused, actor, metadata)
+
+	logger.Info("user paused")
+	return nil
+}
+
+// resume starts deliveries to a paused phone number again.
+func (s *Server) resume(phoneNumber *models.PhoneNumber, actor string) error {
+	if phoneNumber.Status != models.StatusPaused {
+		return errNotPaused
+	}
+
+	if err := s.managers.PhoneNumbers().UpdateStatus(phoneNumber, models.StatusActive, clock.Clock()); err != nil {
+		logger.WithError(err).Error("failed to resume phone number")
+		return err
+	}
+
+	s.recordEvent(phoneNumber.Number, models.EventResumed, actor, nil)
+
+	logger.Info("user resumed")
+	return nil
+}
+
+type PostPauseRequest struct {
+	// How long to pause for, like "7d" or "2w".
+	For string `json:"for,omitempty"`
+
+	// The date to pick back up on, like "2026-11-01". Takes precedence over
+	// For. With neither the pause lasts until it's resumed.
+	Until string `json:"until,omitempty"`
+}
+
+type PauseResponse struct {
+	Number string `json:"number,omitempty"`
+
+	Status models.Status `json:"status,omitempty"`
+
+	// When deliveries start again, if the pause ends on its own.
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// getPausablePhoneNumber looks up the phone number in the request path,
+// writing the error response if it can't.
+func (s *Server) getPausablePhoneNumber(w http.ResponseWriter, r *http.Request) (models.PhoneNumber, bool) {
+	num := models.CleanSubscriberID(mux.Vars(r)["number"])
+	phoneNumber, err := s.managers.PhoneNumbers().Get(num)
+
+	if err == managers.ErrRecordNotFound {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write(Dump(PauseResponse{Number: num, Error: "Not subscribed."}))
+		return phoneNumber, false
+	} else if err != nil {
+		logger.WithError(err).Error("failed to find phone number")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write(Dump(PauseResponse{Number: num, Error: "An internal error occured."}))
+		return phoneNumber, false
+	}
+
+	return phoneNumber, true
+}
+
+func (s *Server) writePauseResponse(w http.ResponseWriter, phoneNumber models.PhoneNumber, err error) {
+	resp := PauseResponse{Number: phoneNumber.Number, Status: phoneNumber.Status}
+
+	switch err {
+	case nil:
+		resp.PausedUntil = phoneNumber.PausedUntil
+	case errNotPausable:
+		w.WriteHeader(http.StatusConflict)
+		resp.Error = "Only active subscriptions can be paused."
+	case errNotPaused:
+		w.WriteHeader(http.StatusConflict)
+		resp.Error = "The subscription isn't paused."
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		resp.Error = "An internal error occured."
+	}
+
+	w.Write(Dump(resp))
+}
+
+func (s *Server) PostPause(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req PostPauseRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.WithError(err).Error("failed to decode request body")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(Dump(PauseResponse{Error: "Failed to read the pause request. Did you send it as JSON?"}))
+		return
+	}
+
+	phoneNumber, ok := s.getPausablePhoneNumber(w, r)
+
+	if !ok {
+		return
+	}
+
+	str := req.For
+
+	if req.Until != "" {
+		str = "until " + req.Until
+	}
+
+	until, err := parseResumeDate(str, clock.Clock(), clock.MustLoadLocation(phoneNumber.Timezone))
+
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(Dump(PauseResponse{Number: phoneNumber.Number, Error: "Invalid pause. Use a date in the next year, or a number of days or weeks."}))
+		return
+	}
+
+	err = s.pause(&phoneNumber, until, models.ActorWeb)
+	s.writePauseResponse(w, phoneNumber, err)
+}
+
+func (s *Server) PostResume(w http.ResponseWriter, r *http.Request) {
+	phoneNumber, ok := s.getPausablePhoneNumber(w, r)
+
+	if !ok {
+		return
+	}
+
+	err := s.resume(&phoneNumber, models.ActorWeb)
+	s.writePauseResponse(w, phoneNumber, err)
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseResumeDate(t *testing.T) {
+	tests := []struct {
+		str      string
+		expected string
+		err      error
+	}{
+		{"", "", nil},
+		{"7d", "2026-10-26T00:00:00-07:00", nil},
+		{"7 days", "2026-10-26T00:00:00-07:00", nil},
+		{"1 day", "2026-10-20T00:00:00-07:00", nil},
+		{"2w", "2026-11-02T00:00:00-08:00", nil},
+		{"3 Weeks", "2026-11-09T00:00:00-08:00", nil},
+		{"until 2026-11-01", "2026-11-01T00:00:00-07:00", nil},
+		{"UNTIL 2026-10-20", "2026-10-20T00:00:00-07:00", nil},
+		{"until 2026-10-19", "", errInvalidPause},
+		{"until tomorrow", "", errInvalidPause},
+		{"0d", "", errInvalidPause},
+		{"fortnight", "", errInvalidPause},
+		{"366d", "", errPauseTooLong},
+		{"until 2027-12-01", "", errPauseTooLong},
+	}
+
+	// Dates are counted from the local day, and DST ends on November 1st.
+	now := mustParseTime("2026-10-19T17:00:00Z")
+	loc := clock.MustLoadLocation("America/Los_Angeles")
+
+	for _, test := range tests {
+		t.Run(test.str, func(t *testing.T) {
+			resume, err := parseResumeDate(test.str, now, loc)
+			assert.Equal(t, test.err, err)
+
+			if test.expected == "" {
+				assert.Nil(t, resume)
+			} else if assert.NotNil(t, resume) {
+				assert.Equal(t, test.expected, resume.Format(time.RFC3339))
+			}
+		})
+	}
+}
+
+func postPause(s *Server, number, action, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/phone-numbers/"+number+"/"+action, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer sekrit")
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestPostPauseAndResume(t *testing.T) {
+	s := NewServer(memory.New(), &recordingSender{}, false, "")
+	s.AdminToken = "sekrit"
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "America/Los_Angeles", Status: models.StatusActive}))
+
+		w := postPause(s, "+14155551234", "pause", `{"until": "2026-11-01"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"number": "+14155551234", "status": "paused", "paused_until": "2026-11-01T00:00:00-07:00"}`, w.Body.String())
+
+		// Pausing again just moves the date.
+		w = postPause(s, "+14155551234", "pause", `{"for": "2w"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"number": "+14155551234", "status": "paused", "paused_until": "2026-11-02T00:00:00-08:00"}`, w.Body.String())
+
+		w = postPause(s, "+14155551234", "pause", `{"for": "forever"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = postPause(s, "+14155551234", "resume", ``)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"number": "+14155551234", "status": "active"}`, w.Body.String())
+
+		w = postPause(s, "+14155551234", "resume", ``)
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		w = postPause(s, "+14155559999", "pause", `{}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		events, err := s.managers.Events().List("+14155551234", 10)
+		assert.NoError(t, err)
+		assert.Len(t, events, 3)
+		assert.Equal(t, models.EventResumed, events[0].Type)
+		assert.Equal(t, models.ActorWeb, events[0].Actor)
+	})
+}
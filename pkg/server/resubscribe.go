@@ -0,0 +1,41 @@
+package server
+
+import "github.com/bradhe/what-day-is-it/pkg/models"
+
+// resubscribe handles a subscribe request for a number that we already know
+// about. Anybody can fill out the form, so nothing changes until the owner
+// proves that it's theirs. If it never confirmed it's asked to again, and
+// otherwise it gets a code that makes the changes when it's verified.
+// Subscribers who stopped or bounced on a channel that can't send START get a
+// code that brings them back, and everybody else who stopped, bounced or was
+// banned doesn't get anything.
+func (s *Server) resubscribe(requested models.PhoneNumber, verification string) (models.PhoneNumber, string, error) {
+	existing, err := s.managers.PhoneNumbers().Get(requested.Number)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to find existing phone number")
+		return requested, verification, err
+	}
+
+	switch existing.Status {
+	case models.StatusPending:
+		return existing, verification, s.askToConfirm(existing, verification, requested)
+	case models.StatusStopped, models.StatusBounced:
+		if !existing.DeliveryChannel().TakesKeywords() {
+			logger.Info("stopped subscriber has to verify to resubscribe")
+			return requested, VerifyByCode, s.askToConfirm(existing, VerifyByCode, requested)
+		}
+
+		logger.Info("stopped phone number has to text START to resubscribe")
+	case models.StatusBanned:
+		logger.Warn("banned phone number tried to resubscribe")
+	default:
+		// Only the owner gets to see or change what they already have, so
+		// the request is all that we show until they verify it.
+		logger.Info("subscribed phone number has to verify changes")
+		return requested, VerifyByCode, s.askToConfirm(existing, VerifyByCode, requested)
+	}
+
+	requested.Status = existing.Status
+	return requested, verification, nil
+}
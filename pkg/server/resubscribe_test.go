@@ -0,0 +1,181 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func postSubscribe(s *Server, body string) (int, PostSubscribeResponse) {
+	w := httptest.NewRecorder()
+	s.PostSubscribe(w, httptest.NewRequest(http.MethodPost, "/api/subscribe", strings.NewReader(body)))
+
+	var resp PostSubscribeResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	return w.Code, resp
+}
+
+func TestPostSubscribeResubscribes(t *testing.T) {
+	tests := []struct {
+		name                 string
+		existing             models.PhoneNumber
+		body                 string
+		verificationRequired bool
+		confirmationRequired bool
+		messages             int
+	}{
+		{
+			"subscribed number has to verify a new timezone",
+			models.PhoneNumber{Number: "+14155551234", Timezone: "America/Los_Angeles", Status: models.StatusActive},
+			`{"number": "+14155551234", "timezone": "Asia/Tokyo"}`,
+			true, false, 1,
+		},
+		{
+			"paused number has to verify too",
+			models.PhoneNumber{Number: "+14155551234", Timezone: "America/Los_Angeles", Status: models.StatusPaused},
+			`{"number": "+14155551234", "timezone": "Asia/Tokyo"}`,
+			true, false, 1,
+		},
+		{
+			"stopped number has to confirm",
+			models.PhoneNumber{Number: "+14155551234", Timezone: "America/Los_Angeles", Status: models.StatusStopped},
+			`{"number": "+14155551234", "timezone": "Asia/Tokyo"}`,
+			false, true, 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sender := &recordingSender{}
+			s := &Server{DefaultTimeZone: "UTC", managers: memory.New(), Senders: Senders{models.ChannelSMS: sender}}
+			assert.NoError(t, s.managers.PhoneNumbers().Create(test.existing))
+
+			code, resp := postSubscribe(s, test.body)
+			assert.Equal(t, http.StatusOK, code)
+			assert.False(t, resp.Subscribed)
+			assert.Equal(t, test.verificationRequired, resp.VerificationRequired)
+			assert.Equal(t, test.confirmationRequired, resp.ConfirmationRequired)
+			assert.Equal(t, "Asia/Tokyo", resp.Timezone)
+			assert.Len(t, sender.Sent(), test.messages)
+
+			// Nothing changes until the code comes back.
+			phoneNumber, err := s.managers.PhoneNumbers().Get("+14155551234")
+			assert.NoError(t, err)
+			assert.Equal(t, test.existing.Timezone, phoneNumber.Timezone)
+			assert.Equal(t, test.existing.Status, phoneNumber.Status)
+			assert.Nil(t, phoneNumber.ResubscribedAt)
+		})
+	}
+}
+
+func TestPostSubscriptionChangesExistingOnlyWithCode(t *testing.T) {
+	s, sender := newTestAPIServer()
+	assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "America/Los_Angeles", Status: models.StatusActive}))
+
+	w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"number": "+14155551234", "timezone": "Asia/Tokyo", "delivery_time": "09:30"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp PostSubscriptionResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	assert.True(t, resp.VerificationRequired)
+	assert.Equal(t, models.StatusPending, resp.Subscription.Status)
+
+	phoneNumber, _ := s.managers.PhoneNumbers().Get("+14155551234")
+	assert.Equal(t, "America/Los_Angeles", phoneNumber.Timezone)
+	assert.Nil(t, phoneNumber.Schedule)
+
+	sent := sender.Sent()
+	assert.Len(t, sent, 1)
+	matches := codeexp.FindStringSubmatch(sent[0].Body)
+	assert.Len(t, matches, 2)
+
+	w = doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions/+14155551234/verify", "", `{"code": "`+matches[1]+`"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var verified PostSubscriptionVerifyResponse
+	json.Unmarshal(w.Body.Bytes(), &verified)
+	assert.NotEmpty(t, verified.ManageToken)
+	assert.Equal(t, "Asia/Tokyo", verified.Subscription.Timezone)
+	assert.Equal(t, "09:30", verified.Subscription.DeliveryTime)
+
+	phoneNumber, _ = s.managers.PhoneNumbers().Get("+14155551234")
+	assert.Equal(t, models.StatusActive, phoneNumber.Status)
+	assert.Equal(t, "Asia/Tokyo", phoneNumber.Timezone)
+}
+
+func TestStoppedNumberResubscribesWithStart(t *testing.T) {
+	s := &Server{DefaultTimeZone: "UTC", managers: memory.New(), Senders: Senders{models.ChannelSMS: &recordingSender{}}}
+	assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "America/Los_Angeles", Status: models.StatusStopped}))
+
+	_, resp := postSubscribe(s, `{"number": "+14155551234", "timezone": "Asia/Tokyo"}`)
+	assert.True(t, resp.ConfirmationRequired)
+
+	reply, err := s.handleKeyword(keywordStart, models.ChannelSMS, "+14155551234")
+	assert.NoError(t, err)
+	assert.Equal(t, startConfirmation, reply)
+
+	phoneNumber, _ := s.managers.PhoneNumbers().Get("+14155551234")
+	assert.True(t, phoneNumber.IsSendable())
+	assert.NotNil(t, phoneNumber.ResubscribedAt)
+
+	// The form didn't prove anything, so START brings back what they had.
+	assert.Equal(t, "America/Los_Angeles", phoneNumber.Timezone)
+}
+
+func TestStoppedSubscribersWithoutStartResubscribeWithCode(t *testing.T) {
+	tests := []struct {
+		channel models.Channel
+		address string
+		body    string
+	}{
+		{models.ChannelEmail, "someone@example.com", `{"channel": "email", "email": "someone@example.com", "timezone": "Tokyo"}`},
+		{models.ChannelSlack, testWebhookURL, `{"channel": "slack", "webhook_url": "` + testWebhookURL + `", "timezone": "Tokyo"}`},
+		{models.ChannelDiscord, testDiscordWebhookURL, `{"channel": "discord", "webhook_url": "` + testDiscordWebhookURL + `", "timezone": "Tokyo"}`},
+		{models.ChannelWebhook, testGenericWebhookURL, `{"channel": "webhook", "webhook_url": "` + testGenericWebhookURL + `", "timezone": "Tokyo"}`},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.channel), func(t *testing.T) {
+			s, _ := newTestAPIServer()
+			sender := &recordingSender{}
+			s.Senders[test.channel] = sender
+
+			num := models.SubscriberID(test.channel, test.address)
+			existing := models.PhoneNumber{Number: num, Channel: test.channel, Address: test.address, Timezone: "America/Los_Angeles", Status: models.StatusStopped}
+
+			assert.NoError(t, s.managers.PhoneNumbers().Create(existing))
+
+			w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", test.body)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var resp PostSubscriptionResponse
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			assert.True(t, resp.VerificationRequired)
+
+			// Still stopped until the code comes back.
+			phoneNumber, _ := s.managers.PhoneNumbers().Get(num)
+			assert.Equal(t, models.StatusStopped, phoneNumber.Status)
+
+			sent := sender.Sent()
+			if !assert.Len(t, sent, 1) {
+				return
+			}
+
+			code := codeexp.FindStringSubmatch(sent[0].Body)[1]
+			w = doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions/"+num+"/verify", "", `{"code": "`+code+`"}`)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			phoneNumber, _ = s.managers.PhoneNumbers().Get(num)
+			assert.Equal(t, models.StatusActive, phoneNumber.Status)
+			assert.Equal(t, "Asia/Tokyo", phoneNumber.Timezone)
+			assert.NotNil(t, phoneNumber.ResubscribedAt)
+		})
+	}
+}
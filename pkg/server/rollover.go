@@ -0,0 +1,307 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+)
+
+// RolloverHeartbeatInterval is how often idle rollover streams get a comment
+// so that proxies don't hang up on them.
+var RolloverHeartbeatInterval = 15 * time.Second
+
+// rolloverEvent is a day starting somewhere. Its ID is when the day started,
+// which is how clients that reconnect tell us what they missed.
+type rolloverEvent struct {
+	ID    int64
+	Today TodayResponse
+}
+
+func newRolloverEvent(start time.Time) rolloverEvent {
+	return rolloverEvent{start.Unix(), newTodayResponse(start)}
+}
+
+// rolloverSubscriber is a stream that's waiting for days to start. A nil
+// event is a heartbeat.
+type rolloverSubscriber struct {
+	events chan *rolloverEvent
+}
+
+type rolloverZone struct {
+	loc         *time.Location
+	next        time.Time
+	subscribers map[*rolloverSubscriber]bool
+}
+
+// rolloverHub tells streams when a day starts in the zones they asked about.
+// There's one timer for the next midnight anywhere and one heartbeat ticker,
+// no matter how many streams there are.
+type rolloverHub struct {
+	sync.Mutex
+	zones map[string]*rolloverZone
+	wake  chan struct{}
+	once  sync.Once
+}
+
+func newRolloverHub() *rolloverHub {
+	return &rolloverHub{
+		zones: make(map[string]*rolloverZone),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+func (h *rolloverHub) subscribe(timezones []string) *rolloverSubscriber {
+	h.once.Do(func() {
+		go h.run()
+	})
+
+	sub := &rolloverSubscriber{make(chan *rolloverEvent, 16)}
+
+	h.Lock()
+	defer h.Unlock()
+
+	now := *clock.Clock()
+
+	for _, timezone := range timezones {
+		zone, ok := h.zones[timezone]
+
+		if !ok {
+			loc := clock.MustLoadLocation(timezone)
+
+			zone = &rolloverZone{
+				loc:         loc,
+				next:        clock.NextMidnight(now.In(loc)),
+				subscribers: make(map[*rolloverSubscriber]bool),
+			}
+
+			h.zones[timezone] = zone
+		}
+
+		zone.subscribers[sub] = true
+	}
+
+	// The timer might need to go off sooner now.
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+
+	return sub
+}
+
+func (h *rolloverHub) unsubscribe(sub *rolloverSubscriber) {
+	h.Lock()
+	defer h.Unlock()
+
+	for timezone, zone := range h.zones {
+		delete(zone.subscribers, sub)
+
+		if len(zone.subscribers) == 0 {
+			delete(h.zones, timezone)
+		}
+	}
+}
+
+// send gives sub the event unless it's too far behind to take it. Streams
+// that are that far behind are stuck, and will catch up when they reconnect.
+func (sub *rolloverSubscriber) send(event *rolloverEvent) {
+	select {
+	case sub.events <- event:
+	default:
+	}
+}
+
+// tick sends an event for each zone where the day started by now, and
+// returns how long it is until the next one.
+func (h *rolloverHub) tick(now time.Time) time.Duration {
+	h.Lock()
+	defer h.Unlock()
+
+	var next time.Time
+
+	for _, zone := range h.zones {
+		if !now.Before(zone.next) {
+			event := newRolloverEvent(zone.next)
+
+			for sub := range zone.subscribers {
+				sub.send(&event)
+			}
+
+			zone.next = clock.NextMidnight(now.In(zone.loc))
+		}
+
+		if next.IsZero() || zone.next.Before(next) {
+			next = zone.next
+		}
+	}
+
+	if next.IsZero() {
+		// Nobody's listening. Wait until somebody subscribes.
+		return time.Hour
+	}
+
+	return next.Sub(now)
+}
+
+func (h *rolloverHub) heartbeat() {
+	h.Lock()
+	defer h.Unlock()
+
+	seen := make(map[*rolloverSubscriber]bool)
+
+	for _, zone := range h.zones {
+		for sub := range zone.subscribers {
+			if !seen[sub] {
+				seen[sub] = true
+				sub.send(nil)
+			}
+		}
+	}
+}
+
+func (h *rolloverHub) run() {
+	heartbeat := time.NewTicker(RolloverHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		timer := time.NewTimer(h.tick(*clock.Clock()))
+
+		select {
+		case <-timer.C:
+		case <-heartbeat.C:
+			h.heartbeat()
+		case <-h.wake:
+		}
+
+		timer.Stop()
+	}
+}
+
+// parseLastEventID returns when the last day that a stream saw started, or
+// zero if it's new. IDs that aren't ours, or that are from the future, mean
+// that the stream starts over.
+func parseLastEventID(str string, now time.Time) int64 {
+	if last, err := strconv.ParseInt(str, 10, 64); err == nil && last <= now.Unix() {
+		return last
+	}
+
+	return 0
+}
+
+// initialRolloverEvents returns the events that a stream starts with, which is
+// today in each zone, or only the days that started after last for streams
+// that are picking back up.
+func initialRolloverEvents(timezones []string, now time.Time, last int64) []rolloverEvent {
+	var events []rolloverEvent
+
+	for _, timezone := range timezones {
+		event := newRolloverEvent(clock.StartOfDay(now.In(clock.MustLoadLocation(timezone))))
+
+		if event.ID > last {
+			events = append(events, event)
+		}
+	}
+
+	// IDs only go up so that Last-Event-ID is the latest one.
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].ID < events[j].ID
+	})
+
+	return events
+}
+
+func writeRolloverEvent(w http.ResponseWriter, event *rolloverEvent) {
+	if event == nil {
+		fmt.Fprint(w, ": heartbeat\n\n")
+	} else {
+		fmt.Fprintf(w, "id: %d\nevent: rollover\ndata: %s\n\n", event.ID, Dump(event.Today))
+	}
+
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (s *Server) GetRolloverStream(w http.ResponseWriter, r *http.Request) {
+	var timezones []string
+
+	seen := make(map[string]bool)
+
+	for _, tz := range r.URL.Query()["tz"] {
+		for _, str := range strings.Split(tz, ",") {
+			if str = strings.TrimSpace(str); str == "" {
+				continue
+			}
+
+			timezone, rerr := resolveTimezone(str)
+
+			if rerr != nil {
+				writeRequestError(w, rerr)
+				return
+			}
+
+			if !seen[timezone] {
+				seen[timezone] = true
+				timezones = append(timezones, timezone)
+			}
+		}
+	}
+
+	if len(timezones) == 0 {
+		timezones = []string{s.DefaultTimeZone}
+	}
+
+	sub := s.rollovers.subscribe(timezones)
+	defer s.rollovers.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	now := *clock.Clock()
+	last := parseLastEventID(r.Header.Get("Last-Event-ID"), now)
+
+	for _, event := range initialRolloverEvents(timezones, now, last) {
+		event := event
+		writeRolloverEvent(w, &event)
+	}
+
+	// When today started in each zone. Zones that share an offset start their
+	// days at the same time, so this has to be kept per zone.
+	started := make(map[string]int64)
+
+	for _, timezone := range timezones {
+		started[timezone] = clock.StartOfDay(now.In(clock.MustLoadLocation(timezone))).Unix()
+	}
+
+	// Makes sure that the headers go out even if there was nothing to send.
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-sub.events:
+			if event != nil {
+				// A day that started while we were connecting has been
+				// sent already.
+				if event.ID <= started[event.Today.Timezone] {
+					continue
+				}
+
+				started[event.Today.Timezone] = event.ID
+			}
+
+			writeRolloverEvent(w, event)
+		}
+	}
+}
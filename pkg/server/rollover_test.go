@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRolloverHub returns a hub that only ticks when it's told to.
+func newTestRolloverHub() *rolloverHub {
+	h := newRolloverHub()
+	h.once.Do(func() {})
+	return h
+}
+
+func anySubscriber(h *rolloverHub) *rolloverSubscriber {
+	h.Lock()
+	defer h.Unlock()
+
+	for _, zone := range h.zones {
+		for sub := range zone.subscribers {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+func receive(sub *rolloverSubscriber) []*rolloverEvent {
+	var events []*rolloverEvent
+
+	for {
+		select {
+		case event := <-sub.events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestRolloverHubTick(t *testing.T) {
+	h := newTestRolloverHub()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		tokyo := h.subscribe([]string{"Asia/Tokyo"})
+		both := h.subscribe([]string{"Asia/Tokyo", "UTC"})
+
+		// The next day to start is UTC's, seven hours from now.
+		assert.Equal(t, 7*time.Hour, h.tick(*mustParseTime("2026-10-19T17:00:00Z")))
+		assert.Empty(t, receive(tokyo))
+		assert.Empty(t, receive(both))
+
+		assert.Equal(t, 15*time.Hour, h.tick(*mustParseTime("2026-10-20T00:00:00Z")))
+		assert.Empty(t, receive(tokyo))
+
+		if events := receive(both); assert.Len(t, events, 1) {
+			assert.Equal(t, mustParseTime("2026-10-20T00:00:00Z").Unix(), events[0].ID)
+			assert.Equal(t, "Tuesday", events[0].Today.Day)
+			assert.Equal(t, "UTC", events[0].Today.Timezone)
+		}
+
+		// Running late still tells everyone about the day that started.
+		h.tick(*mustParseTime("2026-10-20T15:00:01Z"))
+
+		for _, sub := range []*rolloverSubscriber{tokyo, both} {
+			if events := receive(sub); assert.Len(t, events, 1) {
+				assert.Equal(t, mustParseTime("2026-10-20T15:00:00Z").Unix(), events[0].ID)
+				assert.Equal(t, "Wednesday", events[0].Today.Day)
+			}
+		}
+
+		h.unsubscribe(tokyo)
+		h.unsubscribe(both)
+		assert.Empty(t, h.zones)
+		assert.Equal(t, time.Hour, h.tick(*mustParseTime("2026-10-21T00:00:00Z")))
+	})
+}
+
+func TestRolloverHubHeartbeat(t *testing.T) {
+	h := newTestRolloverHub()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		sub := h.subscribe([]string{"Asia/Tokyo", "UTC"})
+		h.heartbeat()
+		assert.Equal(t, []*rolloverEvent{nil}, receive(sub))
+	})
+}
+
+func TestInitialRolloverEvents(t *testing.T) {
+	now := *mustParseTime("2026-10-19T17:00:00Z")
+	utc := mustParseTime("2026-10-19T00:00:00Z").Unix()
+	tokyo := mustParseTime("2026-10-19T15:00:00Z").Unix()
+
+	ids := func(events []rolloverEvent) []int64 {
+		result := []int64{}
+
+		for _, event := range events {
+			result = append(result, event.ID)
+		}
+
+		return result
+	}
+
+	timezones := []string{"Asia/Tokyo", "UTC"}
+
+	assert.Equal(t, []int64{utc, tokyo}, ids(initialRolloverEvents(timezones, now, 0)))
+	assert.Equal(t, []int64{tokyo}, ids(initialRolloverEvents(timezones, now, utc)))
+	assert.Equal(t, []int64{}, ids(initialRolloverEvents(timezones, now, tokyo)))
+
+	assert.Equal(t, utc, parseLastEventID("1792368000", now))
+	assert.Equal(t, int64(0), parseLastEventID("", now))
+	assert.Equal(t, int64(0), parseLastEventID("nope", now))
+	assert.Equal(t, int64(0), parseLastEventID("9999999999", now))
+}
+
+// parseStream returns the events in an event stream, keyed by field.
+func parseStream(body string) []map[string]string {
+	var events []map[string]string
+
+	for _, block := range strings.Split(strings.TrimSpace(body), "\n\n") {
+		event := make(map[string]string)
+
+		for _, line := range strings.Split(block, "\n") {
+			if kv := strings.SplitN(line, ": ", 2); len(kv) == 2 {
+				event[kv[0]] = kv[1]
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// streamRollovers runs a rollover stream for req, ticks the hub at each of
+// ticks once the stream is listening, and returns what the stream wrote.
+func streamRollovers(s *Server, req *http.Request, ticks ...time.Time) *httptest.ResponseRecorder {
+	ctx, cancel := context.WithCancel(req.Context())
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+
+	go func() {
+		s.ServeHTTP(w, req)
+		close(done)
+	}()
+
+	// Wait for the stream to subscribe before midnight comes.
+	sub := anySubscriber(s.rollovers)
+
+	for sub == nil {
+		time.Sleep(time.Millisecond)
+		sub = anySubscriber(s.rollovers)
+	}
+
+	for _, tick := range ticks {
+		s.rollovers.tick(tick)
+	}
+
+	s.rollovers.heartbeat()
+
+	// The stream writes each event before it takes the next one.
+	for len(sub.events) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	<-done
+
+	return w
+}
+
+func TestGetRolloverStream(t *testing.T) {
+	s, _ := newTestAPIServer()
+	s.rollovers = newTestRolloverHub()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stream/rollover?tz=Tokyo,utc&tz=JST", nil)
+		req.Header.Set("Last-Event-ID", "1792368000")
+
+		w := streamRollovers(s, req, *mustParseTime("2026-10-20T00:00:00Z"))
+
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+
+		body := w.Body.String()
+		assert.Contains(t, body, "\n\n: heartbeat\n\n")
+
+		events := parseStream(strings.Replace(body, ": heartbeat\n\n", "", -1))
+
+		// UTC's day had started before the last event, so it isn't sent again.
+		if assert.Len(t, events, 2) {
+			var today TodayResponse
+
+			assert.Equal(t, "1792422000", events[0]["id"])
+			assert.Equal(t, "rollover", events[0]["event"])
+			assert.NoError(t, json.Unmarshal([]byte(events[0]["data"]), &today))
+			assert.Equal(t, "Asia/Tokyo", today.Timezone)
+			assert.Equal(t, "Tuesday", today.Day)
+
+			assert.Equal(t, "1792454400", events[1]["id"])
+			assert.NoError(t, json.Unmarshal([]byte(events[1]["data"]), &today))
+			assert.Equal(t, "UTC", today.Timezone)
+			assert.Equal(t, "Tuesday", today.Day)
+		}
+
+		assert.Empty(t, s.rollovers.zones)
+	})
+}
+
+func TestGetRolloverStreamSharedMidnight(t *testing.T) {
+	s, _ := newTestAPIServer()
+	s.rollovers = newTestRolloverHub()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/stream/rollover?tz=Europe/Berlin,Europe/Paris", nil)
+
+		// Both days start at 22:00 UTC, so both events have the same ID.
+		w := streamRollovers(s, req, *mustParseTime("2026-10-19T22:00:00Z"))
+
+		events := parseStream(strings.Replace(w.Body.String(), ": heartbeat\n\n", "", -1))
+
+		if assert.Len(t, events, 4) {
+			var timezones []string
+
+			for i, event := range events {
+				var today TodayResponse
+
+				assert.NoError(t, json.Unmarshal([]byte(event["data"]), &today))
+				timezones = append(timezones, today.Timezone)
+
+				if i < 2 {
+					assert.Equal(t, "1792360800", event["id"])
+					assert.Equal(t, "Monday", today.Day)
+				} else {
+					assert.Equal(t, "1792447200", event["id"])
+					assert.Equal(t, "Tuesday", today.Day)
+				}
+			}
+
+			assert.ElementsMatch(t, []string{"Europe/Berlin", "Europe/Paris"}, timezones[:2])
+			assert.ElementsMatch(t, []string{"Europe/Berlin", "Europe/Paris"}, timezones[2:])
+		}
+	})
+}
+
+func TestGetRolloverStreamBadTimezone(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	w := doAPIRequest(s, http.MethodGet, "/api/stream/rollover?tz=Tokyo,Atlantis", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrorCodeInvalidTimezone, decodeAPIError(w).Code)
+}
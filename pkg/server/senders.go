@@ -0,0 +1,31 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+)
+
+// Senders deliver messages on each channel.
+type Senders map[models.Channel]Sender
+
+// Send delivers body to phoneNumber on whichever channel it subscribed on.
+func (s Senders) Send(phoneNumber models.PhoneNumber, body string) error {
+	sender, ok := s[phoneNumber.DeliveryChannel()]
+
+	if !ok {
+		return fmt.Errorf("server: can't deliver on channel %s", phoneNumber.DeliveryChannel())
+	}
+
+	return sender.Send(phoneNumber.DeliveryAddress(), body)
+}
+
+// IsUndeliverable indicates that err means the subscriber can't get messages
+// at all, as opposed to something going wrong this time around.
+func IsUndeliverable(err error) bool {
+	if uerr, ok := err.(interface{ Undeliverable() bool }); ok {
+		return uerr.Undeliverable()
+	}
+
+	return false
+}
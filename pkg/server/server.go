@@ -1,22 +1,76 @@
 package server
 
 import (
+	"crypto/ed25519"
 	"net/http"
+	"time"
 
+	"github.com/bradhe/what-day-is-it/pkg/models"
 	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
-	"github.com/bradhe/what-day-is-it/pkg/twilio"
 	"github.com/bradhe/what-day-is-it/pkg/ui"
 	"github.com/gorilla/mux"
 )
 
 var DefaultTimeZone = "America/Los_Angeles"
 
+// DefaultBaseURL is where the site lives.
+var DefaultBaseURL = "https://what-day-is-today.com"
+
+// DefaultConfirmationWindow is how long people have to confirm their
+// subscription before we forget about them.
+var DefaultConfirmationWindow = 24 * time.Hour
+
+// Sender delivers a message to an address on one channel, like a text message
+// to a phone number.
+type Sender interface {
+	Send(to, body string) error
+}
+
 type Server struct {
 	DefaultTimeZone string
 
+	// Where the site lives, for links that we text people.
+	BaseURL string
+
+	// Phone numbers that are allowed to run admin-only SMS commands.
+	AdminNumbers []string
+
+	// Bearer token for the admin API. The admin API is off if it's empty.
+	AdminToken string
+
+	// Signs the tokens that we hand out to subscribers. Subscribers can't
+	// manage their subscriptions through the API if it's empty.
+	SigningSecret []byte
+
+	// How long a new subscriber has to reply YES before their subscription
+	// expires.
+	ConfirmationWindow time.Duration
+
+	// Delivers messages on each channel. SMS goes to the sender that the
+	// server was made with.
+	Senders Senders
+
+	// Verifies that slash commands came from Slack. Slash commands are off if
+	// it's empty.
+	SlackSigningSecret []byte
+
+	// Verifies that interactions came from Discord. Interactions are off if
+	// it's empty.
+	DiscordPublicKey ed25519.PublicKey
+
+	// Telegram sends this with each update to the bot's webhook. The webhook
+	// is off if it's empty.
+	TelegramSecretToken string
+
+	// Verifies that webhooks came from Twilio. Incoming texts and calls are
+	// turned away if it's empty.
+	TwilioAuthToken string
+
 	managers managers.Managers
 	server   *http.Server
-	sender   *twilio.Sender
+	commands *commandRouter
+
+	rollovers *rolloverHub
 
 	apiHandler http.Handler
 	uiHandler  ui.Handler
@@ -36,17 +90,50 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func NewServer(managers managers.Managers, sender *twilio.Sender, development bool, assetBasedir string) *Server {
+func NewServer(managers managers.Managers, sender Sender, development bool, assetBasedir string) *Server {
 	server := &Server{
-		DefaultTimeZone: DefaultTimeZone,
-		managers:        managers,
-		sender:          sender,
+		DefaultTimeZone:    DefaultTimeZone,
+		BaseURL:            DefaultBaseURL,
+		ConfirmationWindow: DefaultConfirmationWindow,
+		managers:           managers,
+		Senders:            Senders{models.ChannelSMS: sender},
+		commands:           newCommandRouter(),
+		rollovers:          newRolloverHub(),
 	}
 
+	registerDefaultCommands(server.commands)
+	registerTimezoneCommands(server.commands)
+	registerPauseCommands(server.commands)
+	registerManageCommands(server.commands)
+
 	r := mux.NewRouter()
 	r.HandleFunc("/api/health", server.GetHealth)
+	r.HandleFunc("/api/openapi.json", server.GetOpenAPI).Methods("GET")
+	r.HandleFunc("/api/today", server.GetToday).Methods("GET")
+	r.HandleFunc("/api/timezones", server.GetTimezones).Methods("GET")
+	r.HandleFunc("/api/calendar/{tz:.+}.ics", server.GetCalendar).Methods("GET")
+	r.HandleFunc("/api/stream/rollover", server.GetRolloverStream).Methods("GET")
 	r.HandleFunc("/api/subscribe", server.PostSubscribe)
+	r.HandleFunc("/api/subscribe/verify", server.PostSubscribeVerify)
+	r.HandleFunc("/api/v1/subscriptions", server.PostSubscription).Methods("POST")
+	r.HandleFunc("/api/v1/subscriptions/{number}", server.GetSubscription).Methods("GET")
+	r.HandleFunc("/api/v1/subscriptions/{number}", server.PatchSubscription).Methods("PATCH")
+	r.HandleFunc("/api/v1/subscriptions/{number}", server.DeleteSubscription).Methods("DELETE")
+	r.HandleFunc("/api/v1/subscriptions/{number}/verify", server.PostSubscriptionVerify).Methods("POST")
+	r.HandleFunc("/api/v1/subscriptions/{number}/deliveries", server.GetSubscriptionDeliveries).Methods("GET")
 	r.HandleFunc("/api/incoming-message", server.PostIncomingMessage)
+	r.HandleFunc("/api/incoming-call", server.PostIncomingCall).Methods("POST")
+	r.HandleFunc("/api/incoming-call/menu", server.PostIncomingCallMenu).Methods("POST")
+	r.HandleFunc("/api/slack/command", server.PostSlackCommand).Methods("POST")
+	r.HandleFunc("/api/discord/interactions", server.PostDiscordInteraction).Methods("POST")
+	r.HandleFunc("/api/telegram/webhook", server.PostTelegramUpdate).Methods("POST")
+	r.HandleFunc("/manage", server.GetManage).Methods("GET")
+	r.HandleFunc("/manage", server.PostManage).Methods("POST")
+	r.HandleFunc("/unsubscribe", server.GetUnsubscribe).Methods("GET")
+	r.HandleFunc("/unsubscribe", server.PostUnsubscribe).Methods("POST")
+	r.HandleFunc("/api/admin/phone-numbers/{number}/events", server.requireAdmin(server.GetEvents)).Methods("GET")
+	r.HandleFunc("/api/admin/phone-numbers/{number}/pause", server.requireAdmin(server.PostPause)).Methods("POST")
+	r.HandleFunc("/api/admin/phone-numbers/{number}/resume", server.requireAdmin(server.PostResume)).Methods("POST")
 
 	base := &http.Server{
 		Handler: newLoggedHandler(server),
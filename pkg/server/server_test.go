@@ -0,0 +1,58 @@
+package server
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+)
+
+func mustParseTime(str string) *time.Time {
+	t, err := time.Parse(time.RFC3339, str)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return &t
+}
+
+func withClockTime(t *testing.T, ct *time.Time, cb func(*testing.T)) {
+	orig := clock.Clock
+	clock.Clock = func() *time.Time {
+		return ct
+	}
+
+	defer func() {
+		clock.Clock = orig
+	}()
+
+	cb(t)
+}
+
+type sentMessage struct {
+	To   string
+	Body string
+}
+
+// recordingSender keeps track of messages instead of sending them.
+type recordingSender struct {
+	sync.Mutex
+	sent []sentMessage
+}
+
+func (s *recordingSender) Send(to, body string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.sent = append(s.sent, sentMessage{to, body})
+	return nil
+}
+
+func (s *recordingSender) Sent() []sentMessage {
+	s.Lock()
+	defer s.Unlock()
+
+	return append([]sentMessage(nil), s.sent...)
+}
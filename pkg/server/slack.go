@@ -0,0 +1,65 @@
+package server
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/slack"
+)
+
+// Slack never sends a slash command anywhere near this big.
+const maxSlackCommandSize = 64 * 1024
+
+// slackCommandAnswer answers a slash command like /whatday Tokyo. The answer
+// goes to the whole channel unless something is wrong with the question.
+func (s *Server) slackCommandAnswer(command, text string) slack.Message {
+	answer, public := s.whatDayAnswer(command, text)
+
+	if !public {
+		return slack.Message{ResponseType: slack.ResponseTypeEphemeral, Text: answer}
+	}
+
+	return slack.Message{ResponseType: slack.ResponseTypeInChannel, Text: answer}
+}
+
+// PostSlackCommand handles slash commands, once we're sure that they came
+// from Slack.
+func (s *Server) PostSlackCommand(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSlackCommandSize))
+
+	if err != nil {
+		logger.WithError(err).Error("failed to read slash command")
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "Couldn't read the request body.")
+		return
+	}
+
+	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
+	signature := r.Header.Get("X-Slack-Signature")
+
+	if err := slack.VerifyRequest(s.SlackSigningSecret, timestamp, signature, body, *clock.Clock()); err != nil {
+		logger.WithError(err).Warn("rejected slash command")
+		writeError(w, http.StatusUnauthorized, ErrorCodeUnauthorized, "The request isn't signed by Slack.")
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+
+	if err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "The request body must be a form.")
+		return
+	}
+
+	command := form.Get("command")
+
+	if command == "" {
+		command = "/whatday"
+	}
+
+	writeJSON(w, s.slackCommandAnswer(command, strings.TrimSpace(form.Get("text"))))
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/slack"
+	"github.com/stretchr/testify/assert"
+)
+
+const testWebhookURL = "https://hooks.slack.com/services/T000/B000/XXXX"
+
+// doSlackCommand sends a slash command signed with secret, like Slack would.
+func doSlackCommand(s *Server, secret, text string) *httptest.ResponseRecorder {
+	body := url.Values{"command": {"/whatday"}, "text": {text}}.Encode()
+	timestamp := strconv.FormatInt(clock.Clock().Unix(), 10)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/slack/command", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slack.Sign([]byte(secret), timestamp, []byte(body)))
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestPostSlackCommand(t *testing.T) {
+	s, _ := newTestAPIServer()
+	s.SlackSigningSecret = []byte("slack-sekrit")
+
+	cases := []struct {
+		text         string
+		responseType string
+		answer       string
+	}{
+		{"", slack.ResponseTypeInChannel, "It's Monday, October 19 in UTC."},
+		{"Tokyo", slack.ResponseTypeInChannel, "It's Tuesday, October 20 in Tokyo."},
+		{"  US/Pacific ", slack.ResponseTypeInChannel, "It's Monday, October 19 in Los Angeles."},
+		{"Atlantis", slack.ResponseTypeEphemeral, "Unknown timezone Atlantis. Try a timezone or a city, like /whatday Tokyo."},
+		{"help", slack.ResponseTypeEphemeral, "Say /whatday to find out what day it is, or /whatday and a timezone or city, like /whatday Tokyo."},
+	}
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		for _, c := range cases {
+			w := doSlackCommand(s, "slack-sekrit", c.text)
+			assert.Equal(t, http.StatusOK, w.Code, c.text)
+
+			var msg slack.Message
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &msg))
+			assert.Equal(t, c.responseType, msg.ResponseType, c.text)
+			assert.Equal(t, c.answer, msg.Text, c.text)
+		}
+	})
+}
+
+func TestPostSlackCommandRequiresSignature(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		// Slash commands are off without a secret.
+		w := doSlackCommand(s, "", "Tokyo")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		s.SlackSigningSecret = []byte("slack-sekrit")
+
+		w = doSlackCommand(s, "nope", "Tokyo")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, ErrorCodeUnauthorized, decodeAPIError(w).Code)
+	})
+}
+
+func TestSlackSubscriptionErrors(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	cases := []struct {
+		body string
+		code string
+	}{
+		{`{"channel": "fax", "number": "+14155551234"}`, ErrorCodeInvalidChannel},
+		{`{"channel": "slack"}`, ErrorCodeInvalidWebhookURL},
+		{`{"channel": "slack", "webhook_url": "https://example.com/services/T000/B000/XXXX"}`, ErrorCodeInvalidWebhookURL},
+		{`{"channel": "sms", "webhook_url": "` + testWebhookURL + `"}`, ErrorCodeInvalidPhoneNumber},
+	}
+
+	for _, c := range cases {
+		w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", c.body)
+		assert.Equal(t, http.StatusBadRequest, w.Code, c.body)
+		assert.Equal(t, c.code, decodeAPIError(w).Code, c.body)
+	}
+}
+
+func TestSendersSend(t *testing.T) {
+	sms := &recordingSender{}
+	senders := Senders{models.ChannelSMS: sms}
+
+	assert.NoError(t, senders.Send(models.PhoneNumber{Number: "+14155551234"}, "Today is Monday"))
+	assert.Equal(t, []sentMessage{{"+14155551234", "Today is Monday"}}, sms.Sent())
+
+	assert.Error(t, senders.Send(models.PhoneNumber{Number: "slack:0123456789abcdef", Channel: models.ChannelSlack, Address: testWebhookURL}, "Today is Monday"))
+
+	assert.True(t, IsUndeliverable(&slack.Error{Status: http.StatusNotFound, Reason: "no_service"}))
+	assert.False(t, IsUndeliverable(&slack.Error{Status: http.StatusInternalServerError}))
+	assert.False(t, IsUndeliverable(nil))
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"errors"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+var (
+	errCodeExpired         = errors.New("server: verification code expired")
+	errTooManyAttempts     = errors.New("server: too many verification attempts")
+	errWrongCode           = errors.New("server: wrong verification code")
+	errSubscriptionExpired = errors.New("server: subscription expired")
+	errTooSoon             = errors.New("server: confirmation sent too recently")
+)
+
+// subscribe signs address up on channel from the web and asks the owner to
+// confirm, or asks them to verify the request if we already know about them.
+// A nil schedule is the default one. It returns the subscription as it stands,
+// or as it was requested if the request is waiting on a code, and which way
+// it's being verified.
+func (s *Server) subscribe(channel models.Channel, address, timezone string, schedule *clock.Schedule, verification string) (models.PhoneNumber, string, error) {
+	num := models.SubscriberID(channel, address)
+	timezone, guessed := s.timezoneFor(guessableNumber(channel, address), timezone)
+	now := clock.Clock()
+	expiresAt := now.Add(s.ConfirmationWindow)
+
+	phoneNumber := models.PhoneNumber{
+		Number:          num,
+		Timezone:        timezone,
+		TimezoneGuessed: guessed,
+
+		// Anyone can type a number in to the form, so we won't text it
+		// anything else until the owner confirms.
+		Status:                models.StatusPending,
+		StatusChangedAt:       map[models.Status]time.Time{models.StatusPending: *now},
+		ConfirmationExpiresAt: &expiresAt,
+		Schedule:              schedule,
+	}
+
+	if channel != models.ChannelSMS {
+		phoneNumber.Channel = channel
+		phoneNumber.Address = address
+	}
+
+	if channel == models.ChannelWebhook {
+		phoneNumber.WebhookSecret = models.NewWebhookSecret()
+	}
+
+	err := s.managers.PhoneNumbers().Create(phoneNumber)
+
+	if err == managers.ErrRecordExists {
+		return s.resubscribe(phoneNumber, verification)
+	} else if err != nil {
+		return phoneNumber, verification, err
+	}
+
+	s.recordEvent(num, models.EventSubscribed, models.ActorWeb, map[string]string{
+		"timezone":     phoneNumber.Timezone,
+		"verification": verification,
+	})
+
+	return phoneNumber, verification, s.askToConfirm(phoneNumber, verification, phoneNumber)
+}
+
+// verify checks the code that the owner of num typed in against the one we
+// texted them, and confirms their subscription if it matches.
+func (s *Server) verify(num, code string) (models.PhoneNumber, error) {
+	verification, err := s.managers.Verifications().Get(num)
+
+	if err == managers.ErrRecordNotFound {
+		logger.Warn("no outstanding verification code")
+		return models.PhoneNumber{}, errCodeExpired
+	} else if err != nil {
+		return models.PhoneNumber{}, err
+	}
+
+	// Every guess counts, right or wrong, and it's counted before we look at
+	// it so that lots of guesses at once don't get more than their share.
+	if err := s.managers.Verifications().AddAttempt(num, MaxVerificationAttempts); err == managers.ErrLimitReached {
+		logger.Warn("too many verification attempts")
+		return models.PhoneNumber{}, errTooManyAttempts
+	} else if err == managers.ErrRecordNotFound {
+		logger.Warn("no outstanding verification code")
+		return models.PhoneNumber{}, errCodeExpired
+	} else if err != nil {
+		return models.PhoneNumber{}, err
+	}
+
+	if !verification.Matches(code) {
+		logger.Warn("wrong verification code")
+		return models.PhoneNumber{}, errWrongCode
+	}
+
+	// Codes are good for one use only, so if we can't throw it away we don't
+	// take it either.
+	if err := s.managers.Verifications().Delete(num); err != nil {
+		logger.WithError(err).Error("failed to delete verification code")
+		return models.PhoneNumber{}, err
+	}
+
+	phoneNumber, err := s.managers.PhoneNumbers().Get(num)
+
+	if err == managers.ErrRecordNotFound {
+		logger.Warn("verified phone number has expired")
+		return phoneNumber, errSubscriptionExpired
+	} else if err != nil {
+		return phoneNumber, err
+	}
+
+	if phoneNumber.Status == models.StatusBanned {
+		logger.Warn("banned phone number tried to verify")
+		return phoneNumber, errBanned
+	}
+
+	if err := s.applyRequested(&phoneNumber, verification); err != nil {
+		logger.WithError(err).Error("failed to apply verified request")
+		return phoneNumber, err
+	}
+
+	switch phoneNumber.Status {
+	case models.StatusPending:
+		if err := s.managers.PhoneNumbers().UpdateStatus(&phoneNumber, models.StatusActive, clock.Clock()); err != nil {
+			logger.WithError(err).Error("failed to confirm phone number")
+			return phoneNumber, err
+		}
+
+		s.recordEvent(num, models.EventConfirmed, models.ActorWeb, map[string]string{
+			"verification": VerifyByCode,
+		})
+	case models.StatusStopped, models.StatusBounced:
+		// Whoever got the code owns the address, so it counts as START.
+		if err := s.managers.PhoneNumbers().Resubscribe(&phoneNumber, clock.Clock()); err != nil {
+			logger.WithError(err).Error("failed to update record as sendable")
+			return phoneNumber, err
+		}
+
+		s.recordEvent(num, models.EventResubscribed, models.ActorWeb, nil)
+	default:
+		return phoneNumber, nil
+	}
+
+	for _, message := range welcomeMessages(phoneNumber) {
+		s.Senders.Send(phoneNumber, message)
+	}
+
+	// We'll update this record so we don't send something again later. The
+	// welcome is already out, so failing the request now would only make
+	// them try a code that's gone.
+	if err := s.managers.PhoneNumbers().UpdateSent(&phoneNumber, clock.Clock()); err != nil {
+		logger.WithError(err).Error("failed to record that the welcome was sent")
+	}
+
+	logger.Info("user verified subscription")
+	return phoneNumber, nil
+}
+
+// applyRequested makes the changes that a subscribe request asked for, now
+// that the code sent for it has been verified.
+func (s *Server) applyRequested(phoneNumber *models.PhoneNumber, verification models.Verification) error {
+	var req PatchSubscriptionRequest
+
+	if verification.Timezone != "" {
+		req.Timezone = &verification.Timezone
+	}
+
+	if verification.DeliveryTime != "" {
+		req.DeliveryTime = &verification.DeliveryTime
+	}
+
+	u, rerr := planUpdate(*phoneNumber, req)
+
+	if rerr != nil {
+		return errors.New(rerr.Message)
+	}
+
+	return s.applyUpdate(phoneNumber, u, models.ActorWeb)
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/telegram"
+)
+
+// Updates are a message and a little bit about who sent it.
+const maxTelegramUpdateSize = 64 * 1024
+
+// HandleTelegramUpdate answers a message sent to the bot, which goes through
+// the same keywords and commands as a text message. Commands like /tz Tokyo
+// are just TZ Tokyo, and /start and /stop are the START and STOP keywords.
+func (s *Server) HandleTelegramUpdate(update telegram.Update) {
+	if update.Message == nil || update.Message.Text == "" {
+		return
+	}
+
+	chatID := strconv.FormatInt(update.Message.Chat.ID, 10)
+	reply, err := s.handleMessage(models.ChannelTelegram, chatID, telegram.CommandText(update.Message.Text))
+
+	if err != nil {
+		logger.WithError(err).Error("failed to handle Telegram message")
+		return
+	} else if reply == "" {
+		return
+	}
+
+	to := models.PhoneNumber{Channel: models.ChannelTelegram, Address: chatID}
+
+	if err := s.Senders.Send(to, reply); err != nil {
+		logger.WithError(err).Error("failed to reply on Telegram")
+	}
+}
+
+// PostTelegramUpdate is the bot's webhook, for when Telegram sends us updates
+// instead of us polling for them.
+func (s *Server) PostTelegramUpdate(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+
+	if s.TelegramSecretToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.TelegramSecretToken)) != 1 {
+		logger.Warn("rejected Telegram update")
+		writeError(w, http.StatusUnauthorized, ErrorCodeUnauthorized, "The request isn't from Telegram.")
+		return
+	}
+
+	var update telegram.Update
+
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxTelegramUpdateSize)).Decode(&update); err != nil {
+		writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, "The request body must be JSON.")
+		return
+	}
+
+	s.HandleTelegramUpdate(update)
+	w.WriteHeader(http.StatusOK)
+}
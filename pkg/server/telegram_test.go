@@ -0,0 +1,95 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/telegram"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestTelegramServer() (*Server, *recordingSender) {
+	s, _ := newTestAPIServer()
+	s.TelegramSecretToken = "sekrit"
+
+	sender := &recordingSender{}
+	s.Senders[models.ChannelTelegram] = sender
+
+	return s, sender
+}
+
+func telegramUpdate(chatID int64, text string) telegram.Update {
+	return telegram.Update{
+		UpdateID: 1,
+		Message:  &telegram.Message{MessageID: 1, Chat: telegram.Chat{ID: chatID, Type: "private"}, Text: text},
+	}
+}
+
+func TestHandleTelegramUpdate(t *testing.T) {
+	withClockTime(t, mustParseTime("2026-10-20T12:00:00Z"), func(t *testing.T) {
+		s, sender := newTestTelegramServer()
+		id := models.SubscriberID(models.ChannelTelegram, "42")
+
+		s.HandleTelegramUpdate(telegramUpdate(42, "/start"))
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get(id)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, models.StatusActive, phoneNumber.Status)
+			assert.Equal(t, models.ChannelTelegram, phoneNumber.DeliveryChannel())
+			assert.Equal(t, "42", phoneNumber.DeliveryAddress())
+		}
+
+		s.HandleTelegramUpdate(telegramUpdate(42, "/tz@WhatDayBot Tokyo"))
+
+		phoneNumber, _ = s.managers.PhoneNumbers().Get(id)
+		assert.Equal(t, "Asia/Tokyo", phoneNumber.Timezone)
+
+		s.HandleTelegramUpdate(telegramUpdate(42, "/stop"))
+
+		phoneNumber, _ = s.managers.PhoneNumbers().Get(id)
+		assert.Equal(t, models.StatusStopped, phoneNumber.Status)
+
+		sent := sender.Sent()
+
+		if assert.Len(t, sent, 3) {
+			for _, m := range sent {
+				assert.Equal(t, "42", m.To)
+			}
+
+			assert.Contains(t, sent[0].Body, "Today is Tuesday")
+			assert.Contains(t, sent[1].Body, "Tokyo")
+		}
+	})
+}
+
+func TestPostTelegramUpdate(t *testing.T) {
+	s, sender := newTestTelegramServer()
+
+	doUpdate := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/telegram/webhook", strings.NewReader(`{"update_id": 1, "message": {"message_id": 1, "chat": {"id": 42, "type": "private"}, "text": "/start"}}`))
+
+		if token != "" {
+			req.Header.Set("X-Telegram-Bot-Api-Secret-Token", token)
+		}
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, doUpdate("").Code)
+	assert.Equal(t, http.StatusUnauthorized, doUpdate("nope").Code)
+	assert.Empty(t, sender.Sent())
+
+	assert.Equal(t, http.StatusOK, doUpdate("sekrit").Code)
+	assert.Len(t, sender.Sent(), 1)
+
+	// No secret means no webhook.
+	s.TelegramSecretToken = ""
+	assert.Equal(t, http.StatusUnauthorized, doUpdate("").Code)
+}
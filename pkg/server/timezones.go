@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+)
+
+type TimezoneResponse struct {
+	// The canonical IANA name, like "Europe/Berlin".
+	Name string `json:"name"`
+
+	// A name to show people, like "Berlin (UTC+02:00)".
+	DisplayName string `json:"display_name"`
+
+	// The offset from UTC right now, like "+02:00".
+	UTCOffset string `json:"utc_offset"`
+
+	// Indicates that daylight saving time is in effect right now.
+	DST bool `json:"dst"`
+
+	Cities []string `json:"cities"`
+
+	Abbreviations []string `json:"abbreviations"`
+
+	// Old names for the zone, like "US/Pacific".
+	Aliases []string `json:"aliases"`
+}
+
+type GetTimezonesResponse struct {
+	Timezones []TimezoneResponse `json:"timezones"`
+}
+
+func emptyIfNil(strs []string) []string {
+	if strs == nil {
+		return []string{}
+	}
+
+	return strs
+}
+
+func newTimezoneResponse(zone clock.Zone) TimezoneResponse {
+	now := clock.Clock().In(clock.MustLoadLocation(zone.Name))
+	offset := now.Format("-07:00")
+
+	return TimezoneResponse{
+		Name:          zone.Name,
+		DisplayName:   zone.DisplayName() + " (UTC" + offset + ")",
+		UTCOffset:     offset,
+		DST:           clock.IsDST(now),
+		Cities:        emptyIfNil(zone.Cities),
+		Abbreviations: emptyIfNil(zone.Abbreviations),
+		Aliases:       emptyIfNil(zone.Aliases),
+	}
+}
+
+func (s *Server) GetTimezones(w http.ResponseWriter, r *http.Request) {
+	resp := GetTimezonesResponse{Timezones: []TimezoneResponse{}}
+
+	for _, zone := range clock.SearchZones(r.URL.Query().Get("q")) {
+		resp.Timezones = append(resp.Timezones, newTimezoneResponse(zone))
+	}
+
+	writeJSON(w, resp)
+}
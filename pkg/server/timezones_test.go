@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTimezones(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-07-01T12:00:00Z"), func(t *testing.T) {
+		w := doAPIRequest(s, http.MethodGet, "/api/timezones?q=berl", "", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp GetTimezonesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		if assert.Len(t, resp.Timezones, 1) {
+			berlin := resp.Timezones[0]
+			assert.Equal(t, "Europe/Berlin", berlin.Name)
+			assert.Equal(t, "Berlin (UTC+02:00)", berlin.DisplayName)
+			assert.Equal(t, "+02:00", berlin.UTCOffset)
+			assert.True(t, berlin.DST)
+			assert.Contains(t, berlin.Cities, "Munich")
+			assert.Equal(t, []string{"CET", "CEST"}, berlin.Abbreviations)
+			assert.Equal(t, []string{}, berlin.Aliases)
+		}
+
+		w = doAPIRequest(s, http.MethodGet, "/api/timezones?q=US/Pacific", "", "")
+
+		resp = GetTimezonesResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		if assert.Len(t, resp.Timezones, 1) {
+			assert.Equal(t, "America/Los_Angeles", resp.Timezones[0].Name)
+			assert.Equal(t, "-07:00", resp.Timezones[0].UTCOffset)
+		}
+
+		w = doAPIRequest(s, http.MethodGet, "/api/timezones", "", "")
+
+		resp = GetTimezonesResponse{}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.True(t, len(resp.Timezones) > 50)
+
+		w = doAPIRequest(s, http.MethodGet, "/api/timezones?q=xyzzy", "", "")
+		assert.JSONEq(t, `{"timezones": []}`, w.Body.String())
+	})
+}
+
+func TestSubscribeCanonicalizesTimezone(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-07-01T12:00:00Z"), func(t *testing.T) {
+		w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"number": "+14155551234", "timezone": "US/Pacific"}`)
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var created PostSubscriptionResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+		assert.Equal(t, "America/Los_Angeles", created.Subscription.Timezone)
+	})
+}
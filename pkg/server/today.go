@@ -0,0 +1,200 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+)
+
+type TodayResponse struct {
+	// The timezone name that the day is for.
+	Timezone string `json:"timezone"`
+
+	// The name of the day, like "Monday".
+	Day string `json:"day"`
+
+	// The ISO 8601 date, like "2026-10-19".
+	Date string `json:"date"`
+
+	// The ISO 8601 week, like "2026-W43".
+	ISOWeek string `json:"iso_week"`
+
+	DayOfYear int `json:"day_of_year"`
+
+	DaysLeftInYear int `json:"days_left_in_year"`
+
+	// The offset from UTC right now, like "+09:00".
+	UTCOffset string `json:"utc_offset"`
+
+	// When this response stops being true, which is when the day ends or,
+	// if the clocks change first, when they do.
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newTodayResponse(now time.Time) TodayResponse {
+	year, week := now.ISOWeek()
+	lastDay := time.Date(now.Year(), time.December, 31, 0, 0, 0, 0, now.Location())
+	expiresAt := clock.NextMidnight(now)
+
+	// The offset is worked out from now, so it's only right until the next
+	// time that the clocks change.
+	if transitions := clock.Transitions(now.Location(), now, expiresAt); len(transitions) > 0 {
+		expiresAt = transitions[0].At
+	}
+
+	return TodayResponse{
+		Timezone:       now.Location().String(),
+		Day:            now.Weekday().String(),
+		Date:           now.Format("2006-01-02"),
+		ISOWeek:        fmt.Sprintf("%04d-W%02d", year, week),
+		DayOfYear:      now.YearDay(),
+		DaysLeftInYear: lastDay.YearDay() - now.YearDay(),
+		UTCOffset:      now.Format("-07:00"),
+		ExpiresAt:      expiresAt,
+	}
+}
+
+// Text is the plain text version of the response, which has the answer on
+// the first line so that `curl | head -1` does the right thing.
+func (t TodayResponse) Text() string {
+	var out strings.Builder
+
+	fmt.Fprintln(&out, t.Day)
+	fmt.Fprintln(&out, "date:", t.Date)
+	fmt.Fprintln(&out, "iso_week:", t.ISOWeek)
+	fmt.Fprintln(&out, "day_of_year:", t.DayOfYear)
+	fmt.Fprintln(&out, "days_left_in_year:", t.DaysLeftInYear)
+	fmt.Fprintln(&out, "utc_offset:", t.UTCOffset)
+	fmt.Fprintln(&out, "timezone:", t.Timezone)
+
+	return out.String()
+}
+
+// acceptQuality returns how much the Accept header wants mediaType, and how
+// specifically it asked for it, so that "application/json, */*" is JSON.
+func acceptQuality(accept, mediaType string) (float64, int) {
+	quality, specificity := 0.0, -1
+
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+
+		var s int
+
+		switch {
+		case mediaRange == mediaType:
+			s = 2
+		case mediaRange == strings.SplitN(mediaType, "/", 2)[0]+"/*":
+			s = 1
+		case mediaRange == "*/*":
+			s = 0
+		default:
+			continue
+		}
+
+		if s < specificity {
+			continue
+		}
+
+		q := 1.0
+
+		for _, param := range params[1:] {
+			if kv := strings.SplitN(strings.TrimSpace(param), "=", 2); len(kv) == 2 && strings.ToLower(kv[0]) == "q" {
+				if v, err := strconv.ParseFloat(kv[1], 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		quality, specificity = q, s
+	}
+
+	return quality, specificity
+}
+
+// wantsJSON indicates that the request prefers JSON to plain text. Anything
+// that doesn't say, like curl, gets plain text.
+func wantsJSON(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+
+	if accept == "" {
+		return false
+	}
+
+	jsonQ, jsonS := acceptQuality(accept, "application/json")
+	textQ, textS := acceptQuality(accept, "text/plain")
+
+	if jsonQ != textQ {
+		return jsonQ > textQ
+	}
+
+	return jsonQ > 0 && jsonS > textS
+}
+
+// matchesETag indicates that an If-None-Match header includes etag.
+func matchesETag(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) GetToday(w http.ResponseWriter, r *http.Request) {
+	asJSON := wantsJSON(r)
+	tz := r.URL.Query().Get("tz")
+
+	if tz == "" {
+		tz = s.DefaultTimeZone
+	}
+
+	timezone, rerr := resolveTimezone(tz)
+
+	if rerr != nil {
+		if asJSON {
+			writeRequestError(w, rerr)
+		} else {
+			http.Error(w, rerr.Message, rerr.Status)
+		}
+
+		return
+	}
+
+	today := newTodayResponse(clock.Clock().In(clock.MustLoadLocation(timezone)))
+
+	format := "text"
+
+	if asJSON {
+		format = "json"
+	}
+
+	// The offset is in the tag because it changes part way through the day
+	// when the clocks do.
+	etag := fmt.Sprintf(`"%s/%s/%s/%s"`, today.Timezone, today.Date, today.UTCOffset, format)
+	maxAge := today.ExpiresAt.Sub(*clock.Clock()) / time.Second
+
+	w.Header().Set("Vary", "Accept")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	w.Header().Set("Expires", today.ExpiresAt.UTC().Format(http.TimeFormat))
+
+	if matchesETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if asJSON {
+		writeJSON(w, today)
+	} else {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(today.Text()))
+	}
+}
@@ -0,0 +1,146 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func doTodayRequest(s *Server, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestGetTodayJSON(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		w := doTodayRequest(s, "/api/today?tz=Tokyo", map[string]string{"Accept": "application/json"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var today TodayResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &today))
+		assert.Equal(t, "Asia/Tokyo", today.Timezone)
+		assert.Equal(t, "Tuesday", today.Day)
+		assert.Equal(t, "2026-10-20", today.Date)
+		assert.Equal(t, "2026-W43", today.ISOWeek)
+		assert.Equal(t, 293, today.DayOfYear)
+		assert.Equal(t, 72, today.DaysLeftInYear)
+		assert.Equal(t, "+09:00", today.UTCOffset)
+		assert.True(t, mustParseTime("2026-10-20T15:00:00Z").Equal(today.ExpiresAt))
+
+		// Tokyo's day ends 22 hours from now.
+		assert.Equal(t, "public, max-age=79200", w.Header().Get("Cache-Control"))
+		assert.Equal(t, "Tue, 20 Oct 2026 15:00:00 GMT", w.Header().Get("Expires"))
+		assert.Equal(t, "Accept", w.Header().Get("Vary"))
+	})
+}
+
+func TestGetTodayText(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-12-31T17:00:00Z"), func(t *testing.T) {
+		w := doTodayRequest(s, "/api/today", map[string]string{"Accept": "*/*"})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "Thursday\ndate: 2026-12-31\niso_week: 2026-W53\nday_of_year: 365\ndays_left_in_year: 0\nutc_offset: +00:00\ntimezone: UTC\n", w.Body.String())
+		assert.Equal(t, "public, max-age=25200", w.Header().Get("Cache-Control"))
+	})
+}
+
+func TestGetTodayClocksChange(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	// New York's clocks go back at 2am, so the offset is only right until then.
+	withClockTime(t, mustParseTime("2026-11-01T04:30:00Z"), func(t *testing.T) {
+		w := doTodayRequest(s, "/api/today?tz=America/New_York", map[string]string{"Accept": "application/json"})
+
+		var today TodayResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &today))
+		assert.Equal(t, "-04:00", today.UTCOffset)
+		assert.True(t, mustParseTime("2026-11-01T06:00:00Z").Equal(today.ExpiresAt))
+		assert.Equal(t, "public, max-age=5400", w.Header().Get("Cache-Control"))
+	})
+
+	withClockTime(t, mustParseTime("2026-11-01T06:30:00Z"), func(t *testing.T) {
+		w := doTodayRequest(s, "/api/today?tz=America/New_York", map[string]string{"Accept": "application/json"})
+
+		var today TodayResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &today))
+		assert.Equal(t, "Sunday", today.Day)
+		assert.Equal(t, "-05:00", today.UTCOffset)
+		assert.True(t, mustParseTime("2026-11-02T05:00:00Z").Equal(today.ExpiresAt))
+	})
+}
+
+func TestGetTodayNotModified(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		w := doTodayRequest(s, "/api/today?tz=Asia/Tokyo", nil)
+		etag := w.Header().Get("ETag")
+		assert.NotEmpty(t, etag)
+
+		w = doTodayRequest(s, "/api/today?tz=Asia/Tokyo", map[string]string{"If-None-Match": etag})
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+
+		// The same day as JSON is a different representation.
+		w = doTodayRequest(s, "/api/today?tz=Asia/Tokyo", map[string]string{"If-None-Match": etag, "Accept": "application/json"})
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	withClockTime(t, mustParseTime("2026-10-20T17:00:00Z"), func(t *testing.T) {
+		w := doTodayRequest(s, "/api/today?tz=Asia/Tokyo", map[string]string{"If-None-Match": `"Asia/Tokyo/2026-10-20/+09:00/text"`})
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Wednesday")
+	})
+}
+
+func TestGetTodayBadTimezone(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	w := doTodayRequest(s, "/api/today?tz=Nowhere", map[string]string{"Accept": "application/json"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrorCodeInvalidTimezone, decodeAPIError(w).Code)
+
+	w = doTodayRequest(s, "/api/today?tz=Nowhere", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "Unknown timezone Nowhere.\n", w.Body.String())
+}
+
+func TestWantsJSON(t *testing.T) {
+	tests := []struct {
+		accept   string
+		expected bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", true},
+		{"application/json, */*", true},
+		{"text/plain, application/json", false},
+		{"text/plain;q=0.5, application/json", true},
+		{"application/json;q=0.5, text/*", false},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", false},
+		{"application/*", true},
+		{"application/json;q=0", false},
+	}
+
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/today", nil)
+		req.Header.Set("Accept", test.accept)
+		assert.Equal(t, test.expected, wantsJSON(req), test.accept)
+	}
+}
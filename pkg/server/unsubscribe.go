@@ -0,0 +1,155 @@
+package server
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/bradhe/what-day-is-it/pkg/tokens"
+)
+
+// UnsubscribeLinkLifetime is how long the unsubscribe link in an email is good
+// for. People dig up old email to unsubscribe, so it's a long time.
+var UnsubscribeLinkLifetime = 365 * 24 * time.Hour
+
+const unsubscribeLinkPurpose = "unsubscribe-link"
+
+// UnsubscribeURL returns where the owner of an email address goes to stop
+// getting email, or nothing if we can't sign tokens. Unlike MANAGE links,
+// it works more than once, since mail clients and people both like to click
+// things twice.
+func (s *Server) UnsubscribeURL(address string) string {
+	signer := tokens.NewSigner(s.SigningSecret)
+
+	if !signer.IsConfigured() {
+		return ""
+	}
+
+	token := signer.Sign(tokens.Claims{
+		Purpose:   unsubscribeLinkPurpose,
+		Subject:   models.SubscriberID(models.ChannelEmail, address),
+		ExpiresAt: clock.Clock().Add(UnsubscribeLinkLifetime),
+	})
+
+	return s.BaseURL + "/unsubscribe?token=" + url.QueryEscape(token)
+}
+
+// unsubscribePage is everything the unsubscribe page shows.
+type unsubscribePage struct {
+	Address string
+
+	// Submitting the form unsubscribes with this. Empty once there's nothing
+	// left to do.
+	Token string
+
+	Message string
+	Error   string
+}
+
+var unsubscribeTemplate = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>What Day Is It? Unsubscribe</title>
+</head>
+<body>
+<h1>What Day Is It?</h1>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+{{if .Message}}<p class="message">{{.Message}}</p>{{end}}
+{{if .Token}}
+<form method="POST" action="/unsubscribe">
+<input type="hidden" name="token" value="{{.Token}}">
+<p>Stop emailing {{.Address}}?</p>
+<p><button type="submit">Unsubscribe</button></p>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+func renderUnsubscribePage(w http.ResponseWriter, status int, page unsubscribePage) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+
+	w.WriteHeader(status)
+
+	if err := unsubscribeTemplate.Execute(w, page); err != nil {
+		logger.WithError(err).Error("failed to render unsubscribe page")
+	}
+}
+
+// checkUnsubscribeToken looks up the subscriber that token is for. The error
+// page has been rendered if it returns false.
+func (s *Server) checkUnsubscribeToken(w http.ResponseWriter, token string) (models.PhoneNumber, bool) {
+	claims, err := tokens.NewSigner(s.SigningSecret).Verify(token, unsubscribeLinkPurpose, *clock.Clock())
+
+	switch err {
+	case nil:
+		// Handled below.
+	case tokens.ErrExpired:
+		renderUnsubscribePage(w, http.StatusGone, unsubscribePage{Error: "This link has expired. Use the one in a newer email."})
+		return models.PhoneNumber{}, false
+	default:
+		renderUnsubscribePage(w, http.StatusForbidden, unsubscribePage{Error: "This link isn't valid. Use the one in a newer email."})
+		return models.PhoneNumber{}, false
+	}
+
+	phoneNumber, err := s.managers.PhoneNumbers().Get(claims.Subject)
+
+	if err == managers.ErrRecordNotFound {
+		renderUnsubscribePage(w, http.StatusNotFound, unsubscribePage{Error: "There's no subscription for this address anymore."})
+		return phoneNumber, false
+	} else if err != nil {
+		logger.WithError(err).Error("failed to find phone number")
+		renderUnsubscribePage(w, http.StatusInternalServerError, unsubscribePage{Error: "Something went wrong. Try again in a bit."})
+		return phoneNumber, false
+	}
+
+	return phoneNumber, true
+}
+
+// GetUnsubscribe asks before unsubscribing, since things like link scanners
+// follow links in email without anybody clicking them.
+func (s *Server) GetUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	phoneNumber, ok := s.checkUnsubscribeToken(w, token)
+
+	if !ok {
+		return
+	}
+
+	if phoneNumber.Status == models.StatusStopped {
+		renderUnsubscribePage(w, http.StatusOK, unsubscribePage{Message: "You're already unsubscribed."})
+		return
+	}
+
+	renderUnsubscribePage(w, http.StatusOK, unsubscribePage{Address: phoneNumber.DeliveryAddress(), Token: token})
+}
+
+// PostUnsubscribe unsubscribes from the page, or straight from the mail client
+// with RFC 8058 one-click unsubscribe, which posts to the link in the
+// List-Unsubscribe header.
+func (s *Server) PostUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	// The form has the token in the body and one-click has it in the URL.
+	phoneNumber, ok := s.checkUnsubscribeToken(w, r.FormValue("token"))
+
+	if !ok {
+		return
+	}
+
+	switch err := s.unsubscribe(&phoneNumber, models.ActorSubscriber); err {
+	case nil:
+		renderUnsubscribePage(w, http.StatusOK, unsubscribePage{Message: "You're unsubscribed. Sorry to see you go!"})
+	case errBanned:
+		renderUnsubscribePage(w, http.StatusConflict, unsubscribePage{Error: bannedMessage})
+	default:
+		renderUnsubscribePage(w, http.StatusInternalServerError, unsubscribePage{Error: "Something went wrong. Try again in a bit."})
+	}
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEmailSubscriber(t *testing.T, s *Server, address string) {
+	err := s.managers.PhoneNumbers().Create(models.PhoneNumber{
+		Number:   models.SubscriberID(models.ChannelEmail, address),
+		Channel:  models.ChannelEmail,
+		Address:  address,
+		Timezone: "UTC",
+		Status:   models.StatusActive,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func doUnsubscribeRequest(s *Server, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+
+	if body != "" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+// unsubscribePath is the path and query of the link in email.
+func unsubscribePath(s *Server, address string) string {
+	return strings.TrimPrefix(s.UnsubscribeURL(address), s.BaseURL)
+}
+
+func TestUnsubscribe(t *testing.T) {
+	s, _ := newTestAPIServer()
+	newTestEmailSubscriber(t, s, "someone@example.com")
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		link := unsubscribePath(s, "someone@example.com")
+		assert.True(t, strings.HasPrefix(link, "/unsubscribe?token="), link)
+
+		// Looking at the link doesn't unsubscribe anybody.
+		w := doUnsubscribeRequest(s, http.MethodGet, link, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Stop emailing someone@example.com?")
+		assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+		phoneNumber, _ := s.managers.PhoneNumbers().Get("someone@example.com")
+		assert.Equal(t, models.StatusActive, phoneNumber.Status)
+
+		// This is what mail clients do for one-click unsubscribe.
+		w = doUnsubscribeRequest(s, http.MethodPost, link, "List-Unsubscribe=One-Click")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "unsubscribed. Sorry to see you go!")
+
+		phoneNumber, _ = s.managers.PhoneNumbers().Get("someone@example.com")
+		assert.Equal(t, models.StatusStopped, phoneNumber.Status)
+
+		// And again from the page, which is fine.
+		token, _ := url.ParseQuery(strings.TrimPrefix(link, "/unsubscribe?"))
+		w = doUnsubscribeRequest(s, http.MethodPost, "/unsubscribe", "token="+url.QueryEscape(token.Get("token")))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		w = doUnsubscribeRequest(s, http.MethodGet, link, "")
+		assert.Contains(t, w.Body.String(), "already unsubscribed.")
+	})
+}
+
+func TestUnsubscribeErrors(t *testing.T) {
+	s, _ := newTestAPIServer()
+	newTestEmailSubscriber(t, s, "someone@example.com")
+
+	var link, gone string
+
+	withClockTime(t, mustParseTime("2025-10-19T17:00:00Z"), func(t *testing.T) {
+		link = unsubscribePath(s, "someone@example.com")
+		gone = unsubscribePath(s, "nobody@example.com")
+	})
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		assert.Equal(t, http.StatusGone, doUnsubscribeRequest(s, http.MethodPost, link, "").Code)
+		assert.Equal(t, http.StatusForbidden, doUnsubscribeRequest(s, http.MethodPost, "/unsubscribe?token=nope", "").Code)
+		assert.Equal(t, http.StatusForbidden, doUnsubscribeRequest(s, http.MethodGet, "/unsubscribe", "").Code)
+	})
+
+	withClockTime(t, mustParseTime("2025-10-19T18:00:00Z"), func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, doUnsubscribeRequest(s, http.MethodPost, gone, "").Code)
+	})
+
+	phoneNumber, _ := s.managers.PhoneNumbers().Get("someone@example.com")
+	assert.Equal(t, models.StatusActive, phoneNumber.Status)
+
+	// No links without a way to sign them.
+	s.SigningSecret = nil
+	assert.Empty(t, s.UnsubscribeURL("someone@example.com"))
+	assert.Equal(t, 365*24*time.Hour, UnsubscribeLinkLifetime)
+}
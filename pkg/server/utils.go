@@ -3,10 +3,11 @@ package server
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/bradhe/stopwatch"
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
 )
 
 func newLoggedHandler(h http.Handler) http.Handler {
@@ -25,10 +26,6 @@ func newLoggedHandler(h http.Handler) http.Handler {
 	})
 }
 
-func isStopMessage(str string) bool {
-	return strings.ToLower(strings.TrimSpace(str)) == "stop"
-}
-
 func Dump(obj interface{}) []byte {
 	if buf, err := json.Marshal(obj); err != nil {
 		panic(err)
@@ -37,16 +34,57 @@ func Dump(obj interface{}) []byte {
 	}
 }
 
-func (s *Server) timezoneOrDefault(str string) string {
-	if str == "" {
-		return s.DefaultTimeZone
+// guessableNumber returns what to guess the timezone of the subscriber at
+// address on channel from, which is their phone number if they have one.
+func guessableNumber(channel models.Channel, address string) string {
+	switch channel {
+	case models.ChannelWhatsApp, models.ChannelVoice:
+		return models.CleanPhoneNumber(address)
 	}
 
-	// Let's try to load this timezone. If it fails we'll just use the default timezone.
-	if _, err := time.LoadLocation(str); err != nil {
-		logger.WithError(err).WithField("requested_timezone", str).WithField("default_timezone", s.DefaultTimeZone).Warn("failed to load timezone")
-		return s.DefaultTimeZone
-	} else {
-		return str
+	return models.SubscriberID(channel, address)
+}
+
+// timezoneFor picks the timezone for a new subscription to number. If the user
+// didn't ask for a usable timezone we guess one from the phone number, falling
+// back to the default timezone, and report that it was a guess.
+func (s *Server) timezoneFor(number, str string) (string, bool) {
+	if str != "" {
+		// Let's try to find this timezone, which also turns old names like
+		// US/Pacific in to canonical ones. If it fails we'll have to guess.
+		if candidates := clock.LookupPlace(str); len(candidates) != 1 {
+			logger.WithField("requested_timezone", str).WithField("candidates", candidates).Warn("failed to find timezone")
+		} else {
+			return candidates[0], false
+		}
 	}
+
+	if guess, ok := clock.GuessTimezone(number); ok {
+		return guess, true
+	}
+
+	logger.WithField("default_timezone", s.DefaultTimeZone).Warn("failed to guess timezone")
+	return s.DefaultTimeZone, true
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil || t.IsZero() || t.Unix() == 0 {
+		return "never"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func (s *Server) isAdmin(number string) bool {
+	// Only phone numbers can be admins. IDs for other channels are left alone
+	// so they can't be mistaken for one.
+	number = models.CleanSubscriberID(number)
+
+	for _, admin := range s.AdminNumbers {
+		if models.CleanPhoneNumber(admin) == number {
+			return true
+		}
+	}
+
+	return false
 }
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+// VerificationCodeLifetime is how long a code we text out stays valid.
+var VerificationCodeLifetime = 10 * time.Minute
+
+// ConfirmationResendInterval is how long a number has to wait between
+// confirmations, however many times someone fills out the form for it.
+var ConfirmationResendInterval = time.Minute
+
+// MaxVerificationAttempts is how many wrong guesses we'll put up with before
+// a code is no good anymore.
+var MaxVerificationAttempts = 5
+
+const verificationCodeMessage = `Your What Day Is It code is %s. It expires in %d minutes. If you didn't ask for it, just ignore this.`
+
+// askToConfirm asks the owner of number to confirm their subscription, either
+// by replying YES or with a one-time code depending on verification. A code
+// also applies whatever requested asked for once it's verified.
+func (s *Server) askToConfirm(phoneNumber models.PhoneNumber, verification string, requested models.PhoneNumber) error {
+	until := clock.Clock().Add(ConfirmationResendInterval)
+
+	if err := s.managers.Nonces().Use("confirm:"+phoneNumber.Number, &until); err == managers.ErrRecordExists {
+		logger.Warn("confirmation sent too recently")
+		return errTooSoon
+	} else if err != nil {
+		logger.WithError(err).Error("failed to check when we last asked to confirm")
+		return err
+	}
+
+	if verification != VerifyByCode {
+		return s.Senders.Send(phoneNumber, confirmationRequestMessage)
+	}
+
+	now := clock.Clock()
+	expiresAt := now.Add(VerificationCodeLifetime)
+
+	v, code, err := models.NewVerification(phoneNumber.Number, &expiresAt)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to generate verification code")
+		return err
+	}
+
+	// Asking for another code doesn't get anyone more guesses. If the last
+	// code has been guessed at, the new one takes over its guesses and runs
+	// out when it would have.
+	if last, err := s.managers.Verifications().Get(phoneNumber.Number); err == nil {
+		if last.Attempts > 0 && !last.IsExpired(now) {
+			v.Attempts = last.Attempts
+			v.ExpiresAt = last.ExpiresAt
+		}
+	} else if err != managers.ErrRecordNotFound {
+		logger.WithError(err).Error("failed to look up last verification code")
+		return err
+	}
+
+	// A guess isn't worth changing anything for.
+	if !requested.TimezoneGuessed {
+		v.Timezone = requested.Timezone
+	}
+
+	if requested.Schedule != nil {
+		v.DeliveryTime = clock.FormatTimeOfDay(requested.Schedule.Minute)
+	}
+
+	// This replaces any code we sent before.
+	if err := s.managers.Verifications().Put(v); err != nil {
+		logger.WithError(err).Error("failed to save verification code")
+		return err
+	}
+
+	// Read out one digit at a time instead of as one big number.
+	if phoneNumber.DeliveryChannel() == models.ChannelVoice {
+		code = strings.Join(strings.Split(code, ""), " ")
+	}
+
+	minutes := int(math.Ceil(v.ExpiresAt.Sub(*now).Minutes()))
+
+	return s.Senders.Send(phoneNumber, fmt.Sprintf(verificationCodeMessage, code, minutes))
+}
+
+type PostSubscribeVerifyRequest struct {
+	// The phone number that the code was sent to.
+	Number string `json:"number"`
+
+	// The code that we texted to the number.
+	Code string `json:"code"`
+}
+
+// PostSubscribeVerify is the original verification endpoint. It's kept around
+// for compatibility; new clients should use PostSubscriptionVerify.
+func (s *Server) PostSubscribeVerify(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var req PostSubscribeVerifyRequest
+	var resp PostSubscribeResponse
+
+	logger.Info("handling subscribe verification request")
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.WithError(err).Error("failed to decode request body")
+		w.WriteHeader(http.StatusBadRequest)
+
+		resp.Subscribed = false
+		resp.Error = "Failed to read the verification request. Did you send it as JSON?"
+		w.Write(Dump(resp))
+
+		return
+	}
+
+	num := models.CleanPhoneNumber(req.Number)
+	resp.Number = num
+
+	phoneNumber, err := s.verify(num, req.Code)
+
+	switch err {
+	case nil:
+		resp.Timezone = phoneNumber.Timezone
+		resp.TimezoneGuessed = phoneNumber.TimezoneGuessed
+		resp.Status = phoneNumber.Status
+		resp.Subscribed = phoneNumber.IsSendable()
+		resp.ManageToken = s.manageToken(num)
+	case errCodeExpired:
+		w.WriteHeader(http.StatusGone)
+		resp.Error = "That code has expired. Sign up again to get a new one."
+	case errTooManyAttempts:
+		w.WriteHeader(http.StatusTooManyRequests)
+		resp.VerificationRequired = true
+		resp.Error = "Too many wrong codes. Sign up again in a few minutes to get a new one."
+	case errWrongCode:
+		w.WriteHeader(http.StatusPreconditionFailed)
+		resp.VerificationRequired = true
+		resp.Error = "That code isn't right."
+	case errSubscriptionExpired:
+		w.WriteHeader(http.StatusGone)
+		resp.Error = "Your subscription expired before it was verified. Sign up again."
+	case errBanned:
+		w.WriteHeader(http.StatusConflict)
+		resp.Error = "This number can't be subscribed."
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+		resp.Error = "An internal error occured."
+	}
+
+	w.Write(Dump(resp))
+}
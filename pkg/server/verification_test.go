@@ -0,0 +1,205 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var codeexp = regexp.MustCompile(`code is ([0-9]{6})`)
+
+// Calls read the code out a digit at a time.
+var spokenCodeexp = regexp.MustCompile(`code is ([0-9](?: [0-9]){5})\.`)
+
+// sentCode finds the code in a message that we sent, however it was sent.
+func sentCode(body string) string {
+	if m := spokenCodeexp.FindStringSubmatch(body); m != nil {
+		return strings.Replace(m[1], " ", "", -1)
+	} else if m := codeexp.FindStringSubmatch(body); m != nil {
+		return m[1]
+	}
+
+	return ""
+}
+
+// wrongCode returns a code that isn't otp.
+func wrongCode(otp string) string {
+	if otp == "000000" {
+		return "111111"
+	}
+
+	return "000000"
+}
+
+func postSubscribeVerify(s *Server, body string) (int, PostSubscribeResponse) {
+	w := httptest.NewRecorder()
+	s.PostSubscribeVerify(w, httptest.NewRequest(http.MethodPost, "/api/subscribe/verify", strings.NewReader(body)))
+
+	var resp PostSubscribeResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+
+	return w.Code, resp
+}
+
+func subscribeWithCode(t *testing.T, s *Server, sender *recordingSender) string {
+	code, resp := postSubscribe(s, `{"number": "+14155551234", "timezone": "America/Los_Angeles", "verification": "code"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, resp.Subscribed)
+	assert.True(t, resp.VerificationRequired)
+	assert.False(t, resp.ConfirmationRequired)
+
+	sent := sender.Sent()
+	matches := codeexp.FindStringSubmatch(sent[len(sent)-1].Body)
+	assert.Len(t, matches, 2)
+
+	return matches[1]
+}
+
+func TestPostSubscribeVerify(t *testing.T) {
+	sender := &recordingSender{}
+	s := &Server{DefaultTimeZone: "UTC", ConfirmationWindow: time.Hour, managers: memory.New(), Senders: Senders{models.ChannelSMS: sender}}
+
+	otp := subscribeWithCode(t, s, sender)
+
+	code, resp := postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+otp+`"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, resp.Subscribed)
+	assert.Equal(t, "America/Los_Angeles", resp.Timezone)
+
+	phoneNumber, err := s.managers.PhoneNumbers().Get("+14155551234")
+	assert.NoError(t, err)
+	assert.True(t, phoneNumber.IsSendable())
+	assert.False(t, phoneNumber.IsAwaitingConfirmation())
+	assert.True(t, strings.HasPrefix(sender.Sent()[1].Body, "Yo!"))
+
+	// The code only works once.
+	code, _ = postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+otp+`"}`)
+	assert.Equal(t, http.StatusGone, code)
+}
+
+func TestPostSubscribeVerifyLimitsAttempts(t *testing.T) {
+	sender := &recordingSender{}
+	s := &Server{DefaultTimeZone: "UTC", ConfirmationWindow: time.Hour, managers: memory.New(), Senders: Senders{models.ChannelSMS: sender}}
+
+	otp := subscribeWithCode(t, s, sender)
+
+	for i := 0; i < MaxVerificationAttempts; i++ {
+		code, resp := postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+wrongCode(otp)+`"}`)
+		assert.Equal(t, http.StatusPreconditionFailed, code)
+		assert.True(t, resp.VerificationRequired)
+	}
+
+	// Even the right code is no good now.
+	code, _ := postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+otp+`"}`)
+	assert.Equal(t, http.StatusTooManyRequests, code)
+
+	phoneNumber, _ := s.managers.PhoneNumbers().Get("+14155551234")
+	assert.False(t, phoneNumber.IsSendable())
+
+	// Signing up again doesn't help until the old code would have expired.
+	expiresAt := clock.Clock().Add(VerificationCodeLifetime)
+	later := clock.Clock().Add(ConfirmationResendInterval)
+
+	withClockTime(t, &later, func(t *testing.T) {
+		otp = subscribeWithCode(t, s, sender)
+		code, _ = postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+otp+`"}`)
+		assert.Equal(t, http.StatusTooManyRequests, code)
+	})
+
+	withClockTime(t, &expiresAt, func(t *testing.T) {
+		otp = subscribeWithCode(t, s, sender)
+		code, _ = postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+otp+`"}`)
+		assert.Equal(t, http.StatusOK, code)
+	})
+}
+
+func TestPostSubscribeVerifyKeepsAttemptsAcrossCodes(t *testing.T) {
+	sender := &recordingSender{}
+	s := &Server{DefaultTimeZone: "UTC", ConfirmationWindow: time.Hour, managers: memory.New(), Senders: Senders{models.ChannelSMS: sender}}
+
+	now := mustParseTime("2026-10-19T17:00:00Z")
+	later := now.Add(2 * ConfirmationResendInterval)
+
+	var otp string
+
+	withClockTime(t, now, func(t *testing.T) {
+		otp = subscribeWithCode(t, s, sender)
+		assert.Contains(t, sender.Sent()[0].Body, "It expires in 10 minutes.")
+
+		for i := 0; i < MaxVerificationAttempts-1; i++ {
+			code, _ := postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+wrongCode(otp)+`"}`)
+			assert.Equal(t, http.StatusPreconditionFailed, code)
+		}
+	})
+
+	withClockTime(t, &later, func(t *testing.T) {
+		otp = subscribeWithCode(t, s, sender)
+
+		// The new code runs out when the old one would have.
+		assert.Contains(t, sender.Sent()[1].Body, "It expires in 8 minutes.")
+
+		code, _ := postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+wrongCode(otp)+`"}`)
+		assert.Equal(t, http.StatusPreconditionFailed, code)
+
+		code, _ = postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+otp+`"}`)
+		assert.Equal(t, http.StatusTooManyRequests, code)
+	})
+}
+
+func TestPostSubscribeVerifyLimitsAttemptsAtOnce(t *testing.T) {
+	sender := &recordingSender{}
+	s := &Server{DefaultTimeZone: "UTC", ConfirmationWindow: time.Hour, managers: memory.New(), Senders: Senders{models.ChannelSMS: sender}}
+
+	wrong := wrongCode(subscribeWithCode(t, s, sender))
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		codes = map[int]int{}
+	)
+
+	for i := 0; i < 4*MaxVerificationAttempts; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			code, _ := postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+wrong+`"}`)
+
+			mu.Lock()
+			codes[code]++
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, MaxVerificationAttempts, codes[http.StatusPreconditionFailed])
+	assert.Equal(t, 3*MaxVerificationAttempts, codes[http.StatusTooManyRequests])
+}
+
+func TestPostSubscribeVerifyExpires(t *testing.T) {
+	sender := &recordingSender{}
+	s := &Server{DefaultTimeZone: "UTC", ConfirmationWindow: time.Hour, managers: memory.New(), Senders: Senders{models.ChannelSMS: sender}}
+
+	var otp string
+
+	withClockTime(t, mustParseTime("2015-05-01T19:00:00Z"), func(t *testing.T) {
+		otp = subscribeWithCode(t, s, sender)
+	})
+
+	withClockTime(t, mustParseTime("2015-05-01T19:10:00Z"), func(t *testing.T) {
+		code, _ := postSubscribeVerify(s, `{"number": "+14155551234", "code": "`+otp+`"}`)
+		assert.Equal(t, http.StatusGone, code)
+	})
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/twilio"
+)
+
+// The keys on the incoming call menu.
+const (
+	voiceKeySubscribe = "1"
+	voiceKeyStop      = "9"
+)
+
+// Where the menu sends the key that was pressed. Twilio resolves it against
+// the incoming call's URL.
+const voiceMenuAction = "/api/incoming-call/menu"
+
+const (
+	voiceSubscribePrompt   = `Press 1 to get a call every morning telling you what day it is.`
+	voiceStopPrompt        = `Press 9 to stop your morning calls.`
+	voiceRetry             = `Sorry, I didn't get that.`
+	voiceGoodbye           = `Goodbye!`
+	voiceStopConfirmation  = `Okay, no more calls. Call back and press 1 if you change your mind.`
+	voiceStartConfirmation = `Welcome back! Every morning I'll call you and tell you what day it is.`
+	voiceHelpMessage       = `What Day Is It: call this number to hear what day it is, then press 1 to get a call every morning or 9 to stop them.`
+)
+
+func writeTwiML(w http.ResponseWriter, twiml []byte) {
+	w.Header().Set("Content-Type", "text/xml")
+	w.Write(twiml)
+}
+
+// callerTimezone is the timezone that a caller hears the day in. Subscribers
+// get their own, whether they get calls or texts, and everyone else gets a
+// guess from their number.
+func (s *Server) callerTimezone(from string) string {
+	for _, id := range []string{models.SubscriberID(models.ChannelVoice, from), from} {
+		if phoneNumber, err := s.managers.PhoneNumbers().Get(id); err == nil {
+			return phoneNumber.Timezone
+		}
+	}
+
+	timezone, _ := s.timezoneFor(from, "")
+	return timezone
+}
+
+// voicePrompt is what the caller can do from the menu, which depends on
+// whether they already get calls.
+func (s *Server) voicePrompt(from string) string {
+	phoneNumber, err := s.managers.PhoneNumbers().Get(models.SubscriberID(models.ChannelVoice, from))
+
+	if err == nil && (phoneNumber.IsSendable() || phoneNumber.Status == models.StatusPaused) {
+		return voiceStopPrompt
+	}
+
+	return voiceSubscribePrompt
+}
+
+// PostIncomingCall is Twilio's voice webhook. Callers hear what day it is and
+// can subscribe to a call every morning, or stop them, from the keypad.
+func (s *Server) PostIncomingCall(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		logger.WithError(err).Error("failed to decode Twilio voice webhook request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !s.verifyTwilioRequest(w, r, r.PostForm) {
+		return
+	}
+
+	from := models.CleanPhoneNumber(r.PostForm.Get("From"))
+	timezone := s.callerTimezone(from)
+	now := clock.Clock().In(clock.MustLoadLocation(timezone))
+	today := fmt.Sprintf("Hi! It's %s in %s.", now.Format("Monday, January 2"), clock.Zone{Name: timezone}.DisplayName())
+
+	// There's no subscribing without caller ID.
+	if !models.IsCleanPhoneNumber(from) {
+		writeTwiML(w, twilio.TwiMLSay(today))
+		return
+	}
+
+	writeTwiML(w, twilio.TwiMLGather(today, s.voicePrompt(from), voiceMenuAction, voiceGoodbye))
+}
+
+// PostIncomingCallMenu handles the key pressed on an incoming call.
+func (s *Server) PostIncomingCallMenu(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		logger.WithError(err).Error("failed to decode Twilio voice webhook request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if !s.verifyTwilioRequest(w, r, r.PostForm) {
+		return
+	}
+
+	from := models.CleanPhoneNumber(r.PostForm.Get("From"))
+
+	if !models.IsCleanPhoneNumber(from) {
+		writeTwiML(w, twilio.TwiMLSay(voiceGoodbye))
+		return
+	}
+
+	var k keyword
+
+	switch r.PostForm.Get("Digits") {
+	case voiceKeySubscribe:
+		k = keywordStart
+	case voiceKeyStop:
+		k = keywordStop
+	default:
+		writeTwiML(w, twilio.TwiMLGather(voiceRetry, s.voicePrompt(from), voiceMenuAction, voiceGoodbye))
+		return
+	}
+
+	reply, err := s.handleKeyword(k, models.ChannelVoice, from)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to handle call")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	writeTwiML(w, twilio.TwiMLSay(reply))
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/twilio"
+	"github.com/stretchr/testify/assert"
+)
+
+// doIncomingCall posts to the voice webhook like Twilio would. Digits is
+// only sent to the menu.
+func doIncomingCall(s *Server, path, from, digits string) *httptest.ResponseRecorder {
+	form := url.Values{"From": {from}, "AccountSid": {"AC123"}}
+
+	if digits != "" {
+		form.Set("Digits", digits)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Twilio-Signature", twilio.Sign(s.TwilioAuthToken, s.BaseURL+path, form))
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestIncomingCall(t *testing.T) {
+	s, _ := newTestAPIServer()
+	id := models.SubscriberID(models.ChannelVoice, "+4915112345678")
+
+	withClockTime(t, mustParseTime("2026-10-19T23:30:00Z"), func(t *testing.T) {
+		w := doIncomingCall(s, "/api/incoming-call", "+4915112345678", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/xml", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "<Say>Hi! It&#39;s Tuesday, October 20 in Berlin.</Say>")
+		assert.Contains(t, w.Body.String(), `<Gather numDigits="1" action="/api/incoming-call/menu" method="POST"><Say>`+voiceSubscribePrompt+`</Say></Gather>`)
+
+		w = doIncomingCall(s, "/api/incoming-call/menu", "+4915112345678", "1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Every morning I&#39;ll call you")
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get(id)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, models.StatusActive, phoneNumber.Status)
+			assert.Equal(t, models.ChannelVoice, phoneNumber.DeliveryChannel())
+			assert.Equal(t, "+4915112345678", phoneNumber.DeliveryAddress())
+			assert.Equal(t, "Europe/Berlin", phoneNumber.Timezone)
+			assert.Equal(t, "Good morning, today is Tuesday.", DailyMessage(phoneNumber))
+		}
+
+		// Texts are a separate subscription.
+		_, err = s.managers.PhoneNumbers().Get("+4915112345678")
+		assert.Error(t, err)
+
+		w = doIncomingCall(s, "/api/incoming-call", "+4915112345678", "")
+		assert.Contains(t, w.Body.String(), voiceStopPrompt)
+
+		w = doIncomingCall(s, "/api/incoming-call/menu", "+4915112345678", "5")
+		assert.Contains(t, w.Body.String(), "<Say>Sorry, I didn&#39;t get that.</Say><Gather")
+
+		w = doIncomingCall(s, "/api/incoming-call/menu", "+4915112345678", "9")
+		assert.Contains(t, w.Body.String(), "<Say>Okay, no more calls.")
+
+		phoneNumber, _ = s.managers.PhoneNumbers().Get(id)
+		assert.Equal(t, models.StatusStopped, phoneNumber.Status)
+
+		w = doIncomingCall(s, "/api/incoming-call/menu", "+4915112345678", "1")
+		assert.Contains(t, w.Body.String(), "<Say>"+strings.Replace(voiceStartConfirmation, "'", "&#39;", -1)+"</Say>")
+	})
+}
+
+func TestIncomingCallRepliesAreSpoken(t *testing.T) {
+	s, _ := newTestAPIServer()
+	id := models.SubscriberID(models.ChannelVoice, "+4915112345678")
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		// Signed up from the form and still waiting on the code.
+		assert.NoError(t, s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: id, Channel: models.ChannelVoice, Address: "+4915112345678", Timezone: "Europe/Berlin", Status: models.StatusPending}))
+
+		w := doIncomingCall(s, "/api/incoming-call/menu", "+4915112345678", "1")
+		assert.Contains(t, w.Body.String(), "<Say>Hi! Every morning I&#39;ll call you")
+		assert.NotContains(t, w.Body.String(), "text")
+
+		phoneNumber, _ := s.managers.PhoneNumbers().Get(id)
+		assert.Equal(t, models.StatusActive, phoneNumber.Status)
+
+		// Pressing 1 again is a welcome back.
+		w = doIncomingCall(s, "/api/incoming-call/menu", "+4915112345678", "1")
+		assert.Contains(t, w.Body.String(), "<Say>Welcome back! Every morning I&#39;ll call you")
+	})
+}
+
+func TestIncomingCallHasToBeSigned(t *testing.T) {
+	s, _ := newTestAPIServer()
+	form := url.Values{"From": {"+4915112345678"}, "Digits": {"1"}}
+
+	for _, path := range []string{"/api/incoming-call", "/api/incoming-call/menu"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Twilio-Signature", twilio.Sign("nope", s.BaseURL+path, form))
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, path)
+	}
+
+	// Pressing 1 didn't subscribe anybody.
+	_, err := s.managers.PhoneNumbers().Get(models.SubscriberID(models.ChannelVoice, "+4915112345678"))
+	assert.Error(t, err)
+}
+
+func TestIncomingCallUsesSubscriberTimezone(t *testing.T) {
+	s, _ := newTestAPIServer()
+	s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "Asia/Tokyo", Status: models.StatusActive})
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		w := doIncomingCall(s, "/api/incoming-call", "+14155551234", "")
+		assert.Contains(t, w.Body.String(), "Hi! It&#39;s Tuesday, October 20 in Tokyo.")
+
+		// No caller ID, no menu.
+		w = doIncomingCall(s, "/api/incoming-call", "anonymous", "")
+		assert.Contains(t, w.Body.String(), "Hi! It&#39;s Monday, October 19 in UTC.")
+		assert.NotContains(t, w.Body.String(), "<Gather")
+	})
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/webhook"
+)
+
+// WebhookSubscription looks up the webhook subscription that posts to url, for
+// the webhook sender to sign with.
+func (s *Server) WebhookSubscription(url string) (webhook.Subscription, error) {
+	phoneNumber, err := s.managers.PhoneNumbers().Get(models.SubscriberID(models.ChannelWebhook, url))
+
+	if err != nil {
+		return webhook.Subscription{}, err
+	}
+
+	return webhook.Subscription{
+		ID:       phoneNumber.Number,
+		Secret:   phoneNumber.WebhookSecret,
+		Timezone: phoneNumber.Timezone,
+	}, nil
+}
+
+// RecordWebhookAttempt adds an attempt at a delivery to the subscription's
+// history, which is where its delivery history comes from.
+func (s *Server) RecordWebhookAttempt(a webhook.Attempt) {
+	metadata := map[string]string{
+		"delivery_id": a.DeliveryID,
+		"attempt":     strconv.Itoa(a.Attempt),
+		"duration_ms": strconv.FormatInt(int64(a.Duration/time.Millisecond), 10),
+	}
+
+	if a.StatusCode != 0 {
+		metadata["status_code"] = strconv.Itoa(a.StatusCode)
+	}
+
+	if a.Error != "" {
+		metadata["error"] = a.Error
+	}
+
+	s.recordEvent(a.Subscription, models.EventWebhookAttempt, models.ActorSystem, metadata)
+}
+
+type DeliveryAttempt struct {
+	// The same for every attempt at a delivery, and in the X-Webhook-Id
+	// header.
+	DeliveryID string `json:"delivery_id"`
+
+	// Which try this was, starting at 1.
+	Attempt int       `json:"attempt"`
+	At      time.Time `json:"at"`
+
+	Succeeded bool `json:"succeeded"`
+
+	// What the receiver responded with. Missing if it never did.
+	StatusCode int `json:"status_code,omitempty"`
+
+	DurationMS int `json:"duration_ms"`
+
+	// What went wrong, if anything.
+	Error string `json:"error,omitempty"`
+}
+
+func newDeliveryAttempt(event models.Event) DeliveryAttempt {
+	attempt, _ := strconv.Atoi(event.Metadata["attempt"])
+	status, _ := strconv.Atoi(event.Metadata["status_code"])
+	duration, _ := strconv.Atoi(event.Metadata["duration_ms"])
+
+	return DeliveryAttempt{
+		DeliveryID: event.Metadata["delivery_id"],
+		Attempt:    attempt,
+		At:         event.At.UTC(),
+		Succeeded:  event.Metadata["error"] == "",
+		StatusCode: status,
+		DurationMS: duration,
+		Error:      event.Metadata["error"],
+	}
+}
+
+type GetDeliveriesResponse struct {
+	// Newest first.
+	Deliveries []DeliveryAttempt `json:"deliveries"`
+}
+
+// GetSubscriptionDeliveries lists the most recent attempts at posting to a
+// webhook subscription.
+func (s *Server) GetSubscriptionDeliveries(w http.ResponseWriter, r *http.Request) {
+	limit := DefaultEventLimit
+
+	if str := r.URL.Query().Get("limit"); str != "" {
+		if n, err := strconv.Atoi(str); err != nil || n < 1 || n > MaxEventLimit {
+			writeError(w, http.StatusBadRequest, ErrorCodeInvalidRequest, fmt.Sprintf("The limit must be between 1 and %d.", MaxEventLimit))
+			return
+		} else {
+			limit = n
+		}
+	}
+
+	phoneNumber, ok := s.getSubscription(w, r)
+
+	if !ok {
+		return
+	}
+
+	if phoneNumber.DeliveryChannel() != models.ChannelWebhook {
+		writeError(w, http.StatusNotFound, ErrorCodeNotFound, "Only webhook subscriptions have a delivery history.")
+		return
+	}
+
+	// Attempts are mixed in with everything else that happened to the
+	// subscription.
+	events, err := s.managers.Events().List(phoneNumber.Number, MaxEventLimit)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to list events")
+		writeError(w, http.StatusInternalServerError, ErrorCodeInternal, "An internal error occured.")
+		return
+	}
+
+	resp := GetDeliveriesResponse{Deliveries: []DeliveryAttempt{}}
+
+	for _, event := range events {
+		if event.Type == models.EventWebhookAttempt && len(resp.Deliveries) < limit {
+			resp.Deliveries = append(resp.Deliveries, newDeliveryAttempt(event))
+		}
+	}
+
+	writeJSON(w, resp)
+}
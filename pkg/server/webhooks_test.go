@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+const testGenericWebhookURL = "https://example.com/hooks/days"
+
+func TestWebhookSubscriptionErrors(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	for _, url := range []string{"", "http://example.com/hooks/days", "https://127.0.0.1/hooks", "https://169.254.169.254/latest"} {
+		w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"channel": "webhook", "webhook_url": "`+url+`"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+		assert.Equal(t, ErrorCodeInvalidWebhookURL, decodeAPIError(w).Code, url)
+	}
+}
+
+func TestGetSubscriptionDeliveries(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	var body []byte
+	statuses := []int{http.StatusServiceUnavailable}
+
+	receiver := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = ioutil.ReadAll(r.Body)
+
+		if len(statuses) > 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+		}
+	}))
+
+	defer receiver.Close()
+
+	// The receiver is on localhost, which the API won't subscribe.
+	phoneNumber := models.PhoneNumber{
+		Number:        models.SubscriberID(models.ChannelWebhook, receiver.URL),
+		Channel:       models.ChannelWebhook,
+		Address:       receiver.URL,
+		WebhookSecret: "whsec_test",
+		Timezone:      "UTC",
+		Status:        models.StatusActive,
+	}
+
+	if err := s.managers.PhoneNumbers().Create(phoneNumber); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := webhook.NewSender(s.WebhookSubscription)
+	sender.Client = receiver.Client()
+	sender.Record = s.RecordWebhookAttempt
+	sender.MinBackoff = time.Millisecond
+	s.Senders[models.ChannelWebhook] = sender
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		assert.NoError(t, s.Senders.Send(phoneNumber, "Today is Monday"))
+		sender.Wait()
+
+		var payload webhook.Payload
+		assert.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, phoneNumber.Number, payload.Subscription)
+		assert.Equal(t, "Monday", payload.Day)
+
+		token := s.manageToken(phoneNumber.Number)
+		w := doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/"+phoneNumber.Number+"/deliveries", token, "")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var resp GetDeliveriesResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+		if assert.Len(t, resp.Deliveries, 2) {
+			// Newest first.
+			assert.Equal(t, DeliveryAttempt{
+				DeliveryID: payload.ID,
+				Attempt:    2,
+				At:         *clock.Clock(),
+				Succeeded:  true,
+				StatusCode: http.StatusOK,
+				DurationMS: resp.Deliveries[0].DurationMS,
+			}, resp.Deliveries[0])
+
+			assert.Equal(t, DeliveryAttempt{
+				DeliveryID: payload.ID,
+				Attempt:    1,
+				At:         *clock.Clock(),
+				StatusCode: http.StatusServiceUnavailable,
+				DurationMS: resp.Deliveries[1].DurationMS,
+				Error:      "webhook: receiver responded with 503 Service Unavailable",
+			}, resp.Deliveries[1])
+		}
+
+		w = doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/"+phoneNumber.Number+"/deliveries?limit=1", token, "")
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Len(t, resp.Deliveries, 1)
+
+		w = doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/"+phoneNumber.Number+"/deliveries?limit=0", token, "")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/"+phoneNumber.Number+"/deliveries", "", "")
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		// Nothing else has a delivery history.
+		s.managers.PhoneNumbers().Create(models.PhoneNumber{Number: "+14155551234", Timezone: "UTC", Status: models.StatusActive})
+
+		w = doAPIRequest(s, http.MethodGet, "/api/v1/subscriptions/+14155551234/deliveries", s.manageToken("+14155551234"), "")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+)
+
+const confirmationRequestMessage = `Someone signed this number up to get a text every morning telling you what day it is. Reply YES to confirm, or just ignore this if it wasn't you.`
+
+// welcomeMessages returns the messages a brand new subscriber gets, in order.
+func welcomeMessages(phoneNumber models.PhoneNumber) []string {
+	today := clock.GetDayInZone(clock.MustLoadLocation(phoneNumber.Timezone))
+
+	var messages []string
+
+	switch phoneNumber.DeliveryChannel() {
+	case models.ChannelSMS:
+		messages = []string{
+			"Yo! Okay, every morning I'll text you what day it is. Just say STOP to make me stop.",
+			fmt.Sprintf("Today is %s by the way.", today),
+		}
+
+		if phoneNumber.TimezoneGuessed {
+			messages = append(messages, fmt.Sprintf("I'm guessing you're on %s time. If not, reply TZ and your city, like TZ Chicago.", phoneNumber.Timezone))
+		}
+	case models.ChannelWhatsApp:
+		messages = []string{
+			fmt.Sprintf("Yo! Okay, every morning I'll message you what day it is. Just say STOP to make me stop. Today is %s by the way.", today),
+		}
+
+		if phoneNumber.TimezoneGuessed {
+			messages = append(messages, fmt.Sprintf("I'm guessing you're on %s time. If not, reply TZ and your city, like TZ Chicago.", phoneNumber.Timezone))
+		}
+	case models.ChannelTelegram:
+		messages = []string{
+			fmt.Sprintf("Yo! Okay, every morning I'll message you what day it is. Just send /stop to make me stop. Today is %s by the way.", today),
+		}
+
+		if phoneNumber.TimezoneGuessed {
+			messages = append(messages, fmt.Sprintf("I'm going with %s time. If that's wrong, send /tz and your city, like /tz Chicago.", phoneNumber.Timezone))
+		}
+	case models.ChannelVoice:
+		// Every message is a phone call, so there's only one.
+		messages = []string{
+			fmt.Sprintf("Hi! Every morning I'll call you and tell you what day it is. Today is %s. To stop the calls, call this number and press 9.", today),
+		}
+	case models.ChannelEmail:
+		messages = []string{
+			fmt.Sprintf("Hi! Every morning I'll email you what day it is in %s. Today is %s by the way.", phoneNumber.Timezone, today),
+		}
+	default:
+		// Nobody can message us from a webhook, so there's nothing to tell
+		// them about replying.
+		messages = []string{
+			fmt.Sprintf("Hi! Every morning I'll post what day it is in %s here. Today is %s.", phoneNumber.Timezone, today),
+		}
+	}
+
+	return messages
+}
+
+// DailyMessage is what a subscriber gets every morning.
+func DailyMessage(phoneNumber models.PhoneNumber) string {
+	today := clock.GetDayInZone(clock.MustLoadLocation(phoneNumber.Timezone))
+
+	if phoneNumber.DeliveryChannel() == models.ChannelVoice {
+		return fmt.Sprintf("Good morning, today is %s.", today)
+	}
+
+	return fmt.Sprintf("Today is %s", today)
+}
+
+// WelcomeBackMessage replaces the daily message the first time a subscriber
+// gets one after a pause.
+func WelcomeBackMessage(phoneNumber models.PhoneNumber) string {
+	return fmt.Sprintf("Welcome back! Today is %s.", clock.GetDayInZone(clock.MustLoadLocation(phoneNumber.Timezone)))
+}
+
+// subscribeByMessage signs up a number, or a chat on another channel, that
+// messaged us out of the blue. The reply is all they'll see, so it carries the
+// whole welcome.
+func (s *Server) subscribeByMessage(channel models.Channel, address string) (string, error) {
+	from := models.SubscriberID(channel, address)
+	timezone, guessed := s.timezoneFor(guessableNumber(channel, address), "")
+	now := clock.Clock()
+
+	// Messaging us is all the confirmation we need.
+	phoneNumber := models.PhoneNumber{
+		Number:          from,
+		Timezone:        timezone,
+		TimezoneGuessed: guessed,
+		Status:          models.StatusActive,
+		StatusChangedAt: map[models.Status]time.Time{models.StatusActive: *now},
+	}
+
+	if channel != models.ChannelSMS {
+		phoneNumber.Channel = channel
+		phoneNumber.Address = address
+	}
+
+	if err := s.managers.PhoneNumbers().Create(phoneNumber); err != nil {
+		logger.WithError(err).Error("failed to save phone number")
+		return "", err
+	}
+
+	s.recordEvent(from, models.EventSubscribed, models.ActorSubscriber, map[string]string{
+		"timezone": timezone,
+	})
+
+	// They're getting today's message in the reply so don't send another.
+	s.managers.PhoneNumbers().UpdateSent(&phoneNumber, clock.Clock())
+
+	logger.WithField("channel", channel).Info("user subscribed by message")
+	return strings.Join(welcomeMessages(phoneNumber), " "), nil
+}
+
+// confirmBySMS activates a subscription that was waiting for the owner of the
+// number to reply YES.
+func (s *Server) confirmBySMS(phoneNumber models.PhoneNumber) (string, error) {
+	if err := s.managers.PhoneNumbers().UpdateStatus(&phoneNumber, models.StatusActive, clock.Clock()); err != nil {
+		logger.WithError(err).Error("failed to confirm phone number")
+		return "", err
+	}
+
+	s.recordEvent(phoneNumber.Number, models.EventConfirmed, models.ActorSubscriber, map[string]string{
+		"verification": VerifyByReply,
+	})
+
+	// They're getting today's message in the reply so don't send another.
+	s.managers.PhoneNumbers().UpdateSent(&phoneNumber, clock.Clock())
+
+	logger.Info("user confirmed subscription")
+	return strings.Join(welcomeMessages(phoneNumber), " "), nil
+}
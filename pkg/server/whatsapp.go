@@ -0,0 +1,40 @@
+package server
+
+import (
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+	"github.com/bradhe/what-day-is-it/pkg/twilio"
+)
+
+// WhatsAppSessionOpen indicates that address messaged us recently enough
+// that WhatsApp will take any message for it, not just an approved template.
+// It's meant for twilio.Sender's SessionOpen.
+func (s *Server) WhatsAppSessionOpen(address string) bool {
+	phoneNumber, err := s.managers.PhoneNumbers().Get(models.SubscriberID(models.ChannelWhatsApp, address))
+
+	if err != nil {
+		if err != managers.ErrRecordNotFound {
+			logger.WithError(err).Error("failed to find WhatsApp subscriber")
+		}
+
+		return false
+	}
+
+	return phoneNumber.LastReceivedAt != nil && clock.Clock().Sub(*phoneNumber.LastReceivedAt) < twilio.SessionWindow
+}
+
+// recordReceived notes that address messaged us on WhatsApp, which opens the
+// session window. Messages from people that aren't subscribed don't matter
+// since we'll never message them first.
+func (s *Server) recordReceived(address string) {
+	phoneNumber, err := s.managers.PhoneNumbers().Get(models.SubscriberID(models.ChannelWhatsApp, address))
+
+	if err == managers.ErrRecordNotFound {
+		return
+	} else if err != nil {
+		logger.WithError(err).Error("failed to find WhatsApp subscriber")
+	} else if err := s.managers.PhoneNumbers().UpdateReceived(&phoneNumber, clock.Clock()); err != nil {
+		logger.WithError(err).Error("failed to record WhatsApp message")
+	}
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/twilio"
+	"github.com/stretchr/testify/assert"
+)
+
+// doIncomingMessage sends a message to the Twilio webhook like Twilio would.
+func doIncomingMessage(s *Server, from, body string) *httptest.ResponseRecorder {
+	form := url.Values{"From": {from}, "Body": {body}, "AccountSid": {"AC123"}}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/incoming-message", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Twilio-Signature", twilio.Sign(s.TwilioAuthToken, s.BaseURL+"/api/incoming-message", form))
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestIncomingWhatsAppMessage(t *testing.T) {
+	s, sms := newTestAPIServer()
+	id := models.SubscriberID(models.ChannelWhatsApp, "whatsapp:+4915112345678")
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		w := doIncomingMessage(s, "whatsapp:+4915112345678", "START")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Today is Monday by the way.")
+
+		phoneNumber, err := s.managers.PhoneNumbers().Get(id)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, models.StatusActive, phoneNumber.Status)
+			assert.Equal(t, models.ChannelWhatsApp, phoneNumber.DeliveryChannel())
+			assert.Equal(t, "whatsapp:+4915112345678", phoneNumber.DeliveryAddress())
+			assert.Equal(t, "Europe/Berlin", phoneNumber.Timezone)
+			assert.Equal(t, clock.Clock(), phoneNumber.LastReceivedAt)
+		}
+
+		w = doIncomingMessage(s, "whatsapp:+4915112345678", "TZ Tokyo")
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		phoneNumber, _ = s.managers.PhoneNumbers().Get(id)
+		assert.Equal(t, "Asia/Tokyo", phoneNumber.Timezone)
+
+		// The same number on SMS is somebody else.
+		_, err = s.managers.PhoneNumbers().Get("+4915112345678")
+		assert.Error(t, err)
+
+		// Replies go back in the response.
+		assert.Empty(t, sms.Sent())
+	})
+}
+
+func TestWhatsAppSessionOpen(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	withClockTime(t, mustParseTime("2026-10-19T17:00:00Z"), func(t *testing.T) {
+		// Nobody's messaged us yet.
+		assert.False(t, s.WhatsAppSessionOpen("whatsapp:+14155551234"))
+
+		doIncomingMessage(s, "whatsapp:+14155551234", "START")
+		assert.True(t, s.WhatsAppSessionOpen("whatsapp:+14155551234"))
+
+		// Messages from people we don't know about don't open anything.
+		doIncomingMessage(s, "whatsapp:+14155554321", "HELP")
+		assert.False(t, s.WhatsAppSessionOpen("whatsapp:+14155554321"))
+	})
+
+	withClockTime(t, mustParseTime("2026-10-20T16:59:00Z"), func(t *testing.T) {
+		assert.True(t, s.WhatsAppSessionOpen("whatsapp:+14155551234"))
+	})
+
+	withClockTime(t, mustParseTime("2026-10-20T17:00:00Z"), func(t *testing.T) {
+		assert.False(t, s.WhatsAppSessionOpen("whatsapp:+14155551234"))
+	})
+}
+
+func TestWhatsAppSubscriptionNeedsAMessage(t *testing.T) {
+	s, _ := newTestAPIServer()
+
+	w := doAPIRequest(s, http.MethodPost, "/api/v1/subscriptions", "", `{"channel": "whatsapp", "number": "+14155551234"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ErrorCodeInvalidChannel, decodeAPIError(w).Code)
+}
+
+func TestIncomingMessageHasToBeSigned(t *testing.T) {
+	s, _ := newTestAPIServer()
+	form := url.Values{"From": {"+14155551234"}, "Body": {"START"}}
+
+	for _, signature := range []string{"", twilio.Sign("nope", s.BaseURL+"/api/incoming-message", form)} {
+		req := httptest.NewRequest(http.MethodPost, "/api/incoming-message", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Twilio-Signature", signature)
+
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+
+	// Nothing is signed without the auth token.
+	s.TwilioAuthToken = ""
+	w := doIncomingMessage(s, "+14155551234", "START")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
@@ -0,0 +1,98 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bradhe/what-day-is-it/pkg/hooks"
+	"github.com/bradhe/what-day-is-it/pkg/logs"
+)
+
+var logger = logs.WithPackage("slack")
+
+// WebhookHost is where Slack's incoming webhooks live. We won't post anywhere
+// else, since the URLs come from whoever fills in the subscribe form.
+const WebhookHost = "hooks.slack.com"
+
+// Message is what we post to a webhook or answer a slash command with.
+type Message struct {
+	// For answers to slash commands, whether everybody in the channel sees it
+	// or just the person that asked.
+	ResponseType string `json:"response_type,omitempty"`
+
+	Text string `json:"text"`
+}
+
+const (
+	ResponseTypeInChannel = "in_channel"
+	ResponseTypeEphemeral = "ephemeral"
+)
+
+// Error is Slack refusing a message that we posted to a webhook.
+type Error struct {
+	Status int
+
+	// Something like no_service or channel_is_archived.
+	Reason string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("slack: %s (status %d)", e.Reason, e.Status)
+}
+
+// These mean the webhook is gone for good, so there's no point in trying
+// again tomorrow.
+var undeliverableReasons = map[string]bool{
+	"invalid_token":       true,
+	"no_service":          true,
+	"no_service_id":       true,
+	"no_team":             true,
+	"team_disabled":       true,
+	"channel_not_found":   true,
+	"channel_is_archived": true,
+}
+
+// Undeliverable indicates that the webhook can't take messages at all, as
+// opposed to something going wrong this time around.
+func (e *Error) Undeliverable() bool {
+	return undeliverableReasons[e.Reason] || e.Status == http.StatusGone
+}
+
+// IsWebhookURL indicates that str looks like one of Slack's incoming webhook
+// URLs.
+func IsWebhookURL(str string) bool {
+	u, err := url.Parse(str)
+
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "https" && u.Host == WebhookHost && strings.HasPrefix(u.Path, "/services/")
+}
+
+// Sender posts messages to incoming webhooks.
+type Sender struct {
+	hooks.Sender
+}
+
+func NewSender() Sender {
+	return Sender{hooks.NewSender(func(status int, body []byte) error {
+		return &Error{Status: status, Reason: strings.TrimSpace(string(body))}
+	})}
+}
+
+// Send posts body to the webhook at to.
+func (s Sender) Send(to, body string) error {
+	buf, _ := json.Marshal(Message{Text: body})
+
+	if _, err := s.Post(to, nil, buf); err != nil {
+		logger.WithError(err).Error("failed to post message to Slack")
+		return err
+	}
+
+	logger.Info("message posted to Slack")
+	return nil
+}
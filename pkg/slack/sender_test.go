@@ -0,0 +1,62 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSend(t *testing.T) {
+	var got Message
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	assert.NoError(t, NewSender().Send(ts.URL, "Today is Monday"))
+	assert.Equal(t, Message{Text: "Today is Monday"}, got)
+}
+
+func TestSendErrors(t *testing.T) {
+	cases := []struct {
+		status        int
+		reason        string
+		undeliverable bool
+	}{
+		{http.StatusNotFound, "no_service", true},
+		{http.StatusGone, "channel_is_archived", true},
+		{http.StatusForbidden, "invalid_token", true},
+		{http.StatusBadRequest, "invalid_payload", false},
+		{http.StatusInternalServerError, "", false},
+	}
+
+	for _, c := range cases {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(c.status)
+			w.Write([]byte(c.reason))
+		}))
+
+		err := NewSender().Send(ts.URL, "Today is Monday")
+		ts.Close()
+
+		if serr, ok := err.(*Error); assert.True(t, ok, c.reason) {
+			assert.Equal(t, c.status, serr.Status)
+			assert.Equal(t, c.reason, serr.Reason)
+			assert.Equal(t, c.undeliverable, serr.Undeliverable(), c.reason)
+		}
+	}
+}
+
+func TestIsWebhookURL(t *testing.T) {
+	assert.True(t, IsWebhookURL("https://hooks.slack.com/services/T000/B000/XXXX"))
+	assert.False(t, IsWebhookURL("http://hooks.slack.com/services/T000/B000/XXXX"))
+	assert.False(t, IsWebhookURL("https://hooks.slack.com.example.com/services/T000/B000/XXXX"))
+	assert.False(t, IsWebhookURL("https://example.com/services/T000/B000/XXXX"))
+	assert.False(t, IsWebhookURL("not a url"))
+}
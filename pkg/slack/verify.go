@@ -0,0 +1,28 @@
+package slack
+
+import (
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/hooks"
+)
+
+// MaxRequestAge is how old a signed request can be before we assume that
+// somebody is replaying it.
+var MaxRequestAge = 5 * time.Minute
+
+var (
+	ErrInvalidSignature = hooks.ErrInvalidSignature
+	ErrStaleRequest     = hooks.ErrStaleRequest
+)
+
+// Sign returns the signature Slack sends along with body in the
+// X-Slack-Signature header.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	return hooks.Sign(secret, "v0", timestamp, body)
+}
+
+// VerifyRequest checks that body came from Slack, using the timestamp and
+// signature from the X-Slack-Request-Timestamp and X-Slack-Signature headers.
+func VerifyRequest(secret []byte, timestamp, signature string, body []byte, now time.Time) error {
+	return hooks.Verify(secret, "v0", timestamp, signature, body, now, MaxRequestAge)
+}
@@ -0,0 +1,34 @@
+package slack
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	secret := []byte("8f742231b10e8888abcd99yyyzzz85a5")
+	body := []byte("token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&command=%2Fwhatday&text=Tokyo")
+	now := time.Unix(1531420618, 0)
+	timestamp := "1531420618"
+	signature := Sign(secret, timestamp, body)
+
+	assert.NoError(t, VerifyRequest(secret, timestamp, signature, body, now))
+	assert.NoError(t, VerifyRequest(secret, timestamp, signature, body, now.Add(4*time.Minute)))
+
+	assert.Equal(t, ErrStaleRequest, VerifyRequest(secret, timestamp, signature, body, now.Add(6*time.Minute)))
+	assert.Equal(t, ErrStaleRequest, VerifyRequest(secret, timestamp, signature, body, now.Add(-6*time.Minute)))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(secret, timestamp, signature, append(body, 'x'), now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest([]byte("nope"), timestamp, signature, body, now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(nil, timestamp, signature, body, now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest(secret, "yesterday", signature, body, now))
+}
+
+func TestSign(t *testing.T) {
+	// The example from Slack's documentation.
+	secret := []byte("8f742231b10e8888abcd99yyyzzz85a5")
+	body := []byte("token=xyzz0WbapA4vBCDEFasx0q6G&team_id=T1DC2JH3J&team_domain=testteamnow&channel_id=G8PSS9T3V&channel_name=foobar&user_id=U2CERLKJA&user_name=roadrunner&command=%2Fwebhook-collect&text=&response_url=https%3A%2F%2Fhooks.slack.com%2Fcommands%2FT1DC2JH3J%2F397700885554%2F96rGlfmibIGlgcZRskXaIFfN&trigger_id=398738663015.47445629121.803a0bc887a14d10d2c447fce8b6703c")
+
+	assert.Equal(t, "v0=a2114d57b48eac39b9ad189dd8316235a7b4a8d21a10bd27519666489c69b503", Sign(secret, "1531420618", body))
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bradhe/what-day-is-it/pkg/clock"
 	"github.com/bradhe/what-day-is-it/pkg/logs"
 	"github.com/bradhe/what-day-is-it/pkg/models"
 	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
@@ -58,12 +59,36 @@ func getTime(name string, attrs map[string]*awsdynamodb.AttributeValue) *time.Ti
 	return &time.Time{}
 }
 
+// getOptionalTime is like getTime but returns nil if the attribute is missing.
+func getOptionalTime(name string, attrs map[string]*awsdynamodb.AttributeValue) *time.Time {
+	if _, ok := attrs[name]; ok {
+		return getTime(name, attrs)
+	}
+
+	return nil
+}
+
 func getTimeAttribute(t *time.Time) *awsdynamodb.AttributeValue {
 	var attr awsdynamodb.AttributeValue
 	attr.N = aws.String(formatTime(t))
 	return &attr
 }
 
+func getInt(name string, attrs map[string]*awsdynamodb.AttributeValue) int {
+	if val, ok := attrs[name]; ok {
+		i, _ := strconv.Atoi(aws.StringValue(val.N))
+		return i
+	}
+
+	return 0
+}
+
+func getIntAttribute(i int) *awsdynamodb.AttributeValue {
+	var attr awsdynamodb.AttributeValue
+	attr.N = aws.String(strconv.Itoa(i))
+	return &attr
+}
+
 func getBool(name string, attrs map[string]*awsdynamodb.AttributeValue) bool {
 	if val, ok := attrs[name]; ok {
 		return aws.BoolValue(val.BOOL)
@@ -82,8 +107,18 @@ func deserializePhoneNumber(attrs map[string]*awsdynamodb.AttributeValue) (num m
 	num.Number = getString("phone_number", attrs)
 	num.Timezone = getString("timezone", attrs)
 	num.LastSentAt = getTime("last_sent_at", attrs)
-	num.IsSendable = getBool("is_sendable", attrs)
+	num.Status = getStatus(attrs)
+	num.StatusChangedAt = getStatusChangedAt("status_changed_at", attrs)
 	num.SendDeadline = getTime("send_deadline", attrs)
+	num.TimezoneGuessed = getBool("timezone_guessed", attrs)
+	num.ResubscribedAt = getTime("resubscribed_at", attrs)
+	num.ConfirmationExpiresAt = getOptionalTime("expires_at", attrs)
+	num.PausedUntil = getOptionalTime("paused_until", attrs)
+	num.Schedule = getSchedule(attrs)
+	num.Channel = models.Channel(getString("channel", attrs))
+	num.Address = getString("address", attrs)
+	num.WebhookSecret = getString("webhook_secret", attrs)
+	num.LastReceivedAt = getOptionalTime("last_received_at", attrs)
 	return
 }
 
@@ -129,22 +164,18 @@ func (m dynamodbPhoneNumberManager) Get(num string) (models.PhoneNumber, error)
 	if out, err := m.svc.GetItem(&in); err != nil {
 		logger.WithError(err).Errorf("failed to get phone number in DynamoDB")
 		return models.PhoneNumber{}, err
+	} else if len(out.Item) == 0 {
+		return models.PhoneNumber{}, managers.ErrRecordNotFound
+	} else if phoneNumber := deserializePhoneNumber(out.Item); phoneNumber.IsExpired(clock.Clock()) {
+		// DynamoDB can take a while to get around to deleting expired items.
+		return models.PhoneNumber{}, managers.ErrRecordNotFound
 	} else {
-		return deserializePhoneNumber(out.Item), nil
+		return phoneNumber, nil
 	}
 }
 
-func nextDeadline(sentAt *time.Time, loc *time.Location) *time.Time {
-	nextDay := sentAt.In(loc).Add(24 * time.Hour)
-
-	// this is a funny way of thunking the date to 8am in that location.
-	deadline := time.Date(nextDay.Year(), nextDay.Month(), nextDay.Day(), 8, 0, 0, 0, nextDay.Location())
-
-	return &deadline
-}
-
 func (m dynamodbPhoneNumberManager) UpdateSent(num *models.PhoneNumber, sentAt *time.Time) error {
-	newDeadline := nextDeadline(sentAt, MustLoadLocation(num.Timezone))
+	newDeadline := clock.NextDeadline(sentAt, MustLoadLocation(num.Timezone), num.DeliverySchedule())
 
 	in := awsdynamodb.UpdateItemInput{
 		Key: map[string]*awsdynamodb.AttributeValue{
@@ -173,58 +204,99 @@ func (m dynamodbPhoneNumberManager) UpdateSent(num *models.PhoneNumber, sentAt *
 	return nil
 }
 
-func (m dynamodbPhoneNumberManager) UpdateNotSendable(num *models.PhoneNumber) error {
+func (m dynamodbPhoneNumberManager) UpdateSkipped(num *models.PhoneNumber, sentAt *time.Time) error {
+	newDeadline := clock.NextDeadline(sentAt, MustLoadLocation(num.Timezone), num.DeliverySchedule())
+
 	in := awsdynamodb.UpdateItemInput{
 		Key: map[string]*awsdynamodb.AttributeValue{
 			"phone_number": getStringAttribute(num.Number),
 		},
 		TableName: aws.String(m.tableName()),
 		ExpressionAttributeNames: map[string]*string{
-			"#is_sendable": aws.String("is_sendable"),
+			"#send_deadline": aws.String("send_deadline"),
 		},
 		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
-			":is_sendable": getBoolAttribute(false),
+			":send_deadline": getTimeAttribute(newDeadline),
 		},
-		UpdateExpression: aws.String("SET #is_sendable = :is_sendable"),
+		UpdateExpression: aws.String("SET #send_deadline = :send_deadline"),
 	}
 
 	if _, err := m.svc.UpdateItem(&in); err != nil {
-		logger.WithError(err).Errorf("failed to update not sendable phone number in DynamoDB")
+		logger.WithError(err).Errorf("failed to update skipped phone number in DynamoDB")
 		return err
 	} else {
-		num.IsSendable = false
+		num.SendDeadline = newDeadline
 	}
 
 	return nil
 }
 
-func (m dynamodbPhoneNumberManager) UpdateSendable(num *models.PhoneNumber) error {
+func (m dynamodbPhoneNumberManager) UpdateTimezone(num *models.PhoneNumber, timezone string, now *time.Time) error {
+	newDeadline := clock.RescheduleDeadline(num.LastSentAt, now, MustLoadLocation(timezone), num.DeliverySchedule())
+
 	in := awsdynamodb.UpdateItemInput{
 		Key: map[string]*awsdynamodb.AttributeValue{
 			"phone_number": getStringAttribute(num.Number),
 		},
 		TableName: aws.String(m.tableName()),
 		ExpressionAttributeNames: map[string]*string{
-			"#is_sendable": aws.String("is_sendable"),
+			"#timezone":         aws.String("timezone"),
+			"#timezone_guessed": aws.String("timezone_guessed"),
+			"#send_deadline":    aws.String("send_deadline"),
 		},
 		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
-			":is_sendable": getBoolAttribute(true),
+			":timezone":         getStringAttribute(timezone),
+			":timezone_guessed": getBoolAttribute(false),
+			":send_deadline":    getTimeAttribute(newDeadline),
 		},
-		UpdateExpression: aws.String("SET #is_sendable = :is_sendable"),
+		UpdateExpression: aws.String("SET #timezone = :timezone, #timezone_guessed = :timezone_guessed, #send_deadline = :send_deadline"),
 	}
 
 	if _, err := m.svc.UpdateItem(&in); err != nil {
-		logger.WithError(err).Errorf("failed to update sendable phone number in DynamoDB")
+		logger.WithError(err).Errorf("failed to update timezone for phone number in DynamoDB")
 		return err
 	} else {
-		num.IsSendable = false
+		num.Timezone = timezone
+		num.TimezoneGuessed = false
+		num.SendDeadline = newDeadline
 	}
 
 	return nil
 }
 
-func (m dynamodbPhoneNumberManager) UpdateSkipped(num *models.PhoneNumber, sentAt *time.Time) error {
-	newDeadline := nextDeadline(sentAt, MustLoadLocation(num.Timezone))
+func (m dynamodbPhoneNumberManager) UpdateReceived(num *models.PhoneNumber, at *time.Time) error {
+	in := awsdynamodb.UpdateItemInput{
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"phone_number": getStringAttribute(num.Number),
+		},
+		TableName: aws.String(m.tableName()),
+		ExpressionAttributeNames: map[string]*string{
+			"#last_received_at": aws.String("last_received_at"),
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":last_received_at": getTimeAttribute(at),
+		},
+		UpdateExpression: aws.String("SET #last_received_at = :last_received_at"),
+	}
+
+	if _, err := m.svc.UpdateItem(&in); err != nil {
+		logger.WithError(err).Errorf("failed to update received phone number in DynamoDB")
+		return err
+	} else {
+		num.LastReceivedAt = at
+	}
+
+	return nil
+}
+
+func (m dynamodbPhoneNumberManager) Resubscribe(num *models.PhoneNumber, at *time.Time) error {
+	updated := *num
+
+	if err := updated.Transition(models.StatusActive, at); err != nil {
+		return err
+	}
+
+	newDeadline := clock.RescheduleDeadline(num.LastSentAt, at, MustLoadLocation(num.Timezone), num.DeliverySchedule())
 
 	in := awsdynamodb.UpdateItemInput{
 		Key: map[string]*awsdynamodb.AttributeValue{
@@ -232,19 +304,40 @@ func (m dynamodbPhoneNumberManager) UpdateSkipped(num *models.PhoneNumber, sentA
 		},
 		TableName: aws.String(m.tableName()),
 		ExpressionAttributeNames: map[string]*string{
-			"#send_deadline": aws.String("send_deadline"),
+			"#phone_number":      aws.String("phone_number"),
+			"#timezone":          aws.String("timezone"),
+			"#timezone_guessed":  aws.String("timezone_guessed"),
+			"#status":            aws.String("status"),
+			"#status_changed_at": aws.String("status_changed_at"),
+			"#resubscribed_at":   aws.String("resubscribed_at"),
+			"#send_deadline":     aws.String("send_deadline"),
+			"#expires_at":        aws.String("expires_at"),
+			"#paused_until":      aws.String("paused_until"),
 		},
 		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
-			":send_deadline": getTimeAttribute(newDeadline),
+			":timezone":          getStringAttribute(num.Timezone),
+			":timezone_guessed":  getBoolAttribute(num.TimezoneGuessed),
+			":status":            getStringAttribute(string(updated.Status)),
+			":previous_status":   getStringAttribute(string(num.Status)),
+			":status_changed_at": getStatusChangedAtAttribute(updated.StatusChangedAt),
+			":resubscribed_at":   getTimeAttribute(at),
+			":send_deadline":     getTimeAttribute(newDeadline),
 		},
-		UpdateExpression: aws.String("SET #send_deadline = :send_deadline"),
+		ConditionExpression: aws.String(existsCondition + " AND " + statusUnchangedCondition),
+		UpdateExpression:    aws.String("SET #timezone = :timezone, #timezone_guessed = :timezone_guessed, #status = :status, #status_changed_at = :status_changed_at, #resubscribed_at = :resubscribed_at, #send_deadline = :send_deadline REMOVE #expires_at, #paused_until"),
 	}
 
 	if _, err := m.svc.UpdateItem(&in); err != nil {
-		logger.WithError(err).Errorf("failed to update skipped phone number in DynamoDB")
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ConditionalCheckFailedException" {
+			return m.conditionFailed(num.Number)
+		}
+
+		logger.WithError(err).Errorf("failed to resubscribe phone number in DynamoDB")
 		return err
 	} else {
-		num.SendDeadline = newDeadline
+		updated.ResubscribedAt = at
+		updated.SendDeadline = newDeadline
+		*num = updated
 	}
 
 	return nil
@@ -256,20 +349,57 @@ func MustLoadLocation(str string) *time.Location {
 }
 
 func serializePhoneNumber(num models.PhoneNumber) map[string]*awsdynamodb.AttributeValue {
-	return map[string]*awsdynamodb.AttributeValue{
-		"phone_number":  getStringAttribute(num.Number),
-		"timezone":      getStringAttribute(num.Timezone),
-		"last_sent_at":  getTimeAttribute(num.LastSentAt),
-		"is_sendable":   getBoolAttribute(num.IsSendable),
-		"send_deadline": getTimeAttribute(num.SendDeadline),
+	attrs := map[string]*awsdynamodb.AttributeValue{
+		"phone_number":      getStringAttribute(num.Number),
+		"timezone":          getStringAttribute(num.Timezone),
+		"last_sent_at":      getTimeAttribute(num.LastSentAt),
+		"status":            getStringAttribute(string(num.Status)),
+		"status_changed_at": getStatusChangedAtAttribute(num.StatusChangedAt),
+		"send_deadline":     getTimeAttribute(num.SendDeadline),
+		"timezone_guessed":  getBoolAttribute(num.TimezoneGuessed),
+		"resubscribed_at":   getTimeAttribute(num.ResubscribedAt),
+	}
+
+	// This is the table's TTL attribute so it has to be left off entirely
+	// unless we actually want the record to expire.
+	if num.ConfirmationExpiresAt != nil {
+		attrs["expires_at"] = getTimeAttribute(num.ConfirmationExpiresAt)
+	}
+
+	if num.PausedUntil != nil {
+		attrs["paused_until"] = getTimeAttribute(num.PausedUntil)
+	}
+
+	if num.Schedule != nil {
+		attrs["delivery_time"] = getStringAttribute(clock.FormatTimeOfDay(num.Schedule.Minute))
+		attrs["delivery_days"] = getIntAttribute(int(num.Schedule.Days))
+	}
+
+	// Everything from before there were channels is SMS.
+	if num.Channel != "" && num.Channel != models.ChannelSMS {
+		attrs["channel"] = getStringAttribute(string(num.Channel))
+		attrs["address"] = getStringAttribute(num.Address)
 	}
+
+	if num.WebhookSecret != "" {
+		attrs["webhook_secret"] = getStringAttribute(num.WebhookSecret)
+	}
+
+	if num.LastReceivedAt != nil {
+		attrs["last_received_at"] = getTimeAttribute(num.LastReceivedAt)
+	}
+
+	return attrs
 }
 
 func (m dynamodbPhoneNumberManager) Create(num models.PhoneNumber) error {
 	in := awsdynamodb.PutItemInput{
 		TableName:           aws.String(m.tableName()),
 		Item:                serializePhoneNumber(num),
-		ConditionExpression: aws.String("attribute_not_exists(phone_number)"),
+		ConditionExpression: aws.String("attribute_not_exists(phone_number) OR expires_at <= :now"),
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":now": getTimeAttribute(clock.Clock()),
+		},
 	}
 
 	if _, err := m.svc.PutItem(&in); err != nil {
@@ -303,6 +433,27 @@ func (m dynamodbManagers) PhoneNumbers() managers.PhoneNumberManager {
 	}
 }
 
+func (m dynamodbManagers) Verifications() managers.VerificationManager {
+	return &dynamodbVerificationManager{
+		tablePrefix: m.tablePrefix,
+		svc:         m.svc,
+	}
+}
+
+func (m dynamodbManagers) Events() managers.EventManager {
+	return &dynamodbEventManager{
+		tablePrefix: m.tablePrefix,
+		svc:         m.svc,
+	}
+}
+
+func (m dynamodbManagers) Nonces() managers.NonceManager {
+	return &dynamodbNonceManager{
+		tablePrefix: m.tablePrefix,
+		svc:         m.svc,
+	}
+}
+
 func New(tablePrefix string) managers.Managers {
 	sess := newAWSSession()
 	svc := awsdynamodb.New(sess)
@@ -0,0 +1,112 @@
+package dynamodb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+)
+
+type dynamodbEventManager struct {
+	tablePrefix string
+	svc         *awsdynamodb.DynamoDB
+}
+
+func (m dynamodbEventManager) tableName() string {
+	return m.tablePrefix + "-Events"
+}
+
+// newEventID makes a sort key that orders events by when they happened. The
+// random suffix keeps two events in the same instant from colliding.
+func newEventID(event models.Event) string {
+	buf := make([]byte, 4)
+	rand.Read(buf)
+
+	return fmt.Sprintf("%020d-%s", event.At.UnixNano(), hex.EncodeToString(buf))
+}
+
+func deserializeEvent(attrs map[string]*awsdynamodb.AttributeValue) (event models.Event) {
+	event.Number = getString("phone_number", attrs)
+	event.Type = models.EventType(getString("type", attrs))
+	event.At = *getTime("at", attrs)
+	event.Actor = getString("actor", attrs)
+
+	if val, ok := attrs["metadata"]; ok && len(val.M) > 0 {
+		event.Metadata = make(map[string]string, len(val.M))
+
+		for k := range val.M {
+			event.Metadata[k] = getString(k, val.M)
+		}
+	}
+
+	return
+}
+
+func serializeEvent(event models.Event) map[string]*awsdynamodb.AttributeValue {
+	metadata := awsdynamodb.AttributeValue{
+		M: make(map[string]*awsdynamodb.AttributeValue, len(event.Metadata)),
+	}
+
+	for k, v := range event.Metadata {
+		metadata.M[k] = getStringAttribute(v)
+	}
+
+	return map[string]*awsdynamodb.AttributeValue{
+		"phone_number": getStringAttribute(event.Number),
+		"event_id":     getStringAttribute(newEventID(event)),
+		"type":         getStringAttribute(string(event.Type)),
+		"at":           getTimeAttribute(&event.At),
+		"actor":        getStringAttribute(event.Actor),
+		"metadata":     &metadata,
+	}
+}
+
+func (m dynamodbEventManager) Append(event models.Event) error {
+	in := awsdynamodb.PutItemInput{
+		TableName: aws.String(m.tableName()),
+		Item:      serializeEvent(event),
+
+		// History is append-only.
+		ConditionExpression: aws.String("attribute_not_exists(event_id)"),
+	}
+
+	if _, err := m.svc.PutItem(&in); err != nil {
+		logger.WithError(err).Error("failed to put event in DynamoDB")
+		return err
+	}
+
+	return nil
+}
+
+func (m dynamodbEventManager) List(num string, limit int) ([]models.Event, error) {
+	in := awsdynamodb.QueryInput{
+		TableName: aws.String(m.tableName()),
+		ExpressionAttributeNames: map[string]*string{
+			"#phone_number": aws.String("phone_number"),
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":phone_number": getStringAttribute(num),
+		},
+		KeyConditionExpression: aws.String("#phone_number = :phone_number"),
+		ScanIndexForward:       aws.Bool(false),
+		Limit:                  aws.Int64(int64(limit)),
+	}
+
+	out, err := m.svc.Query(&in)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to query events in DynamoDB")
+		return nil, err
+	}
+
+	events := make([]models.Event, len(out.Items))
+
+	for i, item := range out.Items {
+		events[i] = deserializeEvent(item)
+	}
+
+	return events, nil
+}
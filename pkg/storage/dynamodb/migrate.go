@@ -0,0 +1,99 @@
+package dynamodb
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+)
+
+// Migrate gives every phone number written before we had statuses a status of
+// its own, derived the same way getStatus derives it on the fly, and drops the
+// attributes that status replaces. It's safe to run more than once.
+func Migrate(tablePrefix string) error {
+	m := dynamodbPhoneNumberManager{
+		tablePrefix: tablePrefix,
+		svc:         awsdynamodb.New(newAWSSession()),
+	}
+
+	return m.migrateStatuses(clock.Clock())
+}
+
+func (m dynamodbPhoneNumberManager) migrateStatuses(now *time.Time) error {
+	var migrated, failed int
+
+	in := awsdynamodb.ScanInput{
+		TableName: aws.String(m.tableName()),
+		ExpressionAttributeNames: map[string]*string{
+			"#status": aws.String("status"),
+		},
+		FilterExpression: aws.String("attribute_not_exists(#status)"),
+	}
+
+	err := m.svc.ScanPages(&in, func(out *awsdynamodb.ScanOutput, last bool) bool {
+		for _, item := range out.Items {
+			if err := m.migrateStatus(item, now); err != nil {
+				logger.WithError(err).Error("failed to migrate phone number")
+				failed++
+			} else {
+				migrated++
+			}
+		}
+
+		return true
+	})
+
+	if err != nil {
+		logger.WithError(err).Error("failed to scan for phone numbers to migrate")
+		return err
+	}
+
+	logger.Infof("migrated %d phone numbers, %d failed", migrated, failed)
+	return nil
+}
+
+func (m dynamodbPhoneNumberManager) migrateStatus(item map[string]*awsdynamodb.AttributeValue, now *time.Time) error {
+	status := legacyStatus(item)
+
+	// Confirmed numbers know when they became active.
+	changedAt := now
+
+	if status == models.StatusActive {
+		if confirmedAt := getOptionalTime("confirmed_at", item); confirmedAt != nil {
+			changedAt = confirmedAt
+		}
+	}
+
+	in := awsdynamodb.UpdateItemInput{
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"phone_number": item["phone_number"],
+		},
+		TableName: aws.String(m.tableName()),
+		ExpressionAttributeNames: map[string]*string{
+			"#status":            aws.String("status"),
+			"#status_changed_at": aws.String("status_changed_at"),
+			"#is_sendable":       aws.String("is_sendable"),
+			"#confirmed_at":      aws.String("confirmed_at"),
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":status":            getStringAttribute(string(status)),
+			":status_changed_at": getStatusChangedAtAttribute(map[models.Status]time.Time{status: *changedAt}),
+		},
+		ConditionExpression: aws.String("attribute_not_exists(#status)"),
+		UpdateExpression:    aws.String("SET #status = :status, #status_changed_at = :status_changed_at REMOVE #is_sendable, #confirmed_at"),
+	}
+
+	if _, err := m.svc.UpdateItem(&in); err != nil {
+		// Someone else got to it first, which is just as good.
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ConditionalCheckFailedException" {
+			return nil
+		}
+
+		return err
+	}
+
+	return nil
+}
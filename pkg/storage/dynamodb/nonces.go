@@ -0,0 +1,67 @@
+package dynamodb
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+type dynamodbNonceManager struct {
+	tablePrefix string
+	svc         *awsdynamodb.DynamoDB
+}
+
+func (m dynamodbNonceManager) tableName() string {
+	return m.tablePrefix + "-Nonces"
+}
+
+func (m dynamodbNonceManager) Use(nonce string, expiresAt *time.Time) error {
+	in := awsdynamodb.PutItemInput{
+		TableName: aws.String(m.tableName()),
+		Item: map[string]*awsdynamodb.AttributeValue{
+			"nonce":      getStringAttribute(nonce),
+			"expires_at": getTimeAttribute(expiresAt),
+		},
+		ConditionExpression: aws.String("attribute_not_exists(nonce)"),
+	}
+
+	if _, err := m.svc.PutItem(&in); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ConditionalCheckFailedException" {
+			return managers.ErrRecordExists
+		}
+
+		logger.WithError(err).Error("failed to put nonce in DynamoDB")
+		return err
+	}
+
+	return nil
+}
+
+func (m dynamodbNonceManager) Used(nonce string) (bool, error) {
+	in := awsdynamodb.GetItemInput{
+		TableName: aws.String(m.tableName()),
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"nonce": getStringAttribute(nonce),
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	out, err := m.svc.GetItem(&in)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to get nonce in DynamoDB")
+		return false, err
+	}
+
+	if len(out.Item) == 0 {
+		return false, nil
+	}
+
+	// DynamoDB can take a while to get around to deleting expired items.
+	expiresAt := getOptionalTime("expires_at", out.Item)
+	return expiresAt == nil || clock.Clock().Before(*expiresAt), nil
+}
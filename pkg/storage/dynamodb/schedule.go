@@ -0,0 +1,62 @@
+package dynamodb
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+)
+
+// getSchedule reads the delivery schedule off of an item. Items that never had
+// their schedule changed don't have one.
+func getSchedule(attrs map[string]*awsdynamodb.AttributeValue) *clock.Schedule {
+	if _, ok := attrs["delivery_time"]; !ok {
+		return nil
+	}
+
+	minute, err := clock.ParseTimeOfDay(getString("delivery_time", attrs))
+
+	if err != nil {
+		logger.WithError(err).Warn("ignoring invalid delivery time")
+		return nil
+	}
+
+	return &clock.Schedule{
+		Minute: minute,
+		Days:   clock.Weekdays(getInt("delivery_days", attrs)),
+	}
+}
+
+func (m dynamodbPhoneNumberManager) UpdateSchedule(num *models.PhoneNumber, schedule clock.Schedule, now *time.Time) error {
+	newDeadline := clock.RescheduleDeadline(num.LastSentAt, now, MustLoadLocation(num.Timezone), schedule)
+
+	in := awsdynamodb.UpdateItemInput{
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"phone_number": getStringAttribute(num.Number),
+		},
+		TableName: aws.String(m.tableName()),
+		ExpressionAttributeNames: map[string]*string{
+			"#delivery_time": aws.String("delivery_time"),
+			"#delivery_days": aws.String("delivery_days"),
+			"#send_deadline": aws.String("send_deadline"),
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":delivery_time": getStringAttribute(clock.FormatTimeOfDay(schedule.Minute)),
+			":delivery_days": getIntAttribute(int(schedule.Days)),
+			":send_deadline": getTimeAttribute(newDeadline),
+		},
+		UpdateExpression: aws.String("SET #delivery_time = :delivery_time, #delivery_days = :delivery_days, #send_deadline = :send_deadline"),
+	}
+
+	if _, err := m.svc.UpdateItem(&in); err != nil {
+		logger.WithError(err).Errorf("failed to update schedule for phone number in DynamoDB")
+		return err
+	} else {
+		num.Schedule = &schedule
+		num.SendDeadline = newDeadline
+	}
+
+	return nil
+}
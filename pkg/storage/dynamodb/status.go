@@ -0,0 +1,197 @@
+package dynamodb
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+// statusUnchangedCondition makes sure nobody changed the status out from
+// under us. Items that predate statuses don't have one at all.
+const statusUnchangedCondition = "(#status = :previous_status OR attribute_not_exists(#status))"
+
+// existsCondition keeps updates from making an item for a number that isn't
+// there, which UpdateItem would otherwise do.
+const existsCondition = "attribute_exists(#phone_number)"
+
+// conditionFailed works out which condition an update of num failed, since
+// DynamoDB doesn't say.
+func (m dynamodbPhoneNumberManager) conditionFailed(num string) error {
+	if _, err := m.Get(num); err != nil {
+		return err
+	}
+
+	return managers.ErrRecordChanged
+}
+
+// getStatus reads the status off of an item. Items written before we had
+// statuses only have is_sendable, and possibly expires_at.
+func getStatus(attrs map[string]*awsdynamodb.AttributeValue) models.Status {
+	if status := models.Status(getString("status", attrs)); status != "" {
+		return status
+	}
+
+	return legacyStatus(attrs)
+}
+
+func legacyStatus(attrs map[string]*awsdynamodb.AttributeValue) models.Status {
+	if _, ok := attrs["expires_at"]; ok {
+		return models.StatusPending
+	}
+
+	if getBool("is_sendable", attrs) {
+		return models.StatusActive
+	}
+
+	return models.StatusStopped
+}
+
+func getStatusChangedAt(name string, attrs map[string]*awsdynamodb.AttributeValue) map[models.Status]time.Time {
+	val, ok := attrs[name]
+
+	if !ok || len(val.M) == 0 {
+		return nil
+	}
+
+	changedAt := make(map[models.Status]time.Time, len(val.M))
+
+	for status := range val.M {
+		changedAt[models.Status(status)] = *getTime(status, val.M)
+	}
+
+	return changedAt
+}
+
+func getStatusChangedAtAttribute(changedAt map[models.Status]time.Time) *awsdynamodb.AttributeValue {
+	var attr awsdynamodb.AttributeValue
+	attr.M = make(map[string]*awsdynamodb.AttributeValue, len(changedAt))
+
+	for status, t := range changedAt {
+		t := t
+		attr.M[string(status)] = getTimeAttribute(&t)
+	}
+
+	return &attr
+}
+
+func (m dynamodbPhoneNumberManager) Pause(num *models.PhoneNumber, until, at *time.Time) error {
+	updated := *num
+
+	if err := updated.Pause(until, at); err != nil {
+		return err
+	}
+
+	names := map[string]*string{
+		"#phone_number":      aws.String("phone_number"),
+		"#status":            aws.String("status"),
+		"#status_changed_at": aws.String("status_changed_at"),
+		"#paused_until":      aws.String("paused_until"),
+	}
+
+	values := map[string]*awsdynamodb.AttributeValue{
+		":status":            getStringAttribute(string(updated.Status)),
+		":previous_status":   getStringAttribute(string(num.Status)),
+		":status_changed_at": getStatusChangedAtAttribute(updated.StatusChangedAt),
+	}
+
+	expr := "SET #status = :status, #status_changed_at = :status_changed_at"
+
+	if until != nil {
+		values[":paused_until"] = getTimeAttribute(until)
+		expr += ", #paused_until = :paused_until"
+	} else {
+		expr += " REMOVE #paused_until"
+	}
+
+	in := awsdynamodb.UpdateItemInput{
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"phone_number": getStringAttribute(num.Number),
+		},
+		TableName:                 aws.String(m.tableName()),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ConditionExpression:       aws.String(existsCondition + " AND " + statusUnchangedCondition),
+		UpdateExpression:          aws.String(expr),
+	}
+
+	if _, err := m.svc.UpdateItem(&in); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ConditionalCheckFailedException" {
+			return m.conditionFailed(num.Number)
+		}
+
+		logger.WithError(err).Errorf("failed to pause phone number in DynamoDB")
+		return err
+	}
+
+	*num = updated
+	return nil
+}
+
+func (m dynamodbPhoneNumberManager) UpdateStatus(num *models.PhoneNumber, status models.Status, at *time.Time) error {
+	updated := *num
+
+	if err := updated.Transition(status, at); err != nil {
+		return err
+	}
+
+	if updated.Status == num.Status {
+		return nil
+	}
+
+	names := map[string]*string{
+		"#phone_number":      aws.String("phone_number"),
+		"#status":            aws.String("status"),
+		"#status_changed_at": aws.String("status_changed_at"),
+		"#expires_at":        aws.String("expires_at"),
+		"#paused_until":      aws.String("paused_until"),
+	}
+
+	values := map[string]*awsdynamodb.AttributeValue{
+		":status":            getStringAttribute(string(updated.Status)),
+		":previous_status":   getStringAttribute(string(num.Status)),
+		":status_changed_at": getStatusChangedAtAttribute(updated.StatusChangedAt),
+	}
+
+	expr := "SET #status = :status, #status_changed_at = :status_changed_at"
+
+	if status == models.StatusActive {
+		// Coming back from anything else means today's message might still be
+		// owed to them.
+		updated.SendDeadline = clock.RescheduleDeadline(num.LastSentAt, at, MustLoadLocation(num.Timezone), num.DeliverySchedule())
+
+		names["#send_deadline"] = aws.String("send_deadline")
+		values[":send_deadline"] = getTimeAttribute(updated.SendDeadline)
+		expr += ", #send_deadline = :send_deadline"
+	}
+
+	// Only pending numbers expire, and only Pause knows when a pause is over.
+	expr += " REMOVE #expires_at, #paused_until"
+
+	in := awsdynamodb.UpdateItemInput{
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"phone_number": getStringAttribute(num.Number),
+		},
+		TableName:                 aws.String(m.tableName()),
+		ExpressionAttributeNames:  names,
+		ExpressionAttributeValues: values,
+		ConditionExpression:       aws.String(existsCondition + " AND " + statusUnchangedCondition),
+		UpdateExpression:          aws.String(expr),
+	}
+
+	if _, err := m.svc.UpdateItem(&in); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ConditionalCheckFailedException" {
+			return m.conditionFailed(num.Number)
+		}
+
+		logger.WithError(err).Errorf("failed to update status of phone number in DynamoDB")
+		return err
+	}
+
+	*num = updated
+	return nil
+}
@@ -0,0 +1,139 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+type dynamodbVerificationManager struct {
+	tablePrefix string
+	svc         *awsdynamodb.DynamoDB
+}
+
+func (m dynamodbVerificationManager) tableName() string {
+	return m.tablePrefix + "-Verifications"
+}
+
+func deserializeVerification(attrs map[string]*awsdynamodb.AttributeValue) (verification models.Verification) {
+	verification.Number = getString("phone_number", attrs)
+	verification.CodeHash = getString("code_hash", attrs)
+	verification.Salt = getString("salt", attrs)
+	verification.Attempts = getInt("attempts", attrs)
+	verification.ExpiresAt = getOptionalTime("expires_at", attrs)
+	verification.Timezone = getString("timezone", attrs)
+	verification.DeliveryTime = getString("delivery_time", attrs)
+	return
+}
+
+func serializeVerification(verification models.Verification) map[string]*awsdynamodb.AttributeValue {
+	attrs := map[string]*awsdynamodb.AttributeValue{
+		"phone_number": getStringAttribute(verification.Number),
+		"code_hash":    getStringAttribute(verification.CodeHash),
+		"salt":         getStringAttribute(verification.Salt),
+		"attempts":     getIntAttribute(verification.Attempts),
+		"expires_at":   getTimeAttribute(verification.ExpiresAt),
+	}
+
+	// Leave off whatever the request didn't ask to change.
+	if verification.Timezone != "" {
+		attrs["timezone"] = getStringAttribute(verification.Timezone)
+	}
+
+	if verification.DeliveryTime != "" {
+		attrs["delivery_time"] = getStringAttribute(verification.DeliveryTime)
+	}
+
+	return attrs
+}
+
+func (m dynamodbVerificationManager) Put(verification models.Verification) error {
+	in := awsdynamodb.PutItemInput{
+		TableName: aws.String(m.tableName()),
+		Item:      serializeVerification(verification),
+	}
+
+	if _, err := m.svc.PutItem(&in); err != nil {
+		logger.WithError(err).Error("failed to put verification in DynamoDB")
+		return err
+	}
+
+	return nil
+}
+
+func (m dynamodbVerificationManager) Get(num string) (models.Verification, error) {
+	in := awsdynamodb.GetItemInput{
+		TableName: aws.String(m.tableName()),
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"phone_number": getStringAttribute(num),
+		},
+		ConsistentRead: aws.Bool(true),
+	}
+
+	if out, err := m.svc.GetItem(&in); err != nil {
+		logger.WithError(err).Errorf("failed to get verification in DynamoDB")
+		return models.Verification{}, err
+	} else if len(out.Item) == 0 {
+		return models.Verification{}, managers.ErrRecordNotFound
+	} else if verification := deserializeVerification(out.Item); verification.IsExpired(clock.Clock()) {
+		// DynamoDB can take a while to get around to deleting expired items.
+		return models.Verification{}, managers.ErrRecordNotFound
+	} else {
+		return verification, nil
+	}
+}
+
+func (m dynamodbVerificationManager) AddAttempt(num string, max int) error {
+	// Counting in DynamoDB means that guesses made at the same time can't all
+	// see the same count and get past the limit together.
+	in := awsdynamodb.UpdateItemInput{
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"phone_number": getStringAttribute(num),
+		},
+		TableName: aws.String(m.tableName()),
+		ExpressionAttributeNames: map[string]*string{
+			"#phone_number": aws.String("phone_number"),
+			"#attempts":     aws.String("attempts"),
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":one": getIntAttribute(1),
+			":max": getIntAttribute(max),
+		},
+		ConditionExpression: aws.String("attribute_exists(#phone_number) AND (#attempts < :max OR attribute_not_exists(#attempts))"),
+		UpdateExpression:    aws.String("ADD #attempts :one"),
+	}
+
+	if _, err := m.svc.UpdateItem(&in); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ConditionalCheckFailedException" {
+			if _, err := m.Get(num); err != nil {
+				return err
+			}
+
+			return managers.ErrLimitReached
+		}
+
+		logger.WithError(err).Errorf("failed to update verification attempts in DynamoDB")
+		return err
+	}
+
+	return nil
+}
+
+func (m dynamodbVerificationManager) Delete(num string) error {
+	in := awsdynamodb.DeleteItemInput{
+		TableName: aws.String(m.tableName()),
+		Key: map[string]*awsdynamodb.AttributeValue{
+			"phone_number": getStringAttribute(num),
+		},
+	}
+
+	if _, err := m.svc.DeleteItem(&in); err != nil {
+		logger.WithError(err).Errorf("failed to delete verification in DynamoDB")
+		return err
+	}
+
+	return nil
+}
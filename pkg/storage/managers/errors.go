@@ -3,5 +3,8 @@ package managers
 import "errors"
 
 var (
-	ErrRecordExists = errors.New("storage: record exists")
+	ErrRecordExists   = errors.New("storage: record exists")
+	ErrRecordNotFound = errors.New("storage: record not found")
+	ErrRecordChanged  = errors.New("storage: record changed")
+	ErrLimitReached   = errors.New("storage: limit reached")
 )
@@ -3,6 +3,7 @@ package managers
 import (
 	"time"
 
+	"github.com/bradhe/what-day-is-it/pkg/clock"
 	"github.com/bradhe/what-day-is-it/pkg/models"
 )
 
@@ -10,12 +11,54 @@ type PhoneNumberManager interface {
 	GetBySendDeadline(*time.Time) ([]models.PhoneNumber, error)
 	UpdateSent(*models.PhoneNumber, *time.Time) error
 	UpdateSkipped(*models.PhoneNumber, *time.Time) error
-	UpdateNotSendable(*models.PhoneNumber) error
-	UpdateSendable(*models.PhoneNumber) error
+	UpdateStatus(*models.PhoneNumber, models.Status, *time.Time) error
+
+	// Pause stops deliveries until the first time, or indefinitely if it's nil.
+	Pause(*models.PhoneNumber, *time.Time, *time.Time) error
+	UpdateTimezone(*models.PhoneNumber, string, *time.Time) error
+	UpdateSchedule(*models.PhoneNumber, clock.Schedule, *time.Time) error
+	Resubscribe(*models.PhoneNumber, *time.Time) error
+
+	// UpdateReceived records when the subscriber last messaged us.
+	UpdateReceived(*models.PhoneNumber, *time.Time) error
 	Create(models.PhoneNumber) error
 	Get(string) (models.PhoneNumber, error)
 }
 
+type VerificationManager interface {
+	// Put stores the verification, replacing any outstanding one for the same
+	// phone number.
+	Put(models.Verification) error
+	Get(string) (models.Verification, error)
+
+	// AddAttempt counts a guess at the verification's code, unless it has
+	// already had max of them, in which case it's ErrLimitReached.
+	AddAttempt(num string, max int) error
+
+	Delete(string) error
+}
+
+type EventManager interface {
+	// Append adds the event to the end of its phone number's history.
+	Append(models.Event) error
+
+	// List returns up to limit of a phone number's most recent events, newest
+	// first.
+	List(string, int) ([]models.Event, error)
+}
+
+type NonceManager interface {
+	// Use records that the nonce has been spent. It returns ErrRecordExists if
+	// it already was. The record can be forgotten after expiresAt.
+	Use(string, *time.Time) error
+
+	// Used indicates that the nonce has been spent.
+	Used(string) (bool, error)
+}
+
 type Managers interface {
 	PhoneNumbers() PhoneNumberManager
+	Verifications() VerificationManager
+	Events() EventManager
+	Nonces() NonceManager
 }
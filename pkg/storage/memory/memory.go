@@ -0,0 +1,298 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/models"
+	"github.com/bradhe/what-day-is-it/pkg/storage/managers"
+)
+
+// memoryPhoneNumberManager keeps phone numbers in a map. It's meant for tests
+// and local development--nothing survives a restart.
+type memoryPhoneNumberManager struct {
+	sync.Mutex
+	numbers map[string]models.PhoneNumber
+}
+
+func (m *memoryPhoneNumberManager) GetBySendDeadline(deadline *time.Time) ([]models.PhoneNumber, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var out []models.PhoneNumber
+
+	for _, num := range m.numbers {
+		if num.SendDeadline == nil || num.SendDeadline.Before(*deadline) {
+			out = append(out, num)
+		}
+	}
+
+	return out, nil
+}
+
+func (m *memoryPhoneNumberManager) Get(num string) (models.PhoneNumber, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if phoneNumber, ok := m.numbers[num]; ok && !phoneNumber.IsExpired(clock.Clock()) {
+		return phoneNumber, nil
+	}
+
+	return models.PhoneNumber{}, managers.ErrRecordNotFound
+}
+
+// update applies fn to the stored copy of num, if there is one, and then
+// copies the result back in to num. Nothing is saved if fn fails.
+func (m *memoryPhoneNumberManager) update(num *models.PhoneNumber, fn func(*models.PhoneNumber) error) error {
+	m.Lock()
+	defer m.Unlock()
+
+	stored, ok := m.numbers[num.Number]
+
+	if !ok {
+		return managers.ErrRecordNotFound
+	}
+
+	if err := fn(&stored); err != nil {
+		return err
+	}
+
+	m.numbers[num.Number] = stored
+	*num = stored
+
+	return nil
+}
+
+func (m *memoryPhoneNumberManager) UpdateSent(num *models.PhoneNumber, sentAt *time.Time) error {
+	return m.update(num, func(stored *models.PhoneNumber) error {
+		stored.LastSentAt = sentAt
+		stored.SendDeadline = clock.NextDeadline(sentAt, clock.MustLoadLocation(stored.Timezone), stored.DeliverySchedule())
+		return nil
+	})
+}
+
+func (m *memoryPhoneNumberManager) UpdateSkipped(num *models.PhoneNumber, sentAt *time.Time) error {
+	return m.update(num, func(stored *models.PhoneNumber) error {
+		stored.SendDeadline = clock.NextDeadline(sentAt, clock.MustLoadLocation(stored.Timezone), stored.DeliverySchedule())
+		return nil
+	})
+}
+
+func (m *memoryPhoneNumberManager) UpdateStatus(num *models.PhoneNumber, status models.Status, at *time.Time) error {
+	return m.update(num, func(stored *models.PhoneNumber) error {
+		if err := stored.Transition(status, at); err != nil {
+			return err
+		}
+
+		if status == models.StatusActive {
+			stored.SendDeadline = clock.RescheduleDeadline(stored.LastSentAt, at, clock.MustLoadLocation(stored.Timezone), stored.DeliverySchedule())
+		}
+
+		return nil
+	})
+}
+
+func (m *memoryPhoneNumberManager) Pause(num *models.PhoneNumber, until, at *time.Time) error {
+	return m.update(num, func(stored *models.PhoneNumber) error {
+		return stored.Pause(until, at)
+	})
+}
+
+func (m *memoryPhoneNumberManager) UpdateTimezone(num *models.PhoneNumber, timezone string, now *time.Time) error {
+	return m.update(num, func(stored *models.PhoneNumber) error {
+		stored.Timezone = timezone
+		stored.TimezoneGuessed = false
+		stored.SendDeadline = clock.RescheduleDeadline(stored.LastSentAt, now, clock.MustLoadLocation(timezone), stored.DeliverySchedule())
+		return nil
+	})
+}
+
+func (m *memoryPhoneNumberManager) UpdateSchedule(num *models.PhoneNumber, schedule clock.Schedule, now *time.Time) error {
+	return m.update(num, func(stored *models.PhoneNumber) error {
+		stored.Schedule = &schedule
+		stored.SendDeadline = clock.RescheduleDeadline(stored.LastSentAt, now, clock.MustLoadLocation(stored.Timezone), schedule)
+		return nil
+	})
+}
+
+func (m *memoryPhoneNumberManager) Resubscribe(num *models.PhoneNumber, at *time.Time) error {
+	timezone, guessed := num.Timezone, num.TimezoneGuessed
+
+	return m.update(num, func(stored *models.PhoneNumber) error {
+		if err := stored.Transition(models.StatusActive, at); err != nil {
+			return err
+		}
+
+		stored.Timezone = timezone
+		stored.TimezoneGuessed = guessed
+		stored.ResubscribedAt = at
+		stored.SendDeadline = clock.RescheduleDeadline(stored.LastSentAt, at, clock.MustLoadLocation(timezone), stored.DeliverySchedule())
+		return nil
+	})
+}
+
+func (m *memoryPhoneNumberManager) UpdateReceived(num *models.PhoneNumber, at *time.Time) error {
+	return m.update(num, func(stored *models.PhoneNumber) error {
+		stored.LastReceivedAt = at
+		return nil
+	})
+}
+
+func (m *memoryPhoneNumberManager) Create(num models.PhoneNumber) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if existing, ok := m.numbers[num.Number]; ok && !existing.IsExpired(clock.Clock()) {
+		return managers.ErrRecordExists
+	}
+
+	m.numbers[num.Number] = num
+	return nil
+}
+
+type memoryVerificationManager struct {
+	sync.Mutex
+	verifications map[string]models.Verification
+}
+
+func (m *memoryVerificationManager) Put(verification models.Verification) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.verifications[verification.Number] = verification
+	return nil
+}
+
+func (m *memoryVerificationManager) Get(num string) (models.Verification, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if verification, ok := m.verifications[num]; ok && !verification.IsExpired(clock.Clock()) {
+		return verification, nil
+	}
+
+	return models.Verification{}, managers.ErrRecordNotFound
+}
+
+func (m *memoryVerificationManager) AddAttempt(num string, max int) error {
+	m.Lock()
+	defer m.Unlock()
+
+	stored, ok := m.verifications[num]
+
+	if !ok || stored.IsExpired(clock.Clock()) {
+		return managers.ErrRecordNotFound
+	}
+
+	if stored.Attempts >= max {
+		return managers.ErrLimitReached
+	}
+
+	stored.Attempts++
+	m.verifications[num] = stored
+
+	return nil
+}
+
+func (m *memoryVerificationManager) Delete(num string) error {
+	m.Lock()
+	defer m.Unlock()
+
+	delete(m.verifications, num)
+	return nil
+}
+
+type memoryEventManager struct {
+	sync.Mutex
+	events map[string][]models.Event
+}
+
+func (m *memoryEventManager) Append(event models.Event) error {
+	m.Lock()
+	defer m.Unlock()
+
+	m.events[event.Number] = append(m.events[event.Number], event)
+	return nil
+}
+
+func (m *memoryEventManager) List(num string, limit int) ([]models.Event, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	events := m.events[num]
+	out := make([]models.Event, 0, len(events))
+
+	for i := len(events) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, events[i])
+	}
+
+	return out, nil
+}
+
+type memoryNonceManager struct {
+	sync.Mutex
+	nonces map[string]time.Time
+}
+
+func (m *memoryNonceManager) Use(nonce string, expiresAt *time.Time) error {
+	m.Lock()
+	defer m.Unlock()
+
+	now := clock.Clock()
+
+	if existing, ok := m.nonces[nonce]; ok && now.Before(existing) {
+		return managers.ErrRecordExists
+	}
+
+	m.nonces[nonce] = *expiresAt
+	return nil
+}
+
+func (m *memoryNonceManager) Used(nonce string) (bool, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	existing, ok := m.nonces[nonce]
+	return ok && clock.Clock().Before(existing), nil
+}
+
+type memoryManagers struct {
+	phoneNumbers  *memoryPhoneNumberManager
+	verifications *memoryVerificationManager
+	events        *memoryEventManager
+	nonces        *memoryNonceManager
+}
+
+func (m *memoryManagers) PhoneNumbers() managers.PhoneNumberManager {
+	return m.phoneNumbers
+}
+
+func (m *memoryManagers) Verifications() managers.VerificationManager {
+	return m.verifications
+}
+
+func (m *memoryManagers) Events() managers.EventManager {
+	return m.events
+}
+
+func (m *memoryManagers) Nonces() managers.NonceManager {
+	return m.nonces
+}
+
+func New() managers.Managers {
+	return &memoryManagers{
+		phoneNumbers: &memoryPhoneNumberManager{
+			numbers: make(map[string]models.PhoneNumber),
+		},
+		verifications: &memoryVerificationManager{
+			verifications: make(map[string]models.Verification),
+		},
+		events: &memoryEventManager{
+			events: make(map[string][]models.Event),
+		},
+		nonces: &memoryNonceManager{
+			nonces: make(map[string]time.Time),
+		},
+	}
+}
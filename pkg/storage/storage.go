@@ -8,3 +8,8 @@ import (
 func New(tablePrefix string) managers.Managers {
 	return dynamodb.New(tablePrefix)
 }
+
+// Migrate brings the data in the stack up to date with the current models.
+func Migrate(tablePrefix string) error {
+	return dynamodb.Migrate(tablePrefix)
+}
@@ -0,0 +1,194 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/logs"
+)
+
+var logger = logs.WithPackage("telegram")
+
+// DefaultBaseURL is where the Bot API lives.
+const DefaultBaseURL = "https://api.telegram.org"
+
+// How long Telegram has to answer anything that isn't a long poll.
+const requestTimeout = 10 * time.Second
+
+// Update is something that happened to the bot, like a message in a chat.
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+
+	// One of private, group, supergroup or channel.
+	Type string `json:"type"`
+}
+
+// Error is the Bot API refusing a request.
+type Error struct {
+	Code        int    `json:"error_code"`
+	Description string `json:"description"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("telegram: %s (code %d)", e.Description, e.Code)
+}
+
+// Undeliverable indicates that the chat can't get messages from us at all,
+// like when it's blocked the bot, as opposed to something going wrong this
+// time around.
+func (e *Error) Undeliverable() bool {
+	return e.Code == http.StatusForbidden || (e.Code == http.StatusBadRequest && strings.Contains(e.Description, "chat not found"))
+}
+
+type response struct {
+	OK     bool            `json:"ok"`
+	Result json.RawMessage `json:"result"`
+	Error
+}
+
+// Bot talks to the Bot API on behalf of a bot.
+type Bot struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func NewBot(token string) *Bot {
+	return NewBotAt(DefaultBaseURL, token)
+}
+
+// NewBotAt is NewBot for a Bot API server somewhere other than Telegram, like
+// a local one.
+func NewBotAt(baseURL, token string) *Bot {
+	return &Bot{
+		token:   token,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		client:  &http.Client{},
+	}
+}
+
+// call runs method with params and decodes its result in to out, which can be
+// nil if we don't care.
+func (b *Bot) call(ctx context.Context, method string, params, out interface{}) error {
+	buf, _ := json.Marshal(params)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.baseURL+"/bot"+b.token+"/"+method, bytes.NewReader(buf))
+
+	if err != nil {
+		return redactURL(method, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.client.Do(req)
+
+	if err != nil {
+		return redactURL(method, err)
+	}
+
+	defer resp.Body.Close()
+
+	var body response
+
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("telegram: invalid response to %s: %s", method, resp.Status)
+	}
+
+	if !body.OK {
+		if body.Code == 0 {
+			body.Code = resp.StatusCode
+		}
+
+		return &body.Error
+	}
+
+	if out != nil {
+		return json.Unmarshal(body.Result, out)
+	}
+
+	return nil
+}
+
+// redactURL leaves the URL out of err, since the token is in it and errors end
+// up in logs and event history.
+func redactURL(method string, err error) error {
+	if uerr, ok := err.(*url.Error); ok {
+		return fmt.Errorf("telegram: %s failed: %v", method, uerr.Err)
+	}
+
+	return err
+}
+
+func (b *Bot) callWithTimeout(method string, params, out interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+
+	return b.call(ctx, method, params, out)
+}
+
+// Send sends body to the chat whose ID is to.
+func (b *Bot) Send(to, body string) error {
+	err := b.callWithTimeout("sendMessage", map[string]string{
+		"chat_id": to,
+		"text":    body,
+	}, nil)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to send message to Telegram")
+		return err
+	}
+
+	logger.Info("message sent to Telegram")
+	return nil
+}
+
+// GetUpdates returns the updates after offset, waiting up to timeout for one
+// to turn up if there aren't any yet.
+func (b *Bot) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout+requestTimeout)
+	defer cancel()
+
+	var updates []Update
+
+	err := b.call(ctx, "getUpdates", map[string]interface{}{
+		"offset":          offset,
+		"timeout":         int(timeout / time.Second),
+		"allowed_updates": []string{"message"},
+	}, &updates)
+
+	return updates, err
+}
+
+// SetWebhook has Telegram post updates to url instead of us polling for them.
+// Each one comes with secretToken in the X-Telegram-Bot-Api-Secret-Token
+// header.
+func (b *Bot) SetWebhook(url, secretToken string) error {
+	return b.callWithTimeout("setWebhook", map[string]interface{}{
+		"url":             url,
+		"secret_token":    secretToken,
+		"allowed_updates": []string{"message"},
+	}, nil)
+}
+
+// DeleteWebhook goes back to polling. Telegram won't answer getUpdates while
+// there's a webhook.
+func (b *Bot) DeleteWebhook() error {
+	return b.callWithTimeout("deleteWebhook", map[string]interface{}{}, nil)
+}
@@ -0,0 +1,226 @@
+package telegram
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testToken = "123:abc"
+
+// fakeBotAPI is just enough of the Bot API to talk to.
+type fakeBotAPI struct {
+	sync.Mutex
+	*httptest.Server
+
+	sent    []map[string]interface{}
+	updates []Update
+	webhook map[string]interface{}
+
+	// How many getUpdates calls to fail before answering them.
+	failures int
+
+	// Error answers every sendMessage if it's set.
+	sendError *Error
+}
+
+func newFakeBotAPI() *fakeBotAPI {
+	api := &fakeBotAPI{}
+	api.Server = httptest.NewServer(http.HandlerFunc(api.serve))
+	return api
+}
+
+func (api *fakeBotAPI) bot() *Bot {
+	return NewBotAt(api.URL, testToken)
+}
+
+func (api *fakeBotAPI) reply(w http.ResponseWriter, result interface{}) {
+	buf, _ := json.Marshal(result)
+	json.NewEncoder(w).Encode(response{OK: true, Result: buf})
+}
+
+func (api *fakeBotAPI) fail(w http.ResponseWriter, e Error) {
+	w.WriteHeader(e.Code)
+	json.NewEncoder(w).Encode(response{Error: e})
+}
+
+func (api *fakeBotAPI) serve(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/bot"+testToken+"/") {
+		api.fail(w, Error{http.StatusUnauthorized, "Unauthorized"})
+		return
+	}
+
+	var params map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&params)
+
+	api.Lock()
+	defer api.Unlock()
+
+	switch strings.TrimPrefix(r.URL.Path, "/bot"+testToken+"/") {
+	case "sendMessage":
+		if api.sendError != nil {
+			api.fail(w, *api.sendError)
+			return
+		}
+
+		api.sent = append(api.sent, params)
+		api.reply(w, Message{MessageID: int64(len(api.sent))})
+	case "getUpdates":
+		if api.failures > 0 {
+			api.failures--
+			api.fail(w, Error{http.StatusBadGateway, "Bad Gateway"})
+			return
+		}
+
+		var updates []Update
+
+		for _, update := range api.updates {
+			if update.UpdateID >= int64(params["offset"].(float64)) {
+				updates = append(updates, update)
+			}
+		}
+
+		api.reply(w, updates)
+	case "setWebhook":
+		api.webhook = params
+		api.reply(w, true)
+	case "deleteWebhook":
+		api.webhook = nil
+		api.reply(w, true)
+	default:
+		api.fail(w, Error{http.StatusNotFound, "Not Found"})
+	}
+}
+
+func (api *fakeBotAPI) Sent() []map[string]interface{} {
+	api.Lock()
+	defer api.Unlock()
+
+	return append([]map[string]interface{}(nil), api.sent...)
+}
+
+func TestBotSend(t *testing.T) {
+	api := newFakeBotAPI()
+	defer api.Close()
+
+	assert.NoError(t, api.bot().Send("42", "It's Tuesday."))
+	assert.Equal(t, []map[string]interface{}{{"chat_id": "42", "text": "It's Tuesday."}}, api.Sent())
+
+	err := NewBotAt(api.URL, "nope").Send("42", "It's Tuesday.")
+	assert.Equal(t, &Error{http.StatusUnauthorized, "Unauthorized"}, err)
+}
+
+func TestBotErrorsLeaveOutToken(t *testing.T) {
+	api := newFakeBotAPI()
+	api.Close()
+
+	err := api.bot().Send("42", "It's Tuesday.")
+
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), testToken)
+		assert.Contains(t, err.Error(), "sendMessage")
+	}
+}
+
+func TestErrorUndeliverable(t *testing.T) {
+	cases := []struct {
+		err           Error
+		undeliverable bool
+	}{
+		{Error{403, "Forbidden: bot was blocked by the user"}, true},
+		{Error{400, "Bad Request: chat not found"}, true},
+		{Error{400, "Bad Request: message text is empty"}, false},
+		{Error{429, "Too Many Requests: retry after 5"}, false},
+	}
+
+	api := newFakeBotAPI()
+	defer api.Close()
+
+	for _, c := range cases {
+		api.sendError = &c.err
+
+		err := api.bot().Send("42", "It's Tuesday.")
+
+		if assert.IsType(t, &Error{}, err) {
+			assert.Equal(t, c.undeliverable, err.(*Error).Undeliverable(), c.err.Description)
+		}
+	}
+}
+
+func TestBotWebhook(t *testing.T) {
+	api := newFakeBotAPI()
+	defer api.Close()
+
+	bot := api.bot()
+
+	assert.NoError(t, bot.SetWebhook("https://example.com/api/telegram/webhook", "sekrit"))
+	assert.Equal(t, "https://example.com/api/telegram/webhook", api.webhook["url"])
+	assert.Equal(t, "sekrit", api.webhook["secret_token"])
+
+	assert.NoError(t, bot.DeleteWebhook())
+	assert.Nil(t, api.webhook)
+}
+
+func TestPoller(t *testing.T) {
+	api := newFakeBotAPI()
+	defer api.Close()
+
+	api.failures = 2
+	api.updates = []Update{
+		{UpdateID: 7, Message: &Message{MessageID: 1, Chat: Chat{ID: 42, Type: "private"}, Text: "/start"}},
+		{UpdateID: 8, Message: &Message{MessageID: 2, Chat: Chat{ID: 42, Type: "private"}, Text: "/tz Tokyo"}},
+	}
+
+	p := NewPoller(api.bot())
+	p.Timeout = 0
+	p.MinBackoff = time.Millisecond
+	p.MaxBackoff = 2 * time.Millisecond
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	var handled []string
+
+	go func() {
+		defer close(done)
+
+		p.Run(stop, func(update Update) {
+			handled = append(handled, update.Message.Text)
+
+			// Each update is only handed over once, so we're done after the
+			// second.
+			if len(handled) == 2 {
+				close(stop)
+			}
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for updates")
+	}
+
+	assert.Equal(t, []string{"/start", "/tz Tokyo"}, handled)
+}
+
+func TestCommandText(t *testing.T) {
+	cases := map[string]string{
+		"/start":             "start",
+		"/tz Tokyo":          "tz Tokyo",
+		"/tz@WhatDayBot Rio": "tz Rio",
+		"/stop@WhatDayBot":   "stop",
+		"  /help ":           "help",
+		"hello":              "hello",
+	}
+
+	for text, expected := range cases {
+		assert.Equal(t, expected, CommandText(text), text)
+	}
+}
@@ -0,0 +1,21 @@
+package telegram
+
+import "strings"
+
+// CommandText turns a bot command like "/tz@WhatDayBot Tokyo" in to the plain
+// "tz Tokyo" that we'd get by SMS. Anything else is left alone.
+func CommandText(text string) string {
+	text = strings.TrimSpace(text)
+
+	if !strings.HasPrefix(text, "/") {
+		return text
+	}
+
+	fields := strings.SplitN(text[1:], " ", 2)
+
+	if i := strings.IndexByte(fields[0], '@'); i >= 0 {
+		fields[0] = fields[0][:i]
+	}
+
+	return strings.Join(fields, " ")
+}
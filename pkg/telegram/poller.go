@@ -0,0 +1,86 @@
+package telegram
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	DefaultPollTimeout = 30 * time.Second
+	DefaultMinBackoff  = time.Second
+	DefaultMaxBackoff  = time.Minute
+)
+
+// Poller long polls for updates, for when there's nowhere for Telegram to send
+// them.
+type Poller struct {
+	Bot *Bot
+
+	// How long each request waits for an update to turn up.
+	Timeout time.Duration
+
+	// How long to wait after a failed request, which doubles each time that
+	// it fails again.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func NewPoller(bot *Bot) *Poller {
+	return &Poller{
+		Bot:        bot,
+		Timeout:    DefaultPollTimeout,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// Run hands each update to handle, in order, until stop is closed. It runs
+// forever if stop is nil.
+func (p *Poller) Run(stop <-chan struct{}, handle func(Update)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var offset int64
+	delay := p.MinBackoff
+
+	for ctx.Err() == nil {
+		updates, err := p.Bot.GetUpdates(ctx, offset, p.Timeout)
+
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			logger.WithError(err).WithField("retry_in", delay.String()).Warn("failed to get updates from Telegram")
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(delay):
+			}
+
+			if delay *= 2; delay > p.MaxBackoff {
+				delay = p.MaxBackoff
+			}
+
+			continue
+		}
+
+		delay = p.MinBackoff
+
+		for _, update := range updates {
+			handle(update)
+
+			// Asking for the updates after this one tells Telegram that we're
+			// done with it.
+			offset = update.UpdateID + 1
+		}
+	}
+}
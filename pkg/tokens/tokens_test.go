@@ -0,0 +1,48 @@
+package tokens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	signer := NewSigner([]byte("sekrit"))
+
+	token := signer.Sign(Claims{Purpose: "manage", Subject: "+14155551234", ExpiresAt: now.Add(time.Hour)})
+
+	claims, err := signer.Verify(token, "manage", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "+14155551234", claims.Subject)
+	assert.True(t, now.Add(time.Hour).Equal(claims.ExpiresAt))
+
+	_, err = signer.Verify(token, "manage", now.Add(time.Hour))
+	assert.Equal(t, ErrExpired, err)
+
+	_, err = signer.Verify(token, "something-else", now)
+	assert.Equal(t, ErrInvalid, err)
+
+	_, err = NewSigner([]byte("other")).Verify(token, "manage", now)
+	assert.Equal(t, ErrInvalid, err)
+
+	_, err = NewSigner(nil).Verify(token, "manage", now)
+	assert.Equal(t, ErrInvalid, err)
+
+	// Swapping in a different payload breaks the signature.
+	other := signer.Sign(Claims{Purpose: "manage", Subject: "+14155559999", ExpiresAt: now.Add(time.Hour)})
+	_, err = signer.Verify(other[:len(other)-43]+token[len(token)-43:], "manage", now)
+	assert.Equal(t, ErrInvalid, err)
+
+	for _, bogus := range []string{"", ".", "abc", "abc.def", token + ".x"} {
+		_, err = signer.Verify(bogus, "manage", now)
+		assert.Equal(t, ErrInvalid, err, bogus)
+	}
+}
+
+func TestNewNonce(t *testing.T) {
+	a, b := NewNonce(), NewNonce()
+	assert.Len(t, a, 22)
+	assert.NotEqual(t, a, b)
+}
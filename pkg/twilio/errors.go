@@ -0,0 +1,41 @@
+package twilio
+
+import "fmt"
+
+// Error is what Twilio tells us when it refuses to send a message.
+type Error struct {
+	Status  int    `json:"status"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("twilio: %s (code %d, status %d)", e.Message, e.Code, e.Status)
+}
+
+// These mean the number will never accept a message from us, so there's no
+// point in trying again tomorrow.
+var undeliverableCodes = map[int]bool{
+	21211: true, // Invalid 'To' phone number
+	21214: true, // 'To' phone number cannot be reached
+	21610: true, // Attempt to send to unsubscribed recipient
+	21612: true, // The 'To' phone number is not currently reachable
+	21614: true, // 'To' number is not a valid mobile number
+	63003: true, // Channel could not find To address, which isn't on WhatsApp
+}
+
+// Undeliverable indicates that the number can't get messages at all, as
+// opposed to something going wrong this time around.
+func (e *Error) Undeliverable() bool {
+	return undeliverableCodes[e.Code]
+}
+
+// IsUndeliverable indicates that err means the number can't get messages at
+// all.
+func IsUndeliverable(err error) bool {
+	if terr, ok := err.(*Error); ok {
+		return terr.Undeliverable()
+	}
+
+	return false
+}
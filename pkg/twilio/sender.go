@@ -5,29 +5,95 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/bradhe/what-day-is-it/pkg/logs"
+	"github.com/bradhe/what-day-is-it/pkg/models"
 )
 
 var logger = logs.WithPackage("twilio")
 
+const apiBaseURL = "https://api.twilio.com/2010-04-01"
+
+// SessionWindow is how long after someone messages us on WhatsApp that we can
+// send them whatever we like. Outside of it WhatsApp only takes templates
+// that it's approved ahead of time.
+const SessionWindow = 24 * time.Hour
+
 type Sender struct {
 	accountSID string
 	authToken  string
 	fromNumber string
+
+	// The number that WhatsApp messages come from, if it isn't the one that
+	// texts come from.
+	WhatsAppNumber string
+
+	// The content SID of the approved template that WhatsApp messages use
+	// outside of the session window. It has one variable, {{1}}, that gets
+	// the message. Without one every message is sent as it is, which only
+	// works in the Twilio sandbox.
+	ContentSID string
+
+	// SessionOpen reports whether a WhatsApp address has messaged us within
+	// SessionWindow. Without it every message goes out as a template.
+	SessionOpen func(to string) bool
+
+	baseURL string
 }
 
 type twilioResponse struct {
 	SID string `json:"sid"`
 }
 
+// Send texts body to a phone number, or messages it to a WhatsApp address.
+// WhatsApp messages outside of the session window go out using the template.
 func (s Sender) Send(to, body string) error {
-	urlStr := "https://api.twilio.com/2010-04-01/Accounts/" + s.accountSID + "/Messages.json"
+	if !models.IsWhatsAppAddress(to) {
+		return s.post("Messages", url.Values{"To": {to}, "From": {s.fromNumber}, "Body": {body}})
+	}
 
-	msgData := url.Values{}
-	msgData.Set("To", to)
-	msgData.Set("From", s.fromNumber)
-	msgData.Set("Body", body)
+	if s.ContentSID != "" && (s.SessionOpen == nil || !s.SessionOpen(to)) {
+		return s.SendTemplate(to, s.ContentSID, map[string]string{"1": body})
+	}
+
+	return s.post("Messages", url.Values{"To": {to}, "From": {s.whatsAppFrom()}, "Body": {body}})
+}
+
+// SendTemplate messages the approved template with contentSID to a WhatsApp
+// address, filling in its variables.
+func (s Sender) SendTemplate(to, contentSID string, variables map[string]string) error {
+	buf, err := json.Marshal(variables)
+
+	if err != nil {
+		return err
+	}
+
+	return s.post("Messages", url.Values{
+		"To":               {to},
+		"From":             {s.whatsAppFrom()},
+		"ContentSid":       {contentSID},
+		"ContentVariables": {string(buf)},
+	})
+}
+
+func (s Sender) whatsAppFrom() string {
+	if s.WhatsAppNumber != "" {
+		return models.WhatsAppAddress(s.WhatsAppNumber)
+	}
+
+	return models.WhatsAppAddress(s.fromNumber)
+}
+
+// Call phones to and has Twilio follow the instructions in twiml when they
+// pick up.
+func (s Sender) Call(to string, twiml []byte) error {
+	return s.post("Calls", url.Values{"To": {to}, "From": {s.fromNumber}, "Twiml": {string(twiml)}})
+}
+
+// post creates a resource, like a message or a call.
+func (s Sender) post(resource string, msgData url.Values) error {
+	urlStr := s.baseURL + "/Accounts/" + s.accountSID + "/" + resource + ".json"
 
 	client := &http.Client{}
 	req, _ := http.NewRequest("POST", urlStr, strings.NewReader(msgData.Encode()))
@@ -51,10 +117,17 @@ func (s Sender) Send(to, body string) error {
 		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
 			logger.WithError(err).Error("failed to parse Twilio response")
 		} else {
-			logger.Infof("message `%s` delivered", data.SID)
+			logger.Infof("%s `%s` delivered", strings.ToLower(strings.TrimSuffix(resource, "s")), data.SID)
 		}
 	} else {
+		terr := Error{Status: resp.StatusCode}
+
+		if err := json.NewDecoder(resp.Body).Decode(&terr); err != nil {
+			logger.WithError(err).Error("failed to parse Twilio error response")
+		}
+
 		logger.Errorf("invalid twilio response code: %s", resp.Status)
+		return &terr
 	}
 
 	return nil
@@ -62,8 +135,9 @@ func (s Sender) Send(to, body string) error {
 
 func NewSender(accountSID, authToken, fromNumber string) Sender {
 	return Sender{
-		accountSID,
-		authToken,
-		fromNumber,
+		accountSID: accountSID,
+		authToken:  authToken,
+		fromNumber: fromNumber,
+		baseURL:    apiBaseURL,
 	}
 }
@@ -0,0 +1,129 @@
+package twilio
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestSender returns a sender that talks to a fake Twilio, along with the
+// forms of the messages that it's been asked to send.
+func newTestSender(t *testing.T) (*Sender, *[]url.Values, func()) {
+	var sent []url.Values
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, []string{"/Accounts/AC123/Messages.json", "/Accounts/AC123/Calls.json"}, r.URL.Path)
+
+		if user, pass, ok := r.BasicAuth(); !ok || user != "AC123" || pass != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		r.ParseForm()
+		sent = append(sent, r.PostForm)
+
+		if r.PostForm.Get("To") == "whatsapp:+14155550000" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status": 400, "code": 63003, "message": "Channel could not find To address"}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"sid": "SM123"}`))
+	}))
+
+	sender := NewSender("AC123", "token", "+14155559999")
+	sender.baseURL = ts.URL
+
+	return &sender, &sent, ts.Close
+}
+
+func TestSend(t *testing.T) {
+	sender, sent, done := newTestSender(t)
+	defer done()
+
+	assert.NoError(t, sender.Send("+14155551234", "Today is Monday"))
+	assert.Equal(t, []url.Values{{
+		"To":   {"+14155551234"},
+		"From": {"+14155559999"},
+		"Body": {"Today is Monday"},
+	}}, *sent)
+}
+
+func TestSendWhatsApp(t *testing.T) {
+	open := map[string]bool{"whatsapp:+14155551234": true}
+
+	cases := []struct {
+		to         string
+		contentSID string
+		expected   url.Values
+	}{
+		// Inside the session window, anything goes.
+		{"whatsapp:+14155551234", "HX123", url.Values{
+			"To":   {"whatsapp:+14155551234"},
+			"From": {"whatsapp:+14155559999"},
+			"Body": {"Today is Monday"},
+		}},
+		{"whatsapp:+14155554321", "HX123", url.Values{
+			"To":               {"whatsapp:+14155554321"},
+			"From":             {"whatsapp:+14155559999"},
+			"ContentSid":       {"HX123"},
+			"ContentVariables": {`{"1":"Today is Monday"}`},
+		}},
+		// No template means we're in the sandbox.
+		{"whatsapp:+14155554321", "", url.Values{
+			"To":   {"whatsapp:+14155554321"},
+			"From": {"whatsapp:+14155559999"},
+			"Body": {"Today is Monday"},
+		}},
+	}
+
+	for _, c := range cases {
+		sender, sent, done := newTestSender(t)
+		sender.ContentSID = c.contentSID
+		sender.SessionOpen = func(to string) bool { return open[to] }
+
+		assert.NoError(t, sender.Send(c.to, "Today is Monday"), c.to)
+		assert.Equal(t, []url.Values{c.expected}, *sent, c.to)
+
+		done()
+	}
+}
+
+func TestSendWhatsAppFromAnotherNumber(t *testing.T) {
+	sender, sent, done := newTestSender(t)
+	defer done()
+
+	sender.WhatsAppNumber = "+14155558888"
+
+	assert.NoError(t, sender.SendTemplate("whatsapp:+14155551234", "HX123", map[string]string{"1": "Monday"}))
+
+	if assert.Len(t, *sent, 1) {
+		assert.Equal(t, "whatsapp:+14155558888", (*sent)[0].Get("From"))
+	}
+}
+
+func TestSendWhatsAppUndeliverable(t *testing.T) {
+	sender, _, done := newTestSender(t)
+	defer done()
+
+	err := sender.Send("whatsapp:+14155550000", "Today is Monday")
+	assert.Error(t, err)
+	assert.True(t, IsUndeliverable(err))
+}
+
+func TestCaller(t *testing.T) {
+	sender, sent, done := newTestSender(t)
+	defer done()
+
+	assert.NoError(t, NewCaller(*sender).Send("+14155551234", "Good morning, today is Monday & all is well."))
+
+	if assert.Len(t, *sent, 1) {
+		assert.Equal(t, "+14155551234", (*sent)[0].Get("To"))
+		assert.Equal(t, "+14155559999", (*sent)[0].Get("From"))
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?><Response><Say>Good morning, today is Monday &amp; all is well.</Say><Pause length="1"/><Say>Good morning, today is Monday &amp; all is well.</Say></Response>`, (*sent)[0].Get("Twiml"))
+	}
+}
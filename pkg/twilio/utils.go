@@ -1,7 +1,33 @@
 package twilio
 
-import "fmt"
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+)
+
+const twimlHeader = `<?xml version="1.0" encoding="UTF-8"?>`
+
+func escape(text string) string {
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(text))
+	return buf.String()
+}
 
 func TwiMLResponse(body string) []byte {
-	return []byte(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?><Response><Message><Body>%s</Body></Message></Response>`, body))
+	return []byte(fmt.Sprintf(`%s<Response><Message><Body>%s</Body></Message></Response>`, twimlHeader, escape(body)))
+}
+
+// TwiMLSay reads text out loud and hangs up. It's read twice since there's no
+// scrolling back on a phone call.
+func TwiMLSay(text string) []byte {
+	return []byte(fmt.Sprintf(`%s<Response><Say>%s</Say><Pause length="1"/><Say>%s</Say></Response>`, twimlHeader, escape(text), escape(text)))
+}
+
+// TwiMLGather reads text and then prompt out loud, and posts the key that the
+// caller presses to action as Digits. If they don't press anything it reads
+// goodbye and hangs up.
+func TwiMLGather(text, prompt, action, goodbye string) []byte {
+	return []byte(fmt.Sprintf(`%s<Response><Say>%s</Say><Gather numDigits="1" action="%s" method="POST"><Say>%s</Say></Gather><Say>%s</Say></Response>`,
+		twimlHeader, escape(text), escape(action), escape(prompt), escape(goodbye)))
 }
@@ -0,0 +1,50 @@
+package twilio
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"sort"
+)
+
+var ErrInvalidSignature = errors.New("twilio: invalid request signature")
+
+// Sign returns the signature Twilio sends in the X-Twilio-Signature header
+// when it posts params to url.
+func Sign(authToken, url string, params url.Values) string {
+	keys := make([]string, 0, len(params))
+
+	for k := range params {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(url))
+
+	for _, k := range keys {
+		for _, v := range params[k] {
+			mac.Write([]byte(k + v))
+		}
+	}
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyRequest checks that params were posted to url by Twilio, using the
+// signature from the X-Twilio-Signature header. The url has to be the one
+// Twilio was configured with, query string and all.
+func VerifyRequest(authToken, url, signature string, params url.Values) error {
+	if authToken == "" {
+		return ErrInvalidSignature
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(Sign(authToken, url, params))) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package twilio
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testParams = url.Values{
+	"CallSid": {"CA1234567890ABCDE"},
+	"Caller":  {"+12349013030"},
+	"Digits":  {"1234"},
+	"From":    {"+12349013030"},
+	"To":      {"+18005551212"},
+}
+
+const testURL = "https://mycompany.com/myapp.php?foo=1&bar=2"
+
+func TestSign(t *testing.T) {
+	// The example from Twilio's documentation.
+	assert.Equal(t, "0/KCTR6DLpKmkAf8muzZqo1nDgQ=", Sign("12345", testURL, testParams))
+}
+
+func TestVerifyRequest(t *testing.T) {
+	signature := Sign("12345", testURL, testParams)
+
+	assert.NoError(t, VerifyRequest("12345", testURL, signature, testParams))
+
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest("nope", testURL, signature, testParams))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest("", testURL, signature, testParams))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest("12345", "https://mycompany.com/myapp.php", signature, testParams))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest("12345", testURL, signature, url.Values{"Digits": {"1"}}))
+}
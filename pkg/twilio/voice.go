@@ -0,0 +1,14 @@
+package twilio
+
+// Caller delivers messages by phoning the subscriber and reading them out.
+type Caller struct {
+	Sender
+}
+
+func NewCaller(sender Sender) Caller {
+	return Caller{sender}
+}
+
+func (c Caller) Send(to, body string) error {
+	return c.Call(to, TwiMLSay(body))
+}
@@ -0,0 +1,347 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/clock"
+	"github.com/bradhe/what-day-is-it/pkg/hooks"
+	"github.com/bradhe/what-day-is-it/pkg/logs"
+)
+
+var logger = logs.WithPackage("webhook")
+
+const (
+	DefaultMaxAttempts = 4
+	DefaultMinBackoff  = time.Second
+	DefaultMaxBackoff  = 30 * time.Second
+)
+
+// Subscription is what the sender needs to know about whoever's at a URL.
+type Subscription struct {
+	ID       string
+	Secret   string
+	Timezone string
+}
+
+// Payload is the JSON body of each delivery.
+type Payload struct {
+	// The same for every attempt at a delivery, so receivers can tell
+	// retries apart from new deliveries.
+	ID string `json:"id"`
+
+	Subscription string `json:"subscription"`
+
+	// The subscription's timezone and the day that it is there, like
+	// 2026-10-19 and Monday.
+	Timezone string `json:"timezone"`
+	Date     string `json:"date"`
+	Day      string `json:"day"`
+
+	// What we'd say by text, like "Today is Monday".
+	Text string `json:"text"`
+}
+
+// Attempt is one try at a delivery, for the subscription's delivery history.
+type Attempt struct {
+	DeliveryID   string
+	Subscription string
+	Attempt      int
+	At           time.Time
+	Duration     time.Duration
+
+	// The receiver's response, which is 0 if we never got one.
+	StatusCode int
+
+	// What went wrong, if anything.
+	Error string
+}
+
+// Succeeded indicates that the receiver took the delivery.
+func (a Attempt) Succeeded() bool {
+	return a.Error == ""
+}
+
+// Error is a receiver not taking a delivery.
+type Error struct {
+	Status int
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("webhook: receiver responded with %d %s", e.Status, http.StatusText(e.Status))
+}
+
+// Undeliverable indicates that the receiver said it's gone for good.
+func (e *Error) Undeliverable() bool {
+	return e.Status == http.StatusGone
+}
+
+// ErrPrivateAddress is a webhook URL that resolves to somewhere on a private
+// network, which we don't connect to.
+var ErrPrivateAddress = errors.New("webhook: refusing to connect to a private address")
+
+// retryable indicates that trying again later might work, which it won't if
+// the receiver turned the delivery down or lives somewhere we won't go.
+func retryable(err error) bool {
+	if errors.Is(err, ErrPrivateAddress) {
+		return false
+	}
+
+	if werr, ok := err.(*Error); ok {
+		switch werr.Status {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		default:
+			return werr.Status >= 500
+		}
+	}
+
+	return true
+}
+
+// IsURL indicates that str is somewhere we'll deliver to, which is anywhere on
+// https that isn't obviously on a private network.
+func IsURL(str string) bool {
+	u, err := url.Parse(str)
+
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return false
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return false
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return isPublic(ip)
+	}
+
+	return true
+}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast() || isPrivate(ip))
+}
+
+var privateNetworks = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"}
+
+func isPrivate(ip net.IP) bool {
+	for _, cidr := range privateNetworks {
+		if _, network, _ := net.ParseCIDR(cidr); network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkAddress runs before every connection, once the host name has been
+// resolved, so that names which IsURL can't see through don't get us on to a
+// private network either.
+func checkAddress(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+		return ErrPrivateAddress
+	}
+
+	return nil
+}
+
+// newClient makes a client that only connects to public addresses and doesn't
+// follow redirects, which could point anywhere.
+func newClient() *http.Client {
+	dialer := &net.Dialer{Timeout: hooks.DefaultTimeout, Control: checkAddress}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   hooks.DefaultTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Sender posts signed payloads to webhook URLs, trying again with backoff
+// when it doesn't work. Only the first attempt holds up Send; the rest are
+// made in the background.
+type Sender struct {
+	hooks.Sender
+
+	// Lookup finds the subscription at a URL.
+	Lookup func(url string) (Subscription, error)
+
+	// Record is told about every attempt, if it's set.
+	Record func(Attempt)
+
+	MaxAttempts int
+
+	// How long to wait after a failed attempt, which doubles each time that
+	// it fails again.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	sleep func(time.Duration)
+
+	retries sync.WaitGroup
+}
+
+func NewSender(lookup func(string) (Subscription, error)) *Sender {
+	return &Sender{
+		Sender:      hooks.Sender{Client: newClient(), Refused: refused},
+		Lookup:      lookup,
+		MaxAttempts: DefaultMaxAttempts,
+		MinBackoff:  DefaultMinBackoff,
+		MaxBackoff:  DefaultMaxBackoff,
+		sleep:       time.Sleep,
+	}
+}
+
+func newDeliveryID() string {
+	buf := make([]byte, 16)
+
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(buf)
+}
+
+// NewPayload describes body for the subscription.
+func NewPayload(sub Subscription, body string) Payload {
+	now := clock.Clock().In(clock.MustLoadLocation(sub.Timezone))
+
+	return Payload{
+		ID:           newDeliveryID(),
+		Subscription: sub.ID,
+		Timezone:     sub.Timezone,
+		Date:         now.Format("2006-01-02"),
+		Day:          now.Weekday().String(),
+		Text:         body,
+	}
+}
+
+func refused(status int, _ []byte) error {
+	return &Error{status}
+}
+
+// post makes one attempt at delivering body.
+func (s *Sender) post(to, secret, id string, body []byte) (int, error) {
+	timestamp := strconv.FormatInt(clock.Clock().Unix(), 10)
+
+	header := http.Header{}
+	header.Set("User-Agent", "what-day-is-it")
+	header.Set(HeaderID, id)
+	header.Set(HeaderTimestamp, timestamp)
+	header.Set(HeaderSignature, Sign(secret, timestamp, body))
+
+	return s.Post(to, header, body)
+}
+
+func (s *Sender) backoff(attempt int) time.Duration {
+	delay := s.MinBackoff
+
+	for i := 1; i < attempt && delay < s.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if delay > s.MaxBackoff {
+		delay = s.MaxBackoff
+	}
+
+	return delay
+}
+
+// Send delivers body to the webhook at to. If the first attempt fails in a
+// way that trying again might fix, the rest are made in the background and
+// Send doesn't wait for them; Record hears about every one.
+func (s *Sender) Send(to, body string) error {
+	sub, err := s.Lookup(to)
+
+	if err != nil {
+		logger.WithError(err).Error("failed to find webhook subscription")
+		return err
+	}
+
+	payload := NewPayload(sub, body)
+	buf, _ := json.Marshal(payload)
+
+	if err := s.attempt(to, sub, payload.ID, buf, 1); err == nil || s.MaxAttempts <= 1 || !retryable(err) {
+		return err
+	}
+
+	s.retries.Add(1)
+
+	go func() {
+		defer s.retries.Done()
+
+		for attempt := 2; ; attempt++ {
+			s.sleep(s.backoff(attempt - 1))
+
+			if err := s.attempt(to, sub, payload.ID, buf, attempt); err == nil || attempt >= s.MaxAttempts || !retryable(err) {
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// attempt makes the attempt'th try at a delivery and records it.
+func (s *Sender) attempt(to string, sub Subscription, id string, buf []byte, attempt int) error {
+	start := time.Now()
+	status, err := s.post(to, sub.Secret, id, buf)
+
+	if s.Record != nil {
+		a := Attempt{
+			DeliveryID:   id,
+			Subscription: sub.ID,
+			Attempt:      attempt,
+			At:           *clock.Clock(),
+			Duration:     time.Since(start),
+			StatusCode:   status,
+		}
+
+		if err != nil {
+			a.Error = err.Error()
+		}
+
+		s.Record(a)
+	}
+
+	if err == nil {
+		logger.WithField("attempt", attempt).Info("webhook delivered")
+	} else if attempt >= s.MaxAttempts || !retryable(err) {
+		logger.WithError(err).WithField("attempt", attempt).Error("failed to deliver webhook")
+	} else {
+		logger.WithError(err).WithField("retry_in", s.backoff(attempt).String()).Warn("webhook delivery failed")
+	}
+
+	return err
+}
+
+// Wait blocks until every delivery that's being retried is done.
+func (s *Sender) Wait() {
+	s.retries.Wait()
+}
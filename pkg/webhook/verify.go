@@ -0,0 +1,36 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/bradhe/what-day-is-it/pkg/hooks"
+)
+
+// MaxRequestAge is how old a delivery can be before receivers should turn it
+// away, so that anybody that gets hold of one can't replay it later.
+const MaxRequestAge = 5 * time.Minute
+
+var (
+	ErrInvalidSignature = hooks.ErrInvalidSignature
+	ErrStaleRequest     = hooks.ErrStaleRequest
+)
+
+// Headers that go along with each delivery.
+const (
+	HeaderID        = "X-Webhook-Id"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the signature for body sent at timestamp, which is v1= and the
+// hex HMAC-SHA256 of "v1:<timestamp>:<body>" keyed with the subscription's
+// secret.
+func Sign(secret, timestamp string, body []byte) string {
+	return hooks.Sign([]byte(secret), "v1", timestamp, body)
+}
+
+// VerifyRequest checks a delivery's signature and makes sure that it's
+// recent, for receivers written in Go.
+func VerifyRequest(secret, timestamp, signature string, body []byte, now time.Time) error {
+	return hooks.Verify([]byte(secret), "v1", timestamp, signature, body, now, MaxRequestAge)
+}
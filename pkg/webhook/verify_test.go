@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"day": "Monday"}`)
+	signature := Sign("sekrit", timestamp, body)
+
+	assert.Regexp(t, `^v1=[0-9a-f]{64}$`, signature)
+
+	assert.NoError(t, VerifyRequest("sekrit", timestamp, signature, body, now))
+	assert.NoError(t, VerifyRequest("sekrit", timestamp, signature, body, now.Add(MaxRequestAge)))
+
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest("nope", timestamp, signature, body, now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest("", timestamp, signature, body, now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest("sekrit", timestamp, signature, []byte(`{"day": "Friday"}`), now))
+	assert.Equal(t, ErrInvalidSignature, VerifyRequest("sekrit", timestamp+"0", signature, body, now))
+	assert.Equal(t, ErrStaleRequest, VerifyRequest("sekrit", timestamp, signature, body, now.Add(MaxRequestAge+time.Second)))
+}